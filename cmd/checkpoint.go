@@ -0,0 +1,117 @@
+// Copyright 2018 The Terraformer Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+
+	"github.com/GoogleCloudPlatform/terraformer/terraformutils"
+)
+
+const (
+	// CheckpointListed holds the resources of a service after listing and initial cleanup
+	CheckpointListed = "listed"
+	// CheckpointRefreshed holds the resources of a service after refresh
+	CheckpointRefreshed = "refreshed"
+)
+
+// checkpointPath mirrors the output path of the service, so regions and projects imported by
+// separate Import calls never share a checkpoint
+func checkpointPath(providerName string, options ImportOptions, service, stage string) string {
+	servicePath := Path(options.PathPattern, providerName, service, options.PathOutput)
+	return filepath.Join(options.CheckpointDir, servicePath, service+"."+stage+".json")
+}
+
+// checkpointOptionsHash hashes the options changing which resources are listed and how, a checkpoint
+// saved with other values is not resumed
+func checkpointOptionsHash(options ImportOptions) string {
+	data, err := json.Marshal(struct {
+		Resources           []string
+		Excludes            []string
+		Filter              []string
+		Regions             []string
+		Projects            []string
+		Zone                string
+		ResourceGroup       string
+		ExcludeManagedState []string
+		Naming              string
+	}{
+		Resources:           options.Resources,
+		Excludes:            options.Excludes,
+		Filter:              options.Filter,
+		Regions:             options.Regions,
+		Projects:            options.Projects,
+		Zone:                options.Zone,
+		ResourceGroup:       options.ResourceGroup,
+		ExcludeManagedState: options.ExcludeManagedState,
+		Naming:              options.Naming,
+	})
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func saveCheckpoint(providerName string, options ImportOptions, args []string, service, stage string, resources []terraformutils.Resource) {
+	if options.CheckpointDir == "" {
+		return
+	}
+	plan := &ImportPlan{
+		Provider:         providerName,
+		Options:          options,
+		Args:             args,
+		ImportedResource: map[string][]terraformutils.Resource{service: resources},
+		OptionsHash:      checkpointOptionsHash(options),
+	}
+	path := checkpointPath(providerName, options, service, stage)
+	if err := ExportPlanFile(plan, filepath.Dir(path), filepath.Base(path)); err != nil {
+		log.Printf("failed to save %s checkpoint for service %s: %s", stage, service, err)
+	}
+}
+
+// loadCheckpoint returns the furthest checkpoint stage of a service saved by a run with the same arguments
+// and options, the service is imported again otherwise
+func loadCheckpoint(providerName string, options ImportOptions, args []string, service string) ([]terraformutils.Resource, string, bool) {
+	if options.CheckpointDir == "" || !options.Resume {
+		return nil, "", false
+	}
+	for _, stage := range []string{CheckpointRefreshed, CheckpointListed} {
+		path := checkpointPath(providerName, options, service, stage)
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+		plan, err := LoadPlanfile(path)
+		if err != nil {
+			log.Printf("ignoring %s checkpoint for service %s: %s", stage, service, err)
+			continue
+		}
+		if plan.Provider != providerName || !reflect.DeepEqual(plan.Args, args) {
+			log.Printf("ignoring %s checkpoint for service %s: saved for other arguments", stage, service)
+			continue
+		}
+		if plan.OptionsHash != checkpointOptionsHash(options) {
+			log.Printf("ignoring %s checkpoint for service %s: saved with other resources, filters or regions", stage, service)
+			continue
+		}
+		return plan.ImportedResource[service], stage, true
+	}
+	return nil, "", false
+}
@@ -0,0 +1,119 @@
+// Copyright 2018 The Terraformer Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package cmd
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/GoogleCloudPlatform/terraformer/terraformutils"
+	"github.com/GoogleCloudPlatform/terraformer/terraformutils/providerwrapper"
+)
+
+var (
+	// runContext is cancelled on SIGINT or SIGTERM
+	runContext = context.Background()
+	// runStart is the start of the run, --timeout covers all Import calls from it
+	runStart = time.Now()
+)
+
+// cancelOnSignal cancels runContext on the first SIGINT or SIGTERM, the next one kills terraformer
+func cancelOnSignal() func() {
+	ctx, cancel := context.WithCancel(context.Background())
+	runContext = ctx
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		select {
+		case sig := <-signals:
+			signal.Stop(signals)
+			log.Printf("received %s, saving finished services, interrupt again to quit immediately", sig)
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return func() {
+		signal.Stop(signals)
+		cancel()
+	}
+}
+
+// importContext returns the context of an Import call, ending with the run or at the --timeout deadline
+func importContext(options ImportOptions) (context.Context, context.CancelFunc) {
+	if options.Timeout > 0 {
+		return context.WithDeadline(runContext, runStart.Add(options.Timeout))
+	}
+	return context.WithCancel(runContext)
+}
+
+// killOnDone kills the provider plugin as soon as the context is done, ending refreshes blocked on it.
+// The returned function stops watching.
+func killOnDone(ctx context.Context, providerWrapper *providerwrapper.ProviderWrapper) func() {
+	finished := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			log.Println("stopping provider plugin:", ctx.Err())
+			providerWrapper.Kill()
+		case <-finished:
+		}
+	}()
+	return func() {
+		close(finished)
+	}
+}
+
+// listingStopTimeout is how long a listing is still waited for once its context is done
+var listingStopTimeout = 10 * time.Second
+
+// runWithContext runs f, which sees the context through Service.GetContext. Once the context is done, services
+// passing it to their API calls stop within listingStopTimeout and are waited for, so nothing writes to them after
+// the caller moves on. A listing still running then uses an SDK without cancellation: it is left in the background
+// and abandon is called to discard whatever it writes to, see detachService. The context error is returned whenever
+// the context is done, even when f returned first, its listing may be partial.
+func runWithContext(ctx context.Context, f func() error, abandon func()) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- f()
+	}()
+	select {
+	case err := <-done:
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return err
+	case <-ctx.Done():
+	}
+	timer := time.NewTimer(listingStopTimeout)
+	defer timer.Stop()
+	select {
+	case <-done:
+	case <-timer.C:
+		log.Println("abandoning listing not stopping:", ctx.Err())
+		abandon()
+	}
+	return ctx.Err()
+}
+
+// detachService gives the provider a fresh instance of the service, leaving the one still written to
+// by an abandoned listing unreachable from the provider.
+func detachService(service string, provider terraformutils.ProviderGenerator, verbose bool) {
+	if err := provider.InitService(service, verbose); err != nil {
+		log.Printf("%s error detaching %s, err: %s\n", provider.GetName(), service, err)
+	}
+}
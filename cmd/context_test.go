@@ -0,0 +1,69 @@
+// Copyright 2018 The Terraformer Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRunWithContext(t *testing.T) {
+	defer func(timeout time.Duration) {
+		listingStopTimeout = timeout
+	}(listingStopTimeout)
+	listingStopTimeout = time.Second
+
+	testCases := map[string]struct {
+		listing   time.Duration
+		abandoned bool
+	}{
+		// a listing ending with its context, e.g. on the deadline, may be partial
+		"returns with the context": {0, false},
+		// a listing ignoring the context but ending soon after is waited for
+		"stops late":  {50 * time.Millisecond, false},
+		"never stops": {time.Hour, true},
+	}
+	for name, testCase := range testCases {
+		ctx, cancel := context.WithCancel(context.Background())
+		listed := make(chan struct{})
+		abandoned := false
+		start := time.Now()
+		err := runWithContext(ctx, func() error {
+			cancel()
+			time.Sleep(testCase.listing)
+			close(listed)
+			return nil
+		}, func() {
+			abandoned = true
+		})
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("%s: expected the cancellation, got %v", name, err)
+		}
+		if abandoned != testCase.abandoned {
+			t.Errorf("%s: abandoned %t, expected %t", name, abandoned, testCase.abandoned)
+		}
+		if !testCase.abandoned {
+			select {
+			case <-listed:
+			default:
+				t.Errorf("%s: expected the listing to be waited for", name)
+			}
+		} else if time.Since(start) > 2*listingStopTimeout {
+			t.Errorf("%s: expected the listing to be abandoned after %s", name, listingStopTimeout)
+		}
+	}
+}
@@ -0,0 +1,223 @@
+// Copyright 2018 The Terraformer Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/GoogleCloudPlatform/terraformer/terraformutils"
+	"github.com/hashicorp/terraform/providers"
+	"github.com/spf13/cobra"
+)
+
+const (
+	DriftFormatTable = "table"
+	DriftFormatJSON  = "json"
+	// DriftExitCode is returned when live infrastructure differs from the generated state
+	DriftExitCode = 2
+)
+
+// driftCollector gathers the drift of every Import call, providers import each region separately
+var driftCollector = struct {
+	sync.Mutex
+	report terraformutils.DriftReport
+	format string
+	out    string
+}{
+	report: terraformutils.DriftReport{
+		New:     []terraformutils.DriftedResource{},
+		Deleted: []terraformutils.DriftedResource{},
+		Changed: []terraformutils.DriftedResource{},
+	},
+}
+
+func newDriftCmd() *cobra.Command {
+	options := ImportOptions{
+		Drift: true,
+	}
+	cmd := &cobra.Command{
+		Use:           "drift",
+		Short:         "Compare generated Terraform state with current state",
+		Long:          "Compare the terraform.tfstate files of a generated directory with current state, exits with code 2 when drift is found",
+		SilenceUsage:  true,
+		SilenceErrors: false,
+		PersistentPostRunE: func(cmd *cobra.Command, args []string) error {
+			driftErr := printDriftReport()
+			// drift of services that failed to list is unknown, their failure takes precedence
+			if err := printFailuresSummary(cmd, args); err != nil {
+				return err
+			}
+			return driftErr
+		},
+	}
+
+	for _, subcommand := range providerImporterSubcommands() {
+		providerCommand := subcommand(options)
+		_ = providerCommand.MarkPersistentFlagRequired("resources")
+		cmd.AddCommand(providerCommand)
+	}
+	return cmd
+}
+
+// setDriftOutput validates the options of a drift run and keeps where its report is printed.
+// Only a local state per service directory can be compared.
+func setDriftOutput(options ImportOptions) error {
+	switch {
+	case options.ImportMode == ImportModeBlocks:
+		return fmt.Errorf("drift compares generated state, --import-mode=%s writes none", ImportModeBlocks)
+	case options.Layout == LayoutModules:
+		return fmt.Errorf("drift compares the state of each service, --layout=%s writes a single one", LayoutModules)
+	case options.State != "" && options.State != DefaultState:
+		return fmt.Errorf("drift compares local state, --state=%s is not supported", options.State)
+	case options.DriftFormat != "" && options.DriftFormat != DriftFormatTable && options.DriftFormat != DriftFormatJSON:
+		return fmt.Errorf("unsupported drift format: %s", options.DriftFormat)
+	}
+	driftCollector.Lock()
+	defer driftCollector.Unlock()
+	driftCollector.format = options.DriftFormat
+	driftCollector.out = options.DriftOut
+	return nil
+}
+
+// detectDrift compares refreshed resources with the state saved in the output path of each service
+func detectDrift(providerMapping *terraformutils.ProvidersMapping, options ImportOptions, schema *providers.GetSchemaResponse) error {
+	var secretPatterns []*regexp.Regexp
+	if options.RedactSecrets && options.SecretsState == terraformutils.SecretsStateStrip {
+		patterns, err := terraformutils.CompileSecretPatterns(options.SecretPatterns)
+		if err != nil {
+			return err
+		}
+		secretPatterns = patterns
+	} else {
+		schema = nil
+	}
+	provider := providerMapping.GetBaseProvider()
+	resourcesByService := providerMapping.GetResourcesByService()
+	if !strings.Contains(options.PathPattern, "{service}") {
+		var compactedResources []terraformutils.Resource
+		for _, resources := range resourcesByService {
+			compactedResources = append(compactedResources, resources...)
+		}
+		return detectPathDrift(provider.GetName(), "", options, compactedResources, schema, secretPatterns)
+	}
+	for serviceName, resources := range resourcesByService {
+		if err := detectPathDrift(provider.GetName(), serviceName, options, resources, schema, secretPatterns); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// detectPathDrift compares the state of one directory, secrets are emptied on both sides when the state
+// was written with --secrets-state=strip
+func detectPathDrift(providerName, serviceName string, options ImportOptions, live []terraformutils.Resource, schema *providers.GetSchemaResponse, secretPatterns []*regexp.Regexp) error {
+	path := Path(options.PathPattern, providerName, serviceName, options.PathOutput)
+	statePath := filepath.Join(path, "terraform.tfstate")
+	data, err := ioutil.ReadFile(statePath)
+	if err != nil {
+		return fmt.Errorf("failed to read generated state, run import first: %s", err)
+	}
+	stored, err := terraformutils.ReadTfStateResources(data)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %s", statePath, err)
+	}
+	// apply the same filters the live resources went through
+	service := &terraformutils.Service{Name: serviceName}
+	service.ParseFilters(options.Filter)
+	service.SetResources(stored)
+	service.InitialCleanup()
+	service.PostRefreshCleanup()
+
+	if schema != nil {
+		terraformutils.StripSecrets(service.GetResources(), schema, secretPatterns)
+		terraformutils.StripSecrets(live, schema, secretPatterns)
+	}
+	report := terraformutils.DetectDrift(service.GetResources(), live)
+	log.Printf("%s drift in %s: %d new, %d deleted, %d changed", providerName, path, len(report.New), len(report.Deleted), len(report.Changed))
+	driftCollector.Lock()
+	driftCollector.report.Merge(report)
+	driftCollector.Unlock()
+	return nil
+}
+
+func printDriftReport() error {
+	driftCollector.Lock()
+	defer driftCollector.Unlock()
+	report, format, out := driftCollector.report, driftCollector.format, driftCollector.out
+
+	var w io.Writer = os.Stdout
+	if out != "" {
+		f, err := os.Create(out)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	switch format {
+	case DriftFormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			return err
+		}
+	case DriftFormatTable, "":
+		if err := printDriftTable(w, report); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unsupported drift format: %s", format)
+	}
+	if report.HasDrift() {
+		return &ExitError{
+			Code: DriftExitCode,
+			Err:  fmt.Errorf("drift detected: %d new, %d deleted, %d changed", len(report.New), len(report.Deleted), len(report.Changed)),
+		}
+	}
+	return nil
+}
+
+func printDriftTable(w io.Writer, report terraformutils.DriftReport) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "DRIFT\tRESOURCE\tID\tATTRIBUTE\tBEFORE\tAFTER")
+	for _, r := range report.New {
+		fmt.Fprintf(tw, "new\t%s\t%s\t\t\t\n", r.Address, r.ID)
+	}
+	for _, r := range report.Deleted {
+		fmt.Fprintf(tw, "deleted\t%s\t%s\t\t\t\n", r.Address, r.ID)
+	}
+	for _, r := range report.Changed {
+		for _, change := range r.Changes {
+			fmt.Fprintf(tw, "changed\t%s\t%s\t%s\t%s\t%s\n", r.Address, r.ID, change.Attribute, driftValue(change.Before), driftValue(change.After))
+		}
+	}
+	return tw.Flush()
+}
+
+func driftValue(value *string) string {
+	if value == nil {
+		return "(none)"
+	}
+	return fmt.Sprintf("%q", *value)
+}
@@ -0,0 +1,69 @@
+// Copyright 2018 The Terraformer Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/GoogleCloudPlatform/terraformer/terraformutils"
+	"github.com/spf13/cobra"
+)
+
+func newGraphCmd() *cobra.Command {
+	var format, level, out string
+	cmd := &cobra.Command{
+		Use:   "graph <planfile>",
+		Short: "Print the dependency graph of planned resources",
+		Long:  "Print the resource or service dependency graph of the resources of a plan file in DOT, JSON or Mermaid, with orphans and cycles between services flagged",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			plan, err := LoadPlanfile(args[0])
+			if err != nil {
+				return err
+			}
+			providerGen, ok := providerGenerators()[plan.Provider]
+			if !ok {
+				return fmt.Errorf("unsupported provider: %s", plan.Provider)
+			}
+			provider := providerGen()
+			graph := planGraph(provider, plan)
+			if out != "" {
+				return graph.Write(out, format, level)
+			}
+			if format == "" {
+				format = terraformutils.GraphFormatDOT
+			}
+			return graph.Render(cmd.OutOrStdout(), format, level)
+		},
+	}
+	cmd.Flags().StringVar(&format, "format", "", "dot, json or mermaid (default from the --out extension, else dot)")
+	cmd.Flags().StringVar(&level, "level", terraformutils.GraphLevelResource, "resource or service, nodes of dot and mermaid graphs")
+	cmd.Flags().StringVar(&out, "out", "", "file to write the graph to, defaults to stdout")
+	return cmd
+}
+
+// planGraph builds the graph import would build from the plan, references are inferred when the plan connects services
+func planGraph(provider terraformutils.ProviderGenerator, plan *ImportPlan) *terraformutils.Graph {
+	importedResource := plan.ImportedResource
+	connections := provider.GetResourceConnections()
+	connected := terraformutils.ConnectionReferences(importedResource, connections)
+	var inferred []terraformutils.Reference
+	if plan.Options.Connect && plan.Options.InferReferences {
+		importedResource = terraformutils.ConnectServices(importedResource, true, connections)
+		inferred = terraformutils.InferReferences(importedResource, connections, terraformutils.ServiceLinkFunc(true)).References
+	}
+	graph := terraformutils.NewGraph()
+	graph.Add(provider.GetName(), "", importedResource, connected, inferred)
+	return graph
+}
@@ -14,18 +14,21 @@
 package cmd
 
 import (
+	"context"
+	"encoding/csv"
 	"fmt"
-	"io/ioutil"
 	"log"
-	"os"
+	"regexp"
 	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/GoogleCloudPlatform/terraformer/terraformutils/terraformerstring"
 
 	"github.com/GoogleCloudPlatform/terraformer/terraformutils/providerwrapper"
 
+	"github.com/hashicorp/terraform/providers"
 	"github.com/spf13/pflag"
 
 	"github.com/GoogleCloudPlatform/terraformer/terraformutils"
@@ -35,39 +38,84 @@ import (
 )
 
 type ImportOptions struct {
-	Resources     []string
-	Excludes      []string
-	PathPattern   string
-	PathOutput    string
-	State         string
-	Bucket        string
-	Profile       string
-	Verbose       bool
-	Zone          string
-	Regions       []string
-	Projects      []string
-	ResourceGroup string
-	Connect       bool
-	Compact       bool
-	Filter        []string
-	Plan          bool `json:"-"`
-	Output        string
-	RetryCount    int
-	RetrySleepMs  int
+	Resources           []string
+	Excludes            []string
+	PathPattern         string
+	PathOutput          string
+	State               string
+	Bucket              string
+	BackendConfig       []string
+	RemoteState         string
+	RemoteStateBucket   string
+	RemoteStateConfig   []string
+	Profile             string
+	Verbose             bool
+	Zone                string
+	Regions             []string
+	Projects            []string
+	ResourceGroup       string
+	Connect             bool
+	InferReferences     bool
+	Compact             bool
+	Filter              []string
+	FilterFile          string
+	Plan                bool   `json:"-"`
+	Drift               bool   `json:"-"`
+	DriftFormat         string `json:"-"`
+	DriftOut            string `json:"-"`
+	Output              string
+	RetryCount          int
+	RetrySleepMs        int
+	ImportMode          string
+	CheckpointDir       string
+	Resume              bool
+	ExcludeManagedState []string
+	ExtractVariables    bool
+	VariablesThreshold  int
+	RedactSecrets       bool
+	SecretPatterns      []string
+	SecretsState        string
+	Layout              string
+	Naming              string
+	Parallelism         int
+	RateLimit           float64
+	PluginProcesses     int
+	ProviderPath        string
+	ProviderVersion     string
+	Report              string
+	Graph               string
+	GraphFormat         string
+	GraphLevel          string
+	FailOn              string
+	Timeout             time.Duration
+	ServiceTimeout      time.Duration
 }
 
 const DefaultPathPattern = "{output}/{provider}/{service}/"
 const DefaultPathOutput = "generated"
 const DefaultState = "local"
+const DefaultImportMode = "state"
+const ImportModeBlocks = "blocks"
+const DefaultVariablesThreshold = 5
+
+// runReport collects the outcome of every Import call of the run, saved to --report
+var runReport = terraformutils.NewReport()
+
+// runGraph collects the resources and references of every Import call of the run, saved to --graph
+var runGraph = terraformutils.NewGraph()
+
+const DefaultLayout = "services"
+const LayoutModules = "modules"
 
 func newImportCmd() *cobra.Command {
 	options := ImportOptions{}
 	cmd := &cobra.Command{
-		Use:           "import",
-		Short:         "Import current state to Terraform configuration",
-		Long:          "Import current state to Terraform configuration",
-		SilenceUsage:  true,
-		SilenceErrors: false,
+		Use:                "import",
+		Short:              "Import current state to Terraform configuration",
+		Long:               "Import current state to Terraform configuration",
+		SilenceUsage:       true,
+		SilenceErrors:      false,
+		PersistentPostRunE: printFailuresSummary,
 		//Version:       version.String(),
 	}
 
@@ -81,38 +129,153 @@ func newImportCmd() *cobra.Command {
 }
 
 func Import(provider terraformutils.ProviderGenerator, options ImportOptions, args []string) error {
+	if err := validateFailOn(options.FailOn); err != nil {
+		return err
+	}
+	if options.Drift {
+		if err := setDriftOutput(options); err != nil {
+			return err
+		}
+	}
+	var naming *terraformutils.NamingTemplate
+	if options.Naming != "" {
+		template, err := terraformutils.NewNamingTemplate(options.Naming)
+		if err != nil {
+			return err
+		}
+		naming = &template
+	}
 
+	report := runReport
+	if options.Report != "" {
+		defer func() {
+			if err := report.Write(options.Report); err != nil {
+				log.Printf("failed to save report to %s: %s", options.Report, err)
+			}
+		}()
+	}
+	if options.Graph != "" {
+		defer writeGraph(options)
+	}
+
+	ctx, cancel := importContext(options)
+	defer cancel()
+	// regions left after an interrupt or the --timeout are not started, no plugin is configured for them
+	if ctx.Err() != nil {
+		return fmt.Errorf("%s import stopped: %w", provider.GetName(), ctx.Err())
+	}
 	providerWrapper, options, err := initOptionsAndWrapper(provider, options, args)
 	if err != nil {
 		return err
 	}
 	defer providerWrapper.Kill()
+	providerWrapper.SetRestartHook(func(plugin int, resourceType, id string, err error) {
+		report.AddPluginRestart(provider.GetName(), plugin, resourceType, id, err)
+	})
+	// load the schema before a cancellation kills the plugin, finished services are still written
+	providerWrapper.GetSchema()
+	stopKillOnDone := killOnDone(ctx, providerWrapper)
+	defer stopKillOnDone()
 	providerMapping := terraformutils.NewProvidersMapping(provider)
 
-	err = initAllServicesResources(providerMapping, options, args, providerWrapper)
+	managed, err := terraformutils.LoadManagedResources(options.ExcludeManagedState)
+	if err != nil {
+		return err
+	}
+	err = initAllServicesResources(ctx, providerMapping, options, args, providerWrapper, managed, report)
 	if err != nil {
 		return err
 	}
 
-	err = terraformutils.RefreshResourcesByProvider(providerMapping, providerWrapper)
+	err = terraformutils.RefreshResourcesByProvider(ctx, providerMapping, providerWrapper, options.Parallelism, func(service string, resources []terraformutils.Resource) {
+		saveCheckpoint(provider.GetName(), options, args, service, CheckpointRefreshed, resources)
+	})
 	if err != nil {
 		return err
 	}
+	refreshed := map[string]int{}
+	for service, resources := range providerMapping.GetResourcesByService() {
+		refreshed[service] = len(resources)
+		providerMapping.GetServiceReport(service).SetRefreshed(len(resources))
+	}
 
+	if naming != nil {
+		providerMapping.RenameResources(*naming)
+	}
 	providerMapping.ConvertTFStates(providerWrapper)
 	// change structs with additional data for each resource
 	providerMapping.CleanupProviders()
+	for service, resources := range providerMapping.GetResourcesByService() {
+		providerMapping.GetServiceReport(service).AddFiltered(refreshed[service] - len(resources))
+	}
 
-	err = importFromPlan(providerMapping, options, args)
+	err = importFromPlan(providerMapping, options, args, providerWrapper)
+	printManagedSummary(provider, managed)
+	if err == nil && ctx.Err() != nil {
+		return fmt.Errorf("%s import stopped, only services finished before were saved: %w", provider.GetName(), ctx.Err())
+	}
 
 	return err
 }
 
+// providerRegion returns the region imported by regional providers, empty for others
+func providerRegion(provider terraformutils.ProviderGenerator) string {
+	if regionalProvider, ok := provider.(terraformutils.RegionalProvider); ok {
+		return regionalProvider.GetRegion()
+	}
+	return ""
+}
+
+// writeGraph saves the graph of the run so far, each regional Import call rewrites it with its resources added
+func writeGraph(options ImportOptions) {
+	if err := runGraph.Write(options.Graph, options.GraphFormat, options.GraphLevel); err != nil {
+		log.Printf("failed to save graph to %s: %s", options.Graph, err)
+		return
+	}
+	if orphans := runGraph.Orphans(); len(orphans) > 0 {
+		log.Printf("graph: %d resources are referenced by nothing, flagged as orphans in %s", len(orphans), options.Graph)
+	}
+	for _, cycle := range runGraph.Cycles {
+		log.Printf("graph: services %s reference each other", strings.Join(cycle, ", "))
+	}
+}
+
+// printManagedSummary lists the resources skipped because existing state already manages them
+func printManagedSummary(provider terraformutils.ProviderGenerator, managed *terraformutils.ManagedResources) {
+	skipped := managed.Skipped()
+	if len(skipped) == 0 {
+		return
+	}
+	log.Printf("%s skipped %d resources already managed by existing state:", provider.GetName(), len(skipped))
+	for _, r := range skipped {
+		log.Printf("\t%s (%s)", r.Address(), r.InstanceState.ID)
+	}
+}
+
 func initOptionsAndWrapper(provider terraformutils.ProviderGenerator, options ImportOptions, args []string) (*providerwrapper.ProviderWrapper, ImportOptions, error) {
-	err := provider.Init(args)
+	err := providerwrapper.SelectProvider(provider.GetName(), options.ProviderPath, options.ProviderVersion)
 	if err != nil {
 		return nil, options, err
 	}
+	err = provider.Init(args)
+	if err != nil {
+		return nil, options, err
+	}
+
+	options.Filter, err = splitFilters(options.Filter)
+	if err != nil {
+		return nil, options, err
+	}
+	if options.FilterFile != "" {
+		filters, err := terraformutils.ReadFilterFile(options.FilterFile)
+		if err != nil {
+			return nil, options, err
+		}
+		options.Filter = append(options.Filter, filters...)
+	}
+	if err := terraformutils.ValidateFilters(options.Filter); err != nil {
+		return nil, options, err
+	}
 
 	if terraformerstring.ContainsString(options.Resources, "*") {
 		log.Println("Attempting an import of ALL resources in " + provider.GetName())
@@ -136,33 +299,78 @@ func initOptionsAndWrapper(provider terraformutils.ProviderGenerator, options Im
 		options.Resources = localSlice
 	}
 
-	providerWrapper, err := providerwrapper.NewProviderWrapper(provider.GetName(), provider.GetConfig(), options.Verbose, map[string]int{"retryCount": options.RetryCount, "retrySleepMs": options.RetrySleepMs})
+	providerWrapper, err := providerwrapper.NewProviderWrapper(provider.GetName(), provider.GetConfig(), options.Verbose, map[string]int{"retryCount": options.RetryCount, "retrySleepMs": options.RetrySleepMs, "pluginProcesses": options.PluginProcesses})
 	if err != nil {
 		return nil, options, err
 	}
 
+	rateLimits := terraformutils.RateLimits{}
+	if rateLimitedProvider, ok := provider.(terraformutils.RateLimitedProvider); ok {
+		rateLimits = rateLimitedProvider.GetRateLimits()
+	}
+	if options.Parallelism > 0 {
+		rateLimits.Parallelism = options.Parallelism
+	}
+	if options.RateLimit > 0 {
+		rateLimits.Rate = options.RateLimit
+	}
+	options.Parallelism = rateLimits.Parallelism
+	providerWrapper.SetRateLimiter(providerwrapper.NewRateLimiter(rateLimits.Rate, rateLimits.ResourceTypes))
+
 	return providerWrapper, options, nil
 }
 
-func initAllServicesResources(providersMapping *terraformutils.ProvidersMapping, options ImportOptions, args []string, providerWrapper *providerwrapper.ProviderWrapper) error {
-	numOfResources := len(options.Resources)
-	var wg sync.WaitGroup
-	wg.Add(numOfResources)
-
-	failedServices := []string{}
-
+func initAllServicesResources(ctx context.Context, providersMapping *terraformutils.ProvidersMapping, options ImportOptions, args []string, providerWrapper *providerwrapper.ProviderWrapper, managed *terraformutils.ManagedResources, report *terraformutils.Report) error {
+	serviceProviders := map[string]terraformutils.ProviderGenerator{}
 	for _, service := range options.Resources {
 		serviceProvider := providersMapping.AddServiceToProvider(service)
 		err := serviceProvider.Init(args)
 		if err != nil {
 			return err
 		}
-		err = initServiceResources(service, serviceProvider, options, providerWrapper)
-		if err != nil {
-			failedServices = append(failedServices, service)
-		}
+		serviceProviders[service] = serviceProvider
+		providersMapping.SetServiceReport(service, report.Service(serviceProvider.GetName(), providerRegion(serviceProvider), service))
+	}
+
+	// list services concurrently, each one failing on its own
+	parallelism := options.Parallelism
+	if parallelism <= 0 {
+		parallelism = terraformutils.DefaultParallelism
 	}
+	input := make(chan string, len(options.Resources))
+	for _, service := range options.Resources {
+		input <- service
+	}
+	close(input)
 
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	failedServices := []string{}
+	refreshedServices := []string{}
+	for i := 0; i < parallelism; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for service := range input {
+				serviceProvider := serviceProviders[service]
+				serviceReport := providersMapping.GetServiceReport(service)
+				refreshed, err := initServiceOrResume(ctx, service, serviceProvider, options, args, providerWrapper, managed, serviceReport)
+				serviceReport.SetError(err)
+				mutex.Lock()
+				if err != nil {
+					failedServices = append(failedServices, service)
+				} else if refreshed {
+					refreshedServices = append(refreshedServices, service)
+				}
+				mutex.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	for _, service := range refreshedServices {
+		providersMapping.MarkServiceRefreshed(service)
+	}
 	// remove providers that failed to init their service
 	providersMapping.RemoveServices(failedServices)
 	providersMapping.ProcessResources()
@@ -170,7 +378,43 @@ func initAllServicesResources(providersMapping *terraformutils.ProvidersMapping,
 	return nil
 }
 
-func importFromPlan(providerMapping *terraformutils.ProvidersMapping, options ImportOptions, args []string) error {
+// initServiceOrResume lists the resources of a service or restores them from its checkpoint,
+// reporting whether the restored resources were already refreshed
+func initServiceOrResume(ctx context.Context, service string, serviceProvider terraformutils.ProviderGenerator, options ImportOptions, args []string,
+	providerWrapper *providerwrapper.ProviderWrapper, managed *terraformutils.ManagedResources, serviceReport *terraformutils.ServiceReport) (bool, error) {
+	if resources, stage, ok := loadCheckpoint(serviceProvider.GetName(), options, args, service); ok {
+		err := restoreServiceResources(service, serviceProvider, options, resources)
+		if err != nil {
+			return false, err
+		}
+		serviceReport.Listed(0, len(resources), len(resources))
+		log.Printf("%s resumed %s from %s checkpoint", serviceProvider.GetName(), service, stage)
+		return stage == CheckpointRefreshed, nil
+	}
+	if err := ctx.Err(); err != nil {
+		return false, fmt.Errorf("listing interrupted: %w", err)
+	}
+	var serviceCtx context.Context
+	var cancel context.CancelFunc
+	if options.ServiceTimeout > 0 {
+		serviceCtx, cancel = context.WithTimeout(ctx, options.ServiceTimeout)
+	} else {
+		serviceCtx, cancel = context.WithCancel(ctx)
+	}
+	defer cancel()
+	err := initServiceResources(serviceCtx, service, serviceProvider, options, providerWrapper, managed, serviceReport)
+	if err != nil {
+		return false, err
+	}
+	// a listing ending with the context may be partial, it is never saved as complete
+	if err := serviceCtx.Err(); err != nil {
+		return false, err
+	}
+	saveCheckpoint(serviceProvider.GetName(), options, args, service, CheckpointListed, serviceProvider.GetService().GetResources())
+	return false, nil
+}
+
+func importFromPlan(providerMapping *terraformutils.ProvidersMapping, options ImportOptions, args []string, providerWrapper *providerwrapper.ProviderWrapper) error {
 	plan := &ImportPlan{
 		Provider:         providerMapping.GetBaseProvider().GetName(),
 		Options:          options,
@@ -183,58 +427,146 @@ func importFromPlan(providerMapping *terraformutils.ProvidersMapping, options Im
 		plan.ImportedResource[service] = append(plan.ImportedResource[service], resourcesByService[service]...)
 	}
 
+	if options.Drift {
+		return detectDrift(providerMapping, options, providerWrapper.GetSchema())
+	}
+
 	if options.Plan {
 		path := Path(options.PathPattern, providerMapping.GetBaseProvider().GetName(), "terraformer", options.PathOutput)
 		return ExportPlanFile(plan, path, "plan.json")
 	}
 
-	return ImportFromPlan(providerMapping.GetBaseProvider(), plan)
+	return ImportFromPlan(providerMapping.GetBaseProvider(), plan, providerWrapper)
 }
 
-func initServiceResources(service string, provider terraformutils.ProviderGenerator,
-	options ImportOptions, providerWrapper *providerwrapper.ProviderWrapper) error {
+func initServiceResources(ctx context.Context, service string, provider terraformutils.ProviderGenerator,
+	options ImportOptions, providerWrapper *providerwrapper.ProviderWrapper, managed *terraformutils.ManagedResources, serviceReport *terraformutils.ServiceReport) error {
 	log.Println(provider.GetName() + " importing... " + service)
+	start := time.Now()
 	err := provider.InitService(service, options.Verbose)
 	if err != nil {
 		log.Printf("%s error importing %s, err: %s\n", provider.GetName(), service, err)
 		return err
 	}
 	provider.GetService().ParseFilters(options.Filter)
-	err = provider.GetService().InitResources()
+	provider.GetService().SetManagedResources(managed)
+	provider.GetService().SetContext(ctx)
+	err = runWithContext(ctx, provider.GetService().InitResources, func() {
+		detachService(service, provider, options.Verbose)
+	})
 	if err != nil {
 		log.Printf("%s error initializing resources in service %s, err: %s\n", provider.GetName(), service, err)
 		return err
 	}
+	if ignoredErrorReporter, ok := provider.GetService().(terraformutils.IgnoredErrorReporter); ok && ignoredErrorReporter.IgnoredError() != nil {
+		log.Printf("%s skipped service %s, err: %s\n", provider.GetName(), service, ignoredErrorReporter.IgnoredError())
+		serviceReport.SetIgnoredError(ignoredErrorReporter.IgnoredError())
+	}
+	found := len(provider.GetService().GetResources())
 
 	provider.GetService().PopulateIgnoreKeys(providerWrapper)
 	provider.GetService().InitialCleanup()
+	serviceReport.Listed(time.Since(start), found, len(provider.GetService().GetResources()))
 	log.Println(provider.GetName() + " done importing " + service)
 
 	return nil
 }
 
-func ImportFromPlan(provider terraformutils.ProviderGenerator, plan *ImportPlan) error {
+// restoreServiceResources initializes the service without listing, its resources were already cleaned up before the checkpoint
+func restoreServiceResources(service string, provider terraformutils.ProviderGenerator, options ImportOptions, resources []terraformutils.Resource) error {
+	err := provider.InitService(service, options.Verbose)
+	if err != nil {
+		log.Printf("%s error importing %s, err: %s\n", provider.GetName(), service, err)
+		return err
+	}
+	provider.GetService().ParseFilters(options.Filter)
+	provider.GetService().SetResources(resources)
+	return nil
+}
+
+func ImportFromPlan(provider terraformutils.ProviderGenerator, plan *ImportPlan, providerWrapper *providerwrapper.ProviderWrapper) error {
 	options := plan.Options
 	importedResource := plan.ImportedResource
 	isServicePath := strings.Contains(options.PathPattern, "{service}")
 
+	if options.ImportMode == "" {
+		options.ImportMode = DefaultImportMode
+	}
+	if options.ImportMode != DefaultImportMode && options.ImportMode != ImportModeBlocks {
+		return fmt.Errorf("unsupported import mode: %s", options.ImportMode)
+	}
+	if options.Layout == "" {
+		options.Layout = DefaultLayout
+	}
+	if options.Layout != DefaultLayout && options.Layout != LayoutModules {
+		return fmt.Errorf("unsupported layout: %s", options.Layout)
+	}
+	if options.SecretsState == "" {
+		options.SecretsState = terraformutils.SecretsStateKeep
+	}
+	if options.SecretsState != terraformutils.SecretsStateKeep && options.SecretsState != terraformutils.SecretsStateStrip {
+		return fmt.Errorf("unsupported secrets state: %s", options.SecretsState)
+	}
+	secretPatterns, err := terraformutils.CompileSecretPatterns(options.SecretPatterns)
+	if err != nil {
+		return err
+	}
+	backend, err := terraformoutput.NewStateBackend(options.State, options.Bucket, options.BackendConfig)
+	if err != nil {
+		return err
+	}
+	remoteStateBackend := backend
+	if options.RemoteState != "" {
+		remoteStateBackend, err = terraformoutput.NewStateBackend(options.RemoteState, options.RemoteStateBucket, options.RemoteStateConfig)
+		if err != nil {
+			return err
+		}
+	}
+
+	knownValues := map[string]string{}
+	if options.ExtractVariables {
+		if knownValuesProvider, ok := provider.(terraformutils.KnownValuesProvider); ok {
+			knownValues = knownValuesProvider.GetKnownValues()
+		}
+	}
+
+	if options.Layout == LayoutModules {
+		return importModules(provider, options, importedResource, providerWrapper, backend, knownValues, secretPatterns)
+	}
+
+	connections := provider.GetResourceConnections()
+	var connected, inferred []terraformutils.Reference
+	if options.Graph != "" {
+		connected = terraformutils.ConnectionReferences(importedResource, connections)
+	}
 	if options.Connect {
 		log.Println(provider.GetName() + " Connecting.... ")
-		importedResource = terraformutils.ConnectServices(importedResource, isServicePath, provider.GetResourceConnections())
+		importedResource = terraformutils.ConnectServices(importedResource, isServicePath, connections)
+		if options.InferReferences {
+			inferredReferences := terraformutils.InferReferences(importedResource, connections, terraformutils.ServiceLinkFunc(isServicePath))
+			runReport.AddAmbiguousReferences(inferredReferences.Ambiguous)
+			connections = terraformutils.MergeConnections(connections, inferredReferences.Connections)
+			inferred = inferredReferences.References
+		}
+	}
+	if options.Graph != "" {
+		runGraph.Add(provider.GetName(), providerRegion(provider), importedResource, connected, inferred)
 	}
+	// services sharing a directory reference each other directly, without outputs and remote states
+	connections = terraformutils.RemoteConnections(connections, isServicePath)
 
 	if !isServicePath {
 		var compactedResources []terraformutils.Resource
 		for _, resources := range importedResource {
 			compactedResources = append(compactedResources, resources...)
 		}
-		e := printService(provider, "", options, compactedResources, importedResource)
+		e := printService(provider, "", options, compactedResources, importedResource, connections, providerWrapper, backend, remoteStateBackend, knownValues, secretPatterns)
 		if e != nil {
 			return e
 		}
 	} else {
 		for serviceName, resources := range importedResource {
-			e := printService(provider, serviceName, options, resources, importedResource)
+			e := printService(provider, serviceName, options, resources, importedResource, connections, providerWrapper, backend, remoteStateBackend, knownValues, secretPatterns)
 			if e != nil {
 				return e
 			}
@@ -243,116 +575,168 @@ func ImportFromPlan(provider terraformutils.ProviderGenerator, plan *ImportPlan)
 	return nil
 }
 
-func printService(provider terraformutils.ProviderGenerator, serviceName string, options ImportOptions, resources []terraformutils.Resource, importedResource map[string][]terraformutils.Resource) error {
+func printService(provider terraformutils.ProviderGenerator, serviceName string, options ImportOptions, resources []terraformutils.Resource, importedResource map[string][]terraformutils.Resource, connections map[string]map[string][]string, providerWrapper *providerwrapper.ProviderWrapper, backend, remoteStateBackend terraformoutput.StateBackend, knownValues map[string]string, secretPatterns []*regexp.Regexp) error {
 	log.Println(provider.GetName() + " save " + serviceName)
+	// redact secrets first, lifting them would write them to locals
+	secrets := map[string]string{}
+	if options.RedactSecrets {
+		secrets = terraformutils.RedactSecrets(resources, providerWrapper.GetSchema(), secretPatterns, options.SecretsState == terraformutils.SecretsStateStrip)
+	}
+	// replace literals with references before printing
+	lifted := terraformutils.LiftedValues{}
+	if options.ExtractVariables {
+		lifted = terraformutils.LiftValues(resources, knownValues, options.VariablesThreshold)
+	}
 	// Print HCL files for Resources
 	path := Path(options.PathPattern, provider.GetName(), serviceName, options.PathOutput)
-	err := terraformoutput.OutputHclFiles(resources, provider, path, serviceName, options.Compact, options.Output)
+	err := terraformoutput.OutputProviderFile(provider, path, options.Output, providerWrapper.GetSchema(), true)
 	if err != nil {
 		return err
 	}
-	tfStateFile, err := terraformutils.PrintTfState(resources)
+	err = terraformoutput.OutputHclFiles(resources, provider, path, serviceName, options.Compact, options.Output, providerWrapper.GetSchema(), connections)
 	if err != nil {
 		return err
 	}
-	// print or upload State file
-	if options.State == "bucket" {
-		log.Println(provider.GetName() + " upload tfstate to  bucket " + options.Bucket)
-		bucket := terraformoutput.BucketState{
-			Name: options.Bucket,
-		}
-		if err := bucket.BucketUpload(path, tfStateFile); err != nil {
+	if options.ImportMode == ImportModeBlocks {
+		err = printImportBlocks(provider, serviceName, options, path, resources)
+	} else {
+		err = printTfState(provider, serviceName, path, resources, providerWrapper.GetSchema(), backend)
+	}
+	if err != nil {
+		return err
+	}
+	// create backend file, in both import modes terraform keeps the state where remote state data sources expect it
+	if backend.Type() != DefaultState {
+		backendFile, err := terraformutils.Print(terraformoutput.BackendTfData(backend, path), options.Output)
+		if err != nil {
 			return err
 		}
-		// create Bucket file
-		if bucketStateDataFile, err := terraformutils.Print(bucket.BucketGetTfData(path), map[string]struct{}{}, options.Output); err == nil {
-			terraformoutput.PrintFile(path+"/bucket.tf", bucketStateDataFile)
+		terraformoutput.PrintFile(path+"/"+backendFileName(backend, options.Output), backendFile)
+	}
+	return printVariables(connections, serviceName, options, path, importedResource, remoteStateBackend, lifted, secrets)
+}
+
+// backendFileName keeps bucket.tf for gcs, the name used before other backends were supported
+func backendFileName(backend terraformoutput.StateBackend, output string) string {
+	if backend.Type() == "gcs" {
+		return "bucket." + terraformoutput.GetFileExtension(output)
+	}
+	return "backend." + terraformoutput.GetFileExtension(output)
+}
+
+// printVariables writes remote states of services connected from other directories, lifted variables, secret
+// variables and locals to variables.tf
+func printVariables(connections map[string]map[string][]string, serviceName string, options ImportOptions, path string, importedResource map[string][]terraformutils.Resource, backend terraformoutput.StateBackend, lifted terraformutils.LiftedValues, secrets map[string]string) error {
+	variables := map[string]interface{}{}
+	if options.Connect {
+		remoteStates := map[string]interface{}{}
+		for k := range connections[serviceName] {
+			if _, exist := importedResource[k]; !exist {
+				continue
+			}
+			remoteStates[k] = terraformoutput.RemoteStateTfData(backend, strings.ReplaceAll(path, serviceName, k))
 		}
-	} else {
-		if serviceName == "" {
-			log.Println(provider.GetName() + " save tfstate")
-		} else {
-			log.Println(provider.GetName() + " save tfstate for " + serviceName)
+		if len(remoteStates) > 0 {
+			variables["data"] = map[string]interface{}{"terraform_remote_state": remoteStates}
 		}
-		if err := ioutil.WriteFile(path+"/terraform.tfstate", tfStateFile, os.ModePerm); err != nil {
+	}
+	declarations := map[string]interface{}{}
+	for name := range lifted.Variables {
+		declarations[name] = map[string]interface{}{}
+	}
+	for name := range secrets {
+		declarations[name] = map[string]interface{}{"sensitive": true}
+	}
+	if len(declarations) > 0 {
+		variables["variable"] = declarations
+	}
+	if len(lifted.Locals) > 0 {
+		variables["locals"] = lifted.Locals
+	}
+	if err := printSecretsExample(path, options.Output, secrets); err != nil {
+		return err
+	}
+	return printVariablesFiles(path, options.Output, variables, lifted.Variables)
+}
+
+// printSecretsExample writes the names of secret variables to secrets.auto.tfvars.example, never their values,
+// copied to secrets.auto.tfvars and filled in terraform loads it automatically
+func printSecretsExample(path, output string, secrets map[string]string) error {
+	if len(secrets) == 0 {
+		return nil
+	}
+	names := map[string]string{}
+	for name := range secrets {
+		names[name] = ""
+	}
+	exampleFile, err := terraformutils.PrintTfVars(names, output)
+	if err != nil {
+		return err
+	}
+	extension := "tfvars"
+	if output == "json" {
+		extension = "tfvars.json"
+	}
+	log.Printf("%d secrets replaced by sensitive variables, listed in %s/secrets.auto.%s.example", len(secrets), path, extension)
+	terraformoutput.PrintFile(path+"/secrets.auto."+extension+".example", exampleFile)
+	return nil
+}
+
+// printVariablesFiles writes variables.tf and terraform.tfvars, skipping empty files
+func printVariablesFiles(path, output string, variables map[string]interface{}, tfVars map[string]string) error {
+	if len(variables) > 0 {
+		variablesFile, err := terraformutils.Print(variables, output)
+		if err != nil {
 			return err
 		}
+		terraformoutput.PrintFile(path+"/variables."+terraformoutput.GetFileExtension(output), variablesFile)
 	}
-	// Print hcl variables.tf
-	if serviceName != "" {
-		if options.Connect && len(provider.GetResourceConnections()[serviceName]) > 0 {
-			variables := map[string]map[string]map[string]interface{}{}
-			variables["data"] = map[string]map[string]interface{}{}
-			variables["data"]["terraform_remote_state"] = map[string]interface{}{}
-			if options.State == "bucket" {
-				bucket := terraformoutput.BucketState{
-					Name: options.Bucket,
-				}
-				for k := range provider.GetResourceConnections()[serviceName] {
-					if _, exist := importedResource[k]; !exist {
-						continue
-					}
-					variables["data"]["terraform_remote_state"][k] = map[string]interface{}{
-						"backend": "gcs",
-						"config":  bucket.BucketGetTfData(strings.ReplaceAll(path, serviceName, k)),
-					}
-				}
-			} else {
-				for k := range provider.GetResourceConnections()[serviceName] {
-					if _, exist := importedResource[k]; !exist {
-						continue
-					}
-					variables["data"]["terraform_remote_state"][k] = map[string]interface{}{
-						"backend": "local",
-						"config": [1]interface{}{map[string]interface{}{
-							"path": strings.Repeat("../", strings.Count(path, "/")) + strings.ReplaceAll(path, serviceName, k) + "terraform.tfstate",
-						}},
-					}
-				}
-			}
-			// create variables file
-			if len(provider.GetResourceConnections()[serviceName]) > 0 && options.Connect && len(variables["data"]["terraform_remote_state"]) > 0 {
-				variablesFile, err := terraformutils.Print(variables, map[string]struct{}{"config": {}}, options.Output)
-				if err != nil {
-					return err
-				}
-				terraformoutput.PrintFile(path+"/variables."+terraformoutput.GetFileExtension(options.Output), variablesFile)
-			}
+	if len(tfVars) > 0 {
+		tfVarsFile, err := terraformutils.PrintTfVars(tfVars, output)
+		if err != nil {
+			return err
 		}
-	} else {
-		if options.Connect {
-			variables := map[string]map[string]map[string]interface{}{}
-			variables["data"] = map[string]map[string]interface{}{}
-			variables["data"]["terraform_remote_state"] = map[string]interface{}{}
-			if options.State == "bucket" {
-				bucket := terraformoutput.BucketState{
-					Name: options.Bucket,
-				}
-				variables["data"]["terraform_remote_state"]["local"] = map[string]interface{}{
-					"backend": "gcs",
-					"config":  bucket.BucketGetTfData(path),
-				}
-			} else {
-				variables["data"]["terraform_remote_state"]["local"] = map[string]interface{}{
-					"backend": "local",
-					"config": map[string]interface{}{
-						"path": "terraform.tfstate",
-					},
-				}
-			}
-			// create variables file
-			if options.Connect {
-				variablesFile, err := terraformutils.Print(variables, map[string]struct{}{"config": {}}, options.Output)
-				if err != nil {
-					return err
-				}
-				terraformoutput.PrintFile(path+"/variables."+terraformoutput.GetFileExtension(options.Output), variablesFile)
-			}
+		extension := "tfvars"
+		if output == "json" {
+			extension = "tfvars.json"
 		}
+		terraformoutput.PrintFile(path+"/terraform."+extension, tfVarsFile)
 	}
 	return nil
 }
 
+func printTfState(provider terraformutils.ProviderGenerator, serviceName string, path string, resources []terraformutils.Resource, schema *providers.GetSchemaResponse, backend terraformoutput.StateBackend) error {
+	tfStateFile, err := terraformutils.PrintTfState(resources, schema)
+	if err != nil {
+		return err
+	}
+	// print or upload State file
+	switch {
+	case backend.Type() != DefaultState:
+		log.Println(provider.GetName() + " upload tfstate to " + backend.Type() + " backend")
+	case serviceName == "":
+		log.Println(provider.GetName() + " save tfstate")
+	default:
+		log.Println(provider.GetName() + " save tfstate for " + serviceName)
+	}
+	return backend.Upload(path, tfStateFile)
+}
+
+// printImportBlocks writes imports.tf instead of a tfstate, leaving the adoption of resources to terraform plan
+func printImportBlocks(provider terraformutils.ProviderGenerator, serviceName string, options ImportOptions, path string, resources []terraformutils.Resource) error {
+	if serviceName == "" {
+		log.Println(provider.GetName() + " save import blocks")
+	} else {
+		log.Println(provider.GetName() + " save import blocks for " + serviceName)
+	}
+	importsFile, err := terraformutils.PrintImportBlocks(resources, options.Output)
+	if err != nil {
+		return err
+	}
+	terraformoutput.PrintFile(path+"/imports."+terraformoutput.GetFileExtension(options.Output), importsFile)
+	return nil
+}
+
 func Path(pathPattern, providerName, serviceName, output string) string {
 	return strings.NewReplacer(
 		"{provider}", providerName,
@@ -387,18 +771,76 @@ func providerServices(provider terraformutils.ProviderGenerator) []string {
 	return services
 }
 
+// splitFilters splits comma separated filters of a single --filter as CSV, as they were before expressions,
+// so quoted values keep their commas. Expressions are kept whole, they may hold commas, e.g. tags.Env in ['a', 'b'].
+func splitFilters(values []string) ([]string, error) {
+	filters := []string{}
+	for _, value := range values {
+		if strings.HasPrefix(value, "Expr=") || strings.Contains(value, ";Expr=") {
+			filters = append(filters, value)
+			continue
+		}
+		if value == "" {
+			continue
+		}
+		record, err := csv.NewReader(strings.NewReader(value)).Read()
+		if err != nil {
+			return nil, fmt.Errorf("invalid filter %s: %w", value, err)
+		}
+		for _, filter := range record {
+			if filter != "" {
+				filters = append(filters, filter)
+			}
+		}
+	}
+	return filters, nil
+}
+
 func baseProviderFlags(flag *pflag.FlagSet, options *ImportOptions, sampleRes, sampleFilters string) {
 	flag.BoolVarP(&options.Connect, "connect", "c", true, "")
+	flag.BoolVarP(&options.InferReferences, "infer-references", "", false, "with --connect, replace IDs, ARNs and self links of other imported resources with references")
 	flag.BoolVarP(&options.Compact, "compact", "C", false, "")
 	flag.StringSliceVarP(&options.Resources, "resources", "r", []string{}, sampleRes)
 	flag.StringSliceVarP(&options.Excludes, "excludes", "x", []string{}, sampleRes)
 	flag.StringVarP(&options.PathPattern, "path-pattern", "p", DefaultPathPattern, "{output}/{provider}/")
 	flag.StringVarP(&options.PathOutput, "path-output", "o", DefaultPathOutput, "")
-	flag.StringVarP(&options.State, "state", "s", DefaultState, "local or bucket")
-	flag.StringVarP(&options.Bucket, "bucket", "b", "", "gs://terraform-state")
-	flag.StringSliceVarP(&options.Filter, "filter", "f", []string{}, sampleFilters)
+	flag.StringVarP(&options.State, "state", "s", DefaultState, "local, bucket (gcs), s3, azurerm or http")
+	flag.StringVarP(&options.Bucket, "bucket", "b", "", "gs://terraform-state, s3://terraform-state, container or http address")
+	flag.StringSliceVarP(&options.BackendConfig, "backend-config", "", []string{}, "region=eu-west-1,dynamodb_table=terraform-lock")
+	flag.StringVarP(&options.RemoteState, "remote-state", "", "", "backend of the remote states connecting services, local, bucket (gcs), s3, azurerm or http (default --state)")
+	flag.StringVarP(&options.RemoteStateBucket, "remote-state-bucket", "", "", "bucket, container or http address of --remote-state")
+	flag.StringSliceVarP(&options.RemoteStateConfig, "remote-state-config", "", []string{}, "config of --remote-state, e.g. region=eu-west-1")
+	flag.StringArrayVarP(&options.Filter, "filter", "f", []string{}, sampleFilters)
+	flag.StringVarP(&options.FilterFile, "filter-file", "", "", "file with one filter per line, e.g. Expr=tags.Env != 'prod' && name =~ '^team-a-'")
 	flag.BoolVarP(&options.Verbose, "verbose", "v", false, "")
 	flag.StringVarP(&options.Output, "output", "O", "hcl", "output format hcl or json")
 	flag.IntVarP(&options.RetryCount, "retry-number", "n", 5, "number of retries to perform when refresh fails")
 	flag.IntVarP(&options.RetrySleepMs, "retry-sleep-ms", "m", 300, "time in ms to sleep between retries")
+	flag.StringVarP(&options.ImportMode, "import-mode", "", DefaultImportMode, "state or blocks")
+	flag.StringVarP(&options.CheckpointDir, "checkpoint-dir", "", "", "directory to save per service checkpoints to")
+	flag.BoolVarP(&options.Resume, "resume", "", false, "skip services already saved in --checkpoint-dir")
+	flag.StringSliceVarP(&options.ExcludeManagedState, "exclude-managed-state", "", []string{}, "terraform.tfstate,other/terraform.tfstate")
+	flag.BoolVarP(&options.ExtractVariables, "extract-variables", "", false, "replace account, region, project and repeated values with variables and locals")
+	flag.IntVarP(&options.VariablesThreshold, "variables-threshold", "", DefaultVariablesThreshold, "number of uses above which a value becomes a local")
+	flag.BoolVarP(&options.RedactSecrets, "redact-secrets", "", false, "replace sensitive attributes with sensitive variables listed in secrets.auto.tfvars.example")
+	flag.StringSliceVarP(&options.SecretPatterns, "secret-patterns", "", []string{}, "with --redact-secrets, also redact <type>.<attribute> matching these regular expressions, e.g. (?i)password|token")
+	flag.StringVarP(&options.SecretsState, "secrets-state", "", terraformutils.SecretsStateKeep, "with --redact-secrets, keep or strip secrets in the state")
+	flag.StringVarP(&options.Layout, "layout", "", DefaultLayout, "services or modules")
+	flag.IntVarP(&options.Parallelism, "parallelism", "", 0, "number of services listed and resources refreshed at once (default 15 or the provider default)")
+	flag.Float64VarP(&options.RateLimit, "rate-limit", "", 0, "max provider requests per second, 0 for the provider default")
+	flag.StringVarP(&options.ProviderPath, "provider-path", "", "", "provider plugin binary to use instead of the installed ones")
+	flag.StringVarP(&options.ProviderVersion, "provider-version", "", "", "version constraint of the installed provider plugin to use, e.g. \"~> 3.0\"")
+	flag.IntVarP(&options.PluginProcesses, "plugin-processes", "", 1, "number of provider plugin processes refreshes are spread over")
+	flag.DurationVarP(&options.Timeout, "timeout", "", 0, "stop and save finished services after this duration, e.g. 30m")
+	flag.DurationVarP(&options.ServiceTimeout, "service-timeout", "", 0, "fail services whose listing takes longer, e.g. 5m")
+	flag.StringVarP(&options.Report, "report", "", "", "report.json, save per service and per resource outcomes")
+	flag.StringVarP(&options.Graph, "graph", "", "", "graph.dot, save the dependency graph of the imported resources")
+	flag.StringVarP(&options.GraphFormat, "graph-format", "", "", "dot, json or mermaid (default from the --graph extension)")
+	flag.StringVarP(&options.GraphLevel, "graph-level", "", terraformutils.GraphLevelResource, "resource or service, nodes of dot and mermaid graphs")
+	flag.StringVarP(&options.Naming, "naming", "", "", "resource name template, e.g. {tag:Name|name}")
+	flag.StringVarP(&options.FailOn, "fail-on", "", FailOnNone, "none, service or resource")
+	if options.Drift {
+		flag.StringVarP(&options.DriftFormat, "drift-format", "", DriftFormatTable, "table or json")
+		flag.StringVarP(&options.DriftOut, "drift-out", "", "", "file to write the drift report to, defaults to stdout")
+	}
 }
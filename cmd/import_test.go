@@ -0,0 +1,55 @@
+// Copyright 2018 The Terraformer Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/spf13/pflag"
+)
+
+func TestFilterFlags(t *testing.T) {
+	options := ImportOptions{}
+	flags := pflag.NewFlagSet("import", pflag.ContinueOnError)
+	baseProviderFlags(flags, &options, "vpc,subnet", "vpc=id1:id2")
+	err := flags.Parse([]string{
+		"--filter=Expr=tags.Env in ['dev','staging'] && name =~ '^a{1,3}'",
+		"--filter", "Type=instance;Expr=tags.Team in ['a', 'b']",
+		"--filter=vpc=id1:id2,subnet=id3",
+		`--filter="Name=tags.Name;Value=a,b",Type=vpc;Name=cidr;Value=10.0.0.0/16`,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{
+		"Expr=tags.Env in ['dev','staging'] && name =~ '^a{1,3}'",
+		"Type=instance;Expr=tags.Team in ['a', 'b']",
+		"vpc=id1:id2",
+		"subnet=id3",
+		"Name=tags.Name;Value=a,b",
+		"Type=vpc;Name=cidr;Value=10.0.0.0/16",
+	}
+	filters, err := splitFilters(options.Filter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(filters, expected) {
+		t.Errorf("unexpected filters %q", filters)
+	}
+	if _, err := splitFilters([]string{`Name=tags.Name;Value="a`}); err == nil {
+		t.Error("expected an error for an unterminated quote")
+	}
+}
@@ -0,0 +1,176 @@
+// Copyright 2018 The Terraformer Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package cmd
+
+import (
+	"errors"
+	"log"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/terraformer/terraformutils"
+	"github.com/GoogleCloudPlatform/terraformer/terraformutils/providerwrapper"
+	"github.com/GoogleCloudPlatform/terraformer/terraformutils/terraformoutput"
+)
+
+// importModules writes every service as a child module under modules/<service> and a root module
+// instantiating them with a single state. Connected services pass outputs to each other as module inputs.
+func importModules(provider terraformutils.ProviderGenerator, options ImportOptions, importedResource map[string][]terraformutils.Resource, providerWrapper *providerwrapper.ProviderWrapper, backend terraformoutput.StateBackend, knownValues map[string]string, secretPatterns []*regexp.Regexp) error {
+	if !strings.Contains(options.PathPattern, "{service}") {
+		return errors.New("modules layout requires {service} in --path-pattern")
+	}
+	root := filepath.Clean(Path(options.PathPattern, provider.GetName(), "", options.PathOutput)) + "/"
+	schema := providerWrapper.GetSchema()
+
+	// inputs of each module by name, with the module whose output feeds them
+	inputs := map[string]map[string]string{}
+	connections := provider.GetResourceConnections()
+	var connected, inferred []terraformutils.Reference
+	if options.Graph != "" {
+		connected = terraformutils.ConnectionReferences(importedResource, connections)
+	}
+	if options.Connect {
+		log.Println(provider.GetName() + " Connecting.... ")
+		link := func(from, to string, resourceToMap terraformutils.Resource, key string) string {
+			if from == to {
+				return "${" + resourceToMap.Address() + "." + key + "}"
+			}
+			name := terraformutils.OutputName(resourceToMap, key)
+			if inputs[from] == nil {
+				inputs[from] = map[string]string{}
+			}
+			inputs[from][name] = to
+			return "${var." + name + "}"
+		}
+		importedResource = terraformutils.ConnectServicesWithLink(importedResource, connections, link)
+		if options.InferReferences {
+			inferredReferences := terraformutils.InferReferences(importedResource, connections, link)
+			runReport.AddAmbiguousReferences(inferredReferences.Ambiguous)
+			connections = terraformutils.MergeConnections(connections, inferredReferences.Connections)
+			inferred = inferredReferences.References
+		}
+	}
+	if options.Graph != "" {
+		runGraph.Add(provider.GetName(), providerRegion(provider), importedResource, connected, inferred)
+	}
+	connections = terraformutils.RemoteConnections(connections, true)
+
+	services := make([]string, 0, len(importedResource))
+	for service := range importedResource {
+		services = append(services, service)
+	}
+	sort.Strings(services)
+	modules := map[string]interface{}{}
+	rootVariables := map[string]interface{}{}
+	rootTfVars := map[string]string{}
+	rootSecrets := map[string]string{}
+	for _, service := range services {
+		resources := importedResource[service]
+		log.Println(provider.GetName() + " save module " + service)
+		path := root + "modules/" + service + "/"
+		secrets := map[string]string{}
+		if options.RedactSecrets {
+			secrets = terraformutils.RedactSecrets(resources, schema, secretPatterns, options.SecretsState == terraformutils.SecretsStateStrip)
+		}
+		lifted := terraformutils.LiftedValues{}
+		if options.ExtractVariables {
+			lifted = terraformutils.LiftValues(resources, knownValues, options.VariablesThreshold)
+		}
+		err := terraformoutput.OutputProviderFile(provider, path, options.Output, schema, false)
+		if err != nil {
+			return err
+		}
+		err = terraformoutput.OutputHclFiles(resources, provider, path, service, options.Compact, options.Output, schema, connections)
+		if err != nil {
+			return err
+		}
+
+		module := map[string]interface{}{"source": "./modules/" + service}
+		declarations := map[string]interface{}{}
+		for name, source := range inputs[service] {
+			declarations[name] = map[string]interface{}{}
+			module[name] = "${module." + source + "." + name + "}"
+		}
+		for name, value := range lifted.Variables {
+			declarations[name] = map[string]interface{}{}
+			module[name] = "${var." + name + "}"
+			rootVariables[name] = map[string]interface{}{}
+			rootTfVars[name] = value
+		}
+		for name, value := range secrets {
+			declarations[name] = map[string]interface{}{"sensitive": true}
+			module[name] = "${var." + name + "}"
+			rootVariables[name] = map[string]interface{}{"sensitive": true}
+			rootSecrets[name] = value
+		}
+		variables := map[string]interface{}{}
+		if len(declarations) > 0 {
+			variables["variable"] = declarations
+		}
+		if len(lifted.Locals) > 0 {
+			variables["locals"] = lifted.Locals
+		}
+		if err := printVariablesFiles(path, options.Output, variables, map[string]string{}); err != nil {
+			return err
+		}
+		modules[service] = module
+	}
+
+	log.Println(provider.GetName() + " save root module")
+	if err := terraformoutput.OutputProviderFile(provider, root, options.Output, schema, true); err != nil {
+		return err
+	}
+	mainFile, err := terraformutils.Print(map[string]interface{}{"module": modules}, options.Output)
+	if err != nil {
+		return err
+	}
+	terraformoutput.PrintFile(root+"main."+terraformoutput.GetFileExtension(options.Output), mainFile)
+	variables := map[string]interface{}{}
+	if len(rootVariables) > 0 {
+		variables["variable"] = rootVariables
+	}
+	if err := printVariablesFiles(root, options.Output, variables, rootTfVars); err != nil {
+		return err
+	}
+	if err := printSecretsExample(root, options.Output, rootSecrets); err != nil {
+		return err
+	}
+
+	if options.ImportMode == ImportModeBlocks {
+		importsFile, err := terraformutils.PrintModulesImportBlocks(importedResource, options.Output)
+		if err != nil {
+			return err
+		}
+		terraformoutput.PrintFile(root+"imports."+terraformoutput.GetFileExtension(options.Output), importsFile)
+	} else {
+		tfStateFile, err := terraformutils.PrintModulesTfState(importedResource, schema)
+		if err != nil {
+			return err
+		}
+		log.Println(provider.GetName() + " save tfstate of root module")
+		if err := backend.Upload(root, tfStateFile); err != nil {
+			return err
+		}
+	}
+	if backend.Type() != DefaultState {
+		backendFile, err := terraformutils.Print(terraformoutput.BackendTfData(backend, root), options.Output)
+		if err != nil {
+			return err
+		}
+		terraformoutput.PrintFile(root+backendFileName(backend, options.Output), backendFile)
+	}
+	return nil
+}
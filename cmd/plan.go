@@ -21,6 +21,8 @@ import (
 	"path/filepath"
 
 	"github.com/GoogleCloudPlatform/terraformer/terraformutils"
+	"github.com/GoogleCloudPlatform/terraformer/terraformutils/providerwrapper"
+	"github.com/GoogleCloudPlatform/terraformer/terraformutils/terraformoutput"
 	"github.com/spf13/cobra"
 )
 
@@ -30,6 +32,8 @@ type ImportPlan struct {
 	Options          ImportOptions
 	Args             []string
 	ImportedResource map[string][]terraformutils.Resource
+	// OptionsHash identifies the options a checkpoint was saved with, see checkpointOptionsHash
+	OptionsHash string `json:",omitempty"`
 }
 
 func newPlanCmd() *cobra.Command {
@@ -37,11 +41,12 @@ func newPlanCmd() *cobra.Command {
 		Plan: true,
 	}
 	cmd := &cobra.Command{
-		Use:           "plan",
-		Short:         "Plan to import current state to Terraform configuration",
-		Long:          "Plan to import current state to Terraform configuration",
-		SilenceUsage:  true,
-		SilenceErrors: false,
+		Use:                "plan",
+		Short:              "Plan to import current state to Terraform configuration",
+		Long:               "Plan to import current state to Terraform configuration",
+		SilenceUsage:       true,
+		SilenceErrors:      false,
+		PersistentPostRunE: printFailuresSummary,
 		//Version:       version.String(),
 	}
 
@@ -52,6 +57,7 @@ func newPlanCmd() *cobra.Command {
 }
 
 func newCmdPlanImporter(options ImportOptions) *cobra.Command {
+	var backendConfig, remoteStateConfig []string
 	cmd := &cobra.Command{
 		Use:   "plan",
 		Short: "Import planned state to Terraform configuration",
@@ -62,6 +68,8 @@ func newCmdPlanImporter(options ImportOptions) *cobra.Command {
 			if err != nil {
 				return err
 			}
+			plan.Options.BackendConfig = append(plan.Options.BackendConfig, backendConfig...)
+			plan.Options.RemoteStateConfig = append(plan.Options.RemoteStateConfig, remoteStateConfig...)
 
 			var provider terraformutils.ProviderGenerator
 			if providerGen, ok := providerGenerators()[plan.Provider]; ok {
@@ -70,6 +78,9 @@ func newCmdPlanImporter(options ImportOptions) *cobra.Command {
 				return fmt.Errorf("unsupported provider: %s", plan.Provider)
 			}
 
+			if err = providerwrapper.SelectProvider(provider.GetName(), plan.Options.ProviderPath, plan.Options.ProviderVersion); err != nil {
+				return err
+			}
 			if err = provider.Init(plan.Args); err != nil {
 				return err
 			}
@@ -80,9 +91,30 @@ func newCmdPlanImporter(options ImportOptions) *cobra.Command {
 				}
 			}
 
-			return ImportFromPlan(provider, plan)
+			// the planned resources are already refreshed, only the schema is needed
+			providerWrapper, err := providerwrapper.NewSchemaProviderWrapper(provider.GetName(), plan.Options.Verbose)
+			if err != nil {
+				return err
+			}
+			defer providerWrapper.Kill()
+
+			// the report and the graph of the planned options are written like in a direct import
+			if plan.Options.Report != "" {
+				defer func() {
+					if err := runReport.Write(plan.Options.Report); err != nil {
+						log.Printf("failed to save report to %s: %s", plan.Options.Report, err)
+					}
+				}()
+			}
+			if plan.Options.Graph != "" {
+				defer writeGraph(plan.Options)
+			}
+
+			return ImportFromPlan(provider, plan, providerWrapper)
 		},
 	}
+	cmd.Flags().StringSliceVarP(&backendConfig, "backend-config", "", []string{}, "credentials of --state, they are not saved in plan files")
+	cmd.Flags().StringSliceVarP(&remoteStateConfig, "remote-state-config", "", []string{}, "credentials of --remote-state, they are not saved in plan files")
 	return cmd
 }
 
@@ -109,6 +141,10 @@ func LoadPlanfile(path string) (*ImportPlan, error) {
 
 func ExportPlanFile(plan *ImportPlan, path, filename string) error {
 	plan.Version = version
+	// backend credentials are read from flags or the environment again when the plan is imported
+	saved := *plan
+	saved.Options.BackendConfig = terraformoutput.RedactBackendConfig(plan.Options.BackendConfig)
+	saved.Options.RemoteStateConfig = terraformoutput.RedactBackendConfig(plan.Options.RemoteStateConfig)
 
 	planfilePath := filepath.Join(path, filename)
 	log.Println("Saving planfile to", planfilePath)
@@ -125,5 +161,5 @@ func ExportPlanFile(plan *ImportPlan, path, filename string) error {
 
 	enc := json.NewEncoder(f)
 	enc.SetIndent("", "\t")
-	return enc.Encode(plan)
+	return enc.Encode(saved)
 }
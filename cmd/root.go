@@ -15,6 +15,8 @@
 package cmd
 
 import (
+	"os"
+
 	"github.com/GoogleCloudPlatform/terraformer/terraformutils"
 	"github.com/spf13/cobra"
 )
@@ -27,13 +29,40 @@ func NewCmdRoot() *cobra.Command {
 	}
 	cmd.AddCommand(newImportCmd())
 	cmd.AddCommand(newPlanCmd())
+	cmd.AddCommand(newDriftCmd())
+	cmd.AddCommand(newSchemaCmd())
+	cmd.AddCommand(newGraphCmd())
 	cmd.AddCommand(versionCmd)
 	return cmd
 }
 
+// ExitError ends terraformer with a specific exit code
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+func (e *ExitError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
 func Execute() error {
+	stop := cancelOnSignal()
+	defer stop()
 	cmd := NewCmdRoot()
-	return cmd.Execute()
+	err := cmd.ExecuteContext(runContext)
+	if err == nil {
+		return nil
+	}
+	// post-run hooks are skipped when the command fails, its failures are summarized here
+	if executed, _, findErr := cmd.Find(os.Args[1:]); findErr == nil {
+		return summarizeFailedRun(executed, err)
+	}
+	return err
 }
 
 func providerImporterSubcommands() []func(options ImportOptions) *cobra.Command {
@@ -0,0 +1,66 @@
+// Copyright 2018 The Terraformer Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/GoogleCloudPlatform/terraformer/terraformutils/providerwrapper"
+	"github.com/spf13/cobra"
+)
+
+func newSchemaCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "schema",
+		Short: "Inspect provider schemas",
+		Long:  "Inspect the provider schemas terraformer caches per provider binary",
+	}
+	cmd.AddCommand(newSchemaDumpCmd())
+	return cmd
+}
+
+func newSchemaDumpCmd() *cobra.Command {
+	var providerPath, providerVersion string
+	var verbose bool
+	cmd := &cobra.Command{
+		Use:   "dump <provider>",
+		Short: "Print the schema of a provider as JSON",
+		Long:  "Print the cached schema of the installed provider as JSON, fetching and caching it when missing",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			providerName := args[0]
+			if providerGen, ok := providerGenerators()[providerName]; ok {
+				providerName = providerGen().GetName()
+			}
+			if err := providerwrapper.SelectProvider(providerName, providerPath, providerVersion); err != nil {
+				return err
+			}
+			schema, err := providerwrapper.GetProviderSchema(providerName, verbose)
+			if err != nil {
+				return err
+			}
+			out, err := json.MarshalIndent(schema, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), string(out))
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&providerPath, "provider-path", "", "provider plugin binary to use instead of the installed ones")
+	cmd.Flags().StringVar(&providerVersion, "provider-version", "", "version constraint of the installed provider plugin to use, e.g. \"~> 3.0\"")
+	cmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "")
+	return cmd
+}
@@ -0,0 +1,111 @@
+// Copyright 2018 The Terraformer Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/terraformer/terraformutils"
+	"github.com/GoogleCloudPlatform/terraformer/terraformutils/providerwrapper"
+	"github.com/spf13/cobra"
+)
+
+const (
+	FailOnNone     = "none"
+	FailOnService  = "service"
+	FailOnResource = "resource"
+	// SkippedResourcesExitCode is returned with --fail-on=resource when the import completed without some resources
+	SkippedResourcesExitCode = 3
+	// FailedServicesExitCode is returned with --fail-on=service or resource when listing a service failed
+	FailedServicesExitCode = 4
+)
+
+func validateFailOn(failOn string) error {
+	switch failOn {
+	case "", FailOnNone, FailOnService, FailOnResource:
+		return nil
+	}
+	return fmt.Errorf("unsupported fail-on policy: %s", failOn)
+}
+
+// failuresSummarized is set once printFailuresSummary ran, from a post-run hook or from summarizeFailedRun
+var failuresSummarized bool
+
+// printFailuresSummary lists the failures of all Import calls of the run and applies the --fail-on policy
+func printFailuresSummary(cmd *cobra.Command, args []string) error {
+	failuresSummarized = true
+	failOn, err := cmd.Flags().GetString("fail-on")
+	if err != nil {
+		failOn = FailOnNone
+	}
+	failedServices, failedResources := runReport.Failures()
+	if len(failedServices) == 0 && len(failedResources) == 0 {
+		return nil
+	}
+
+	log.Printf("completed with %d failed services and %d skipped resources:", len(failedServices), len(failedResources))
+	for _, s := range failedServices {
+		log.Printf("\tservice %s %s %s: %s", s.Provider, s.Region, s.Service, s.Error)
+	}
+	deniedTypes := map[string][]terraformutils.ResourceFailure{}
+	for _, r := range failedResources {
+		if r.Class == string(providerwrapper.ErrorPermissionDenied) {
+			resourceType := strings.SplitN(r.Address, ".", 2)[0]
+			deniedTypes[resourceType] = append(deniedTypes[resourceType], r)
+			continue
+		}
+		log.Printf("\tresource %s (%s): %s", r.Address, r.ID, r.Error)
+	}
+	// permission errors are the same for all resources of a type, reported once
+	for _, resourceType := range sortedResourceTypes(deniedTypes) {
+		failures := deniedTypes[resourceType]
+		log.Printf("\tresource type %s, %d resources: %s", resourceType, len(failures), failures[0].Error)
+	}
+
+	switch {
+	case failOn == FailOnNone || failOn == "":
+		return nil
+	case len(failedServices) > 0:
+		return &ExitError{Code: FailedServicesExitCode, Err: fmt.Errorf("listing %d services failed", len(failedServices))}
+	case failOn == FailOnResource:
+		return &ExitError{Code: SkippedResourcesExitCode, Err: fmt.Errorf("%d resources were skipped", len(failedResources))}
+	}
+	return nil
+}
+
+// summarizeFailedRun prints the failures summary of a run that returned err, cobra skipping post-run
+// hooks after a failed RunE. The exit code of the --fail-on policy takes precedence over the one of err.
+func summarizeFailedRun(cmd *cobra.Command, err error) error {
+	if failuresSummarized {
+		return err
+	}
+	exitError := &ExitError{}
+	if summaryErr := printFailuresSummary(cmd, nil); errors.As(summaryErr, &exitError) {
+		return &ExitError{Code: exitError.Code, Err: fmt.Errorf("%w, %s", err, exitError.Err)}
+	}
+	return err
+}
+
+func sortedResourceTypes(failures map[string][]terraformutils.ResourceFailure) []string {
+	keys := make([]string, 0, len(failures))
+	for key := range failures {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
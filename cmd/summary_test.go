@@ -0,0 +1,51 @@
+// Copyright 2018 The Terraformer Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/terraformer/terraformutils"
+	"github.com/spf13/cobra"
+)
+
+func TestSummarizeFailedRun(t *testing.T) {
+	defer func(report *terraformutils.Report) {
+		runReport = report
+		failuresSummarized = false
+	}(runReport)
+	runReport = terraformutils.NewReport()
+	runReport.Service("aws", "eu-west-1", "vpc").Error = "listing interrupted"
+
+	options := ImportOptions{}
+	cmd := &cobra.Command{Use: "aws"}
+	baseProviderFlags(cmd.Flags(), &options, "vpc", "")
+	if err := cmd.Flags().Parse([]string{"--fail-on=service"}); err != nil {
+		t.Fatal(err)
+	}
+
+	runErr := fmt.Errorf("import stopped: %w", context.Canceled)
+	err := summarizeFailedRun(cmd, runErr)
+	exitError := &ExitError{}
+	if !errors.As(err, &exitError) || exitError.Code != FailedServicesExitCode || !errors.Is(err, runErr) {
+		t.Errorf("unexpected error %v", err)
+	}
+	if err := summarizeFailedRun(cmd, runErr); err != runErr {
+		t.Errorf("expected failures to be summarized once, got %v", err)
+	}
+}
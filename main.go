@@ -15,6 +15,7 @@
 package main
 
 import (
+	"errors"
 	"io"
 	"log"
 	"os"
@@ -38,6 +39,10 @@ func main() {
 	log.SetOutput(TerraformerWriter{})
 	if err := cmd.Execute(); err != nil {
 		log.Println(err)
+		exitError := &cmd.ExitError{}
+		if errors.As(err, &exitError) {
+			os.Exit(exitError.Code)
+		}
 		os.Exit(1)
 	}
 }
@@ -15,8 +15,6 @@
 package aws
 
 import (
-	"context"
-
 	"github.com/GoogleCloudPlatform/terraformer/terraformutils"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/accessanalyzer"
@@ -36,7 +34,7 @@ func (g *AccessAnalyzerGenerator) InitResources() error {
 	svc := accessanalyzer.New(config)
 	p := accessanalyzer.NewListAnalyzersPaginator(svc.ListAnalyzersRequest(&accessanalyzer.ListAnalyzersInput{}))
 	var resources []terraformutils.Resource
-	for p.Next(context.Background()) {
+	for p.Next(g.GetContext()) {
 		for _, analyzer := range p.CurrentPage().Analyzers {
 			resourceName := aws.StringValue(analyzer.Name)
 			resources = append(resources, terraformutils.NewSimpleResource(
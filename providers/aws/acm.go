@@ -15,7 +15,6 @@
 package aws
 
 import (
-	"context"
 	"log"
 	"strings"
 
@@ -36,7 +35,7 @@ type ACMGenerator struct {
 func (g *ACMGenerator) createCertificatesResources(svc *acm.Client) []terraformutils.Resource {
 	resources := []terraformutils.Resource{}
 	p := acm.NewListCertificatesPaginator(svc.ListCertificatesRequest(&acm.ListCertificatesInput{}))
-	for p.Next(context.Background()) {
+	for p.Next(g.GetContext()) {
 		for _, cert := range p.CurrentPage().CertificateSummaryList {
 			certArn := aws.StringValue(cert.CertificateArn)
 			certID := extractCertificateUUID(certArn)
@@ -15,7 +15,6 @@
 package aws
 
 import (
-	"context"
 	"fmt"
 	"log"
 
@@ -33,7 +32,7 @@ type AlbGenerator struct {
 
 func (g *AlbGenerator) loadLB(svc *elasticloadbalancingv2.Client) error {
 	p := elasticloadbalancingv2.NewDescribeLoadBalancersPaginator(svc.DescribeLoadBalancersRequest(&elasticloadbalancingv2.DescribeLoadBalancersInput{}))
-	for p.Next(context.Background()) {
+	for p.Next(g.GetContext()) {
 		for _, lb := range p.CurrentPage().LoadBalancers {
 			resourceName := aws.StringValue(lb.LoadBalancerName)
 			g.Resources = append(g.Resources, terraformutils.NewSimpleResource(
@@ -54,7 +53,7 @@ func (g *AlbGenerator) loadLB(svc *elasticloadbalancingv2.Client) error {
 
 func (g *AlbGenerator) loadLBListener(svc *elasticloadbalancingv2.Client, loadBalancerArn *string) error {
 	p := elasticloadbalancingv2.NewDescribeListenersPaginator(svc.DescribeListenersRequest(&elasticloadbalancingv2.DescribeListenersInput{LoadBalancerArn: loadBalancerArn}))
-	for p.Next(context.Background()) {
+	for p.Next(g.GetContext()) {
 		for _, ls := range p.CurrentPage().Listeners {
 			resourceName := aws.StringValue(ls.ListenerArn)
 			g.Resources = append(g.Resources, terraformutils.NewSimpleResource(
@@ -84,7 +83,7 @@ func (g *AlbGenerator) loadLBListenerRule(svc *elasticloadbalancingv2.Client, li
 			ListenerArn: listenerArn,
 			Marker:      marker,
 			PageSize:    aws.Int64(400)},
-		).Send(context.Background())
+		).Send(g.GetContext())
 		if err != nil {
 			return err
 		}
@@ -111,7 +110,7 @@ func (g *AlbGenerator) loadLBListenerRule(svc *elasticloadbalancingv2.Client, li
 func (g *AlbGenerator) loadLBListenerCertificate(svc *elasticloadbalancingv2.Client, loadBalancer *elasticloadbalancingv2.Listener) error {
 	lcs, err := svc.DescribeListenerCertificatesRequest(&elasticloadbalancingv2.DescribeListenerCertificatesInput{
 		ListenerArn: loadBalancer.ListenerArn,
-	}).Send(context.Background())
+	}).Send(g.GetContext())
 	if err != nil {
 		return err
 	}
@@ -138,7 +137,7 @@ func (g *AlbGenerator) loadLBListenerCertificate(svc *elasticloadbalancingv2.Cli
 
 func (g *AlbGenerator) loadLBTargetGroup(svc *elasticloadbalancingv2.Client) error {
 	p := elasticloadbalancingv2.NewDescribeTargetGroupsPaginator(svc.DescribeTargetGroupsRequest(&elasticloadbalancingv2.DescribeTargetGroupsInput{}))
-	for p.Next(context.Background()) {
+	for p.Next(g.GetContext()) {
 		for _, tg := range p.CurrentPage().TargetGroups {
 			resourceName := aws.StringValue(tg.TargetGroupName)
 			g.Resources = append(g.Resources, terraformutils.NewSimpleResource(
@@ -160,7 +159,7 @@ func (g *AlbGenerator) loadLBTargetGroup(svc *elasticloadbalancingv2.Client) err
 func (g *AlbGenerator) loadTargetGroupTargets(svc *elasticloadbalancingv2.Client, targetGroupArn *string) error {
 	targetHealths, err := svc.DescribeTargetHealthRequest(&elasticloadbalancingv2.DescribeTargetHealthInput{
 		TargetGroupArn: targetGroupArn,
-	}).Send(context.Background())
+	}).Send(g.GetContext())
 	if err != nil {
 		return err
 	}
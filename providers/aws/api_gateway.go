@@ -15,7 +15,6 @@
 package aws
 
 import (
-	"context"
 	"log"
 	"strings"
 
@@ -53,7 +52,7 @@ func (g *APIGatewayGenerator) InitResources() error {
 
 func (g *APIGatewayGenerator) loadRestApis(svc *apigateway.Client) error {
 	p := apigateway.NewGetRestApisPaginator(svc.GetRestApisRequest(&apigateway.GetRestApisInput{}))
-	for p.Next(context.Background()) {
+	for p.Next(g.GetContext()) {
 		for _, restAPI := range p.CurrentPage().Items {
 			if g.shouldFilterRestAPI(restAPI.Tags) {
 				continue
@@ -103,7 +102,7 @@ func (g *APIGatewayGenerator) shouldFilterRestAPI(tags map[string]string) bool {
 func (g *APIGatewayGenerator) loadStages(svc *apigateway.Client, restAPIID *string) error {
 	output, err := svc.GetStagesRequest(&apigateway.GetStagesInput{
 		RestApiId: restAPIID,
-	}).Send(context.Background())
+	}).Send(g.GetContext())
 	if err != nil {
 		return err
 	}
@@ -129,7 +128,7 @@ func (g *APIGatewayGenerator) loadResources(svc *apigateway.Client, restAPIID *s
 	p := apigateway.NewGetResourcesPaginator(svc.GetResourcesRequest(&apigateway.GetResourcesInput{
 		RestApiId: restAPIID,
 	}))
-	for p.Next(context.Background()) {
+	for p.Next(g.GetContext()) {
 		for _, resource := range p.CurrentPage().Items {
 			resourceID := *resource.Id
 			g.Resources = append(g.Resources, terraformutils.NewResource(
@@ -159,7 +158,7 @@ func (g *APIGatewayGenerator) loadModels(svc *apigateway.Client, restAPIID *stri
 	p := apigateway.NewGetModelsPaginator(svc.GetModelsRequest(&apigateway.GetModelsInput{
 		RestApiId: restAPIID,
 	}))
-	for p.Next(context.Background()) {
+	for p.Next(g.GetContext()) {
 		for _, model := range p.CurrentPage().Items {
 			resourceID := *model.Id
 			g.Resources = append(g.Resources, terraformutils.NewResource(
@@ -208,7 +207,7 @@ func (g *APIGatewayGenerator) loadResourceMethods(svc *apigateway.Client, restAP
 			HttpMethod: &httpMethod,
 			ResourceId: resource.Id,
 			RestApiId:  restAPIID,
-		}).Send(context.Background())
+		}).Send(g.GetContext())
 		if err != nil {
 			return err
 		}
@@ -233,7 +232,7 @@ func (g *APIGatewayGenerator) loadResourceMethods(svc *apigateway.Client, restAP
 				HttpMethod: &httpMethod,
 				ResourceId: resource.Id,
 				RestApiId:  restAPIID,
-			}).Send(context.Background())
+			}).Send(g.GetContext())
 			if err != nil {
 				return err
 			}
@@ -284,7 +283,7 @@ func (g *APIGatewayGenerator) loadResponses(svc *apigateway.Client, restAPIID *s
 		response, err := svc.GetGatewayResponsesRequest(&apigateway.GetGatewayResponsesInput{
 			RestApiId: restAPIID,
 			Position:  position,
-		}).Send(context.Background())
+		}).Send(g.GetContext())
 		if err != nil {
 			return err
 		}
@@ -321,7 +320,7 @@ func (g *APIGatewayGenerator) loadDocumentationParts(svc *apigateway.Client, res
 		response, err := svc.GetDocumentationPartsRequest(&apigateway.GetDocumentationPartsInput{
 			RestApiId: restAPIID,
 			Position:  position,
-		}).Send(context.Background())
+		}).Send(g.GetContext())
 		if err != nil {
 			return err
 		}
@@ -349,7 +348,7 @@ func (g *APIGatewayGenerator) loadAuthorizers(svc *apigateway.Client, restAPIID
 		response, err := svc.GetAuthorizersRequest(&apigateway.GetAuthorizersInput{
 			RestApiId: restAPIID,
 			Position:  position,
-		}).Send(context.Background())
+		}).Send(g.GetContext())
 		if err != nil {
 			return err
 		}
@@ -377,7 +376,7 @@ func (g *APIGatewayGenerator) loadAuthorizers(svc *apigateway.Client, restAPIID
 
 func (g *APIGatewayGenerator) loadVpcLinks(svc *apigateway.Client) error {
 	p := apigateway.NewGetVpcLinksPaginator(svc.GetVpcLinksRequest(&apigateway.GetVpcLinksInput{}))
-	for p.Next(context.Background()) {
+	for p.Next(g.GetContext()) {
 		for _, vpcLink := range p.CurrentPage().Items {
 			g.Resources = append(g.Resources, terraformutils.NewSimpleResource(
 				*vpcLink.Id,
@@ -392,7 +391,7 @@ func (g *APIGatewayGenerator) loadVpcLinks(svc *apigateway.Client) error {
 
 func (g *APIGatewayGenerator) loadUsagePlans(svc *apigateway.Client) error {
 	p := apigateway.NewGetUsagePlansPaginator(svc.GetUsagePlansRequest(&apigateway.GetUsagePlansInput{}))
-	for p.Next(context.Background()) {
+	for p.Next(g.GetContext()) {
 		for _, usagePlan := range p.CurrentPage().Items {
 			g.Resources = append(g.Resources, terraformutils.NewSimpleResource(
 				*usagePlan.Id,
@@ -1,8 +1,6 @@
 package aws
 
 import (
-	"context"
-
 	"github.com/GoogleCloudPlatform/terraformer/terraformutils"
 	"github.com/aws/aws-sdk-go-v2/service/appsync"
 )
@@ -23,7 +21,7 @@ func (g *AppSyncGenerator) InitResources() error {
 	for {
 		apis, err := svc.ListGraphqlApisRequest(&appsync.ListGraphqlApisInput{
 			NextToken: nextToken,
-		}).Send(context.Background())
+		}).Send(g.GetContext())
 		if err != nil {
 			return err
 		}
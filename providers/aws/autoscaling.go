@@ -15,8 +15,6 @@
 package aws
 
 import (
-	"context"
-
 	"github.com/GoogleCloudPlatform/terraformer/terraformutils"
 
 	"github.com/aws/aws-sdk-go-v2/service/autoscaling"
@@ -33,7 +31,7 @@ type AutoScalingGenerator struct {
 
 func (g *AutoScalingGenerator) loadAutoScalingGroups(svc *autoscaling.Client) error {
 	p := autoscaling.NewDescribeAutoScalingGroupsPaginator(svc.DescribeAutoScalingGroupsRequest(&autoscaling.DescribeAutoScalingGroupsInput{}))
-	for p.Next(context.Background()) {
+	for p.Next(g.GetContext()) {
 		for _, asg := range p.CurrentPage().AutoScalingGroups {
 			resourceName := aws.StringValue(asg.AutoScalingGroupName)
 			g.Resources = append(g.Resources, terraformutils.NewResource(
@@ -56,7 +54,7 @@ func (g *AutoScalingGenerator) loadAutoScalingGroups(svc *autoscaling.Client) er
 
 func (g *AutoScalingGenerator) loadLaunchConfigurations(svc *autoscaling.Client) error {
 	p := autoscaling.NewDescribeLaunchConfigurationsPaginator(svc.DescribeLaunchConfigurationsRequest(&autoscaling.DescribeLaunchConfigurationsInput{}))
-	for p.Next(context.Background()) {
+	for p.Next(g.GetContext()) {
 		for _, lc := range p.CurrentPage().LaunchConfigurations {
 			resourceName := aws.StringValue(lc.LaunchConfigurationName)
 			attributes := map[string]string{}
@@ -82,7 +80,7 @@ func (g *AutoScalingGenerator) loadLaunchTemplates(config aws.Config) error {
 	ec2svc := ec2.New(config)
 
 	p := ec2.NewDescribeLaunchTemplatesPaginator(ec2svc.DescribeLaunchTemplatesRequest(&ec2.DescribeLaunchTemplatesInput{}))
-	for p.Next(context.Background()) {
+	for p.Next(g.GetContext()) {
 		for _, lt := range p.CurrentPage().LaunchTemplates {
 			g.Resources = append(g.Resources, terraformutils.NewSimpleResource(
 				aws.StringValue(lt.LaunchTemplateId),
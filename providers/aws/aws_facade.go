@@ -1,6 +1,7 @@
 package aws
 
 import (
+	"context"
 	"strings"
 
 	"github.com/GoogleCloudPlatform/terraformer/terraformutils"
@@ -9,7 +10,8 @@ import (
 
 type AwsFacade struct { //nolint
 	AWSService
-	service terraformutils.ServiceGenerator
+	service      terraformutils.ServiceGenerator
+	ignoredError error
 }
 
 func (s *AwsFacade) SetProviderName(providerName string) {
@@ -39,6 +41,14 @@ func (s *AwsFacade) InitialCleanup() {
 	s.service.InitialCleanup()
 }
 
+func (s *AwsFacade) SetManagedResources(managed *terraformutils.ManagedResources) {
+	s.service.SetManagedResources(managed)
+}
+
+func (s *AwsFacade) SetContext(ctx context.Context) {
+	s.service.SetContext(ctx)
+}
+
 func (s *AwsFacade) PostRefreshCleanup() {
 	s.service.PostRefreshCleanup()
 }
@@ -66,11 +76,17 @@ func (s *AwsFacade) InitResources() error {
 	if strings.Contains(message, "no such host") || strings.Contains(message, "i/o timeout") ||
 		strings.Contains(message, "x509: certificate is valid for") ||
 		strings.Contains(message, "Unavailable Operation") { // skip not available AWS services
+		s.ignoredError = err
 		return nil
 	}
 	return err
 }
 
+// IgnoredError returns the error of a service not available in the region, skipped by InitResources
+func (s *AwsFacade) IgnoredError() error {
+	return s.ignoredError
+}
+
 func (s *AwsFacade) PostConvertHook() error {
 	return s.service.PostConvertHook()
 }
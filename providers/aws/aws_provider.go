@@ -15,6 +15,7 @@
 package aws
 
 import (
+	"log"
 	"os"
 	"strconv"
 
@@ -203,6 +204,45 @@ func (p *AWSProvider) Init(args []string) error {
 	return nil
 }
 
+// GetKnownValues names the account and region found in ARNs and attributes of generated resources
+func (p *AWSProvider) GetKnownValues() map[string]string {
+	values := map[string]string{}
+	if p.region != GlobalRegion && p.region != NoRegion {
+		values["region"] = p.region
+	}
+	service := &AWSService{}
+	service.SetArgs(map[string]interface{}{
+		"region":  p.region,
+		"profile": p.profile,
+	})
+	config, err := service.generateConfig()
+	if err == nil {
+		var account *string
+		account, err = service.getAccountNumber(config)
+		if err == nil {
+			values["account_id"] = *account
+		}
+	}
+	if err != nil {
+		log.Println("failed to read AWS account number:", err)
+	}
+	return values
+}
+
+func (p *AWSProvider) GetRegion() string {
+	return p.region
+}
+
+// GetRateLimits slows down KMS, whose read APIs are throttled far below other services
+func (p *AWSProvider) GetRateLimits() terraformutils.RateLimits {
+	return terraformutils.RateLimits{
+		ResourceTypes: map[string]float64{
+			"aws_kms_key":   5,
+			"aws_kms_alias": 5,
+		},
+	}
+}
+
 func (p *AWSProvider) GetName() string {
 	return "aws"
 }
@@ -15,9 +15,9 @@
 package aws
 
 import (
-	"context"
 	"os"
 	"regexp"
+	"sync"
 
 	"github.com/aws/aws-sdk-go-v2/service/sts"
 
@@ -34,7 +34,13 @@ type AWSService struct { //nolint
 
 var awsVariable = regexp.MustCompile(`(\${[0-9A-Za-z:]+})`)
 
+// configMutex serializes config loading of services listed concurrently: credentials exported
+// to AWS_* variables are read by the next loads and MFA tokens are asked on stdin
+var configMutex sync.Mutex
+
 func (s *AWSService) generateConfig() (aws.Config, error) {
+	configMutex.Lock()
+	defer configMutex.Unlock()
 	config, e := s.buildBaseConfig()
 
 	if e != nil {
@@ -44,7 +50,7 @@ func (s *AWSService) generateConfig() (aws.Config, error) {
 		config.LogLevel = aws.LogDebugWithHTTPBody
 	}
 
-	creds, e := config.Credentials.Retrieve(context.Background())
+	creds, e := config.Credentials.Retrieve(s.GetContext())
 
 	if e != nil {
 		return config, e
@@ -80,7 +86,7 @@ func (*AWSService) escapeAwsInterpolation(str string) string {
 
 func (s *AWSService) getAccountNumber(config aws.Config) (*string, error) {
 	stsSvc := sts.New(config)
-	identity, err := stsSvc.GetCallerIdentityRequest(&sts.GetCallerIdentityInput{}).Send(context.Background())
+	identity, err := stsSvc.GetCallerIdentityRequest(&sts.GetCallerIdentityInput{}).Send(s.GetContext())
 	if err != nil {
 		return nil, err
 	}
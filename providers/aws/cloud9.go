@@ -15,8 +15,6 @@
 package aws
 
 import (
-	"context"
-
 	"github.com/GoogleCloudPlatform/terraformer/terraformutils"
 	"github.com/aws/aws-sdk-go-v2/service/cloud9"
 )
@@ -33,14 +31,14 @@ func (g *Cloud9Generator) InitResources() error {
 		return e
 	}
 	svc := cloud9.New(config)
-	output, err := svc.ListEnvironmentsRequest(&cloud9.ListEnvironmentsInput{}).Send(context.Background())
+	output, err := svc.ListEnvironmentsRequest(&cloud9.ListEnvironmentsInput{}).Send(g.GetContext())
 	if err != nil {
 		return err
 	}
 	for _, environmentID := range output.EnvironmentIds {
 		details, _ := svc.DescribeEnvironmentStatusRequest(&cloud9.DescribeEnvironmentStatusInput{
 			EnvironmentId: &environmentID,
-		}).Send(context.Background())
+		}).Send(g.GetContext())
 		if details.Status == cloud9.EnvironmentStatusError ||
 			details.Status == cloud9.EnvironmentStatusDeleting {
 			continue
@@ -15,8 +15,6 @@
 package aws
 
 import (
-	"context"
-
 	"github.com/GoogleCloudPlatform/terraformer/terraformutils"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/cloudfront"
@@ -35,7 +33,7 @@ func (g *CloudFrontGenerator) InitResources() error {
 	}
 	svc := cloudfront.New(config)
 	p := cloudfront.NewListDistributionsPaginator(svc.ListDistributionsRequest(&cloudfront.ListDistributionsInput{}))
-	for p.Next(context.Background()) {
+	for p.Next(g.GetContext()) {
 		for _, distribution := range p.CurrentPage().DistributionList.Items {
 			r := terraformutils.NewResource(
 				aws.StringValue(distribution.Id),
@@ -15,8 +15,6 @@
 package aws
 
 import (
-	"context"
-
 	"github.com/GoogleCloudPlatform/terraformer/terraformutils"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/cloudformation"
@@ -35,7 +33,7 @@ func (g *CloudFormationGenerator) InitResources() error {
 	}
 	svc := cloudformation.New(config)
 	p := cloudformation.NewListStacksPaginator(svc.ListStacksRequest(&cloudformation.ListStacksInput{}))
-	for p.Next(context.Background()) {
+	for p.Next(g.GetContext()) {
 		for _, stackSummary := range p.CurrentPage().StackSummaries {
 			if stackSummary.StackStatus == cloudformation.StackStatusDeleteComplete {
 				continue
@@ -52,7 +50,7 @@ func (g *CloudFormationGenerator) InitResources() error {
 	if err := p.Err(); err != nil {
 		return err
 	}
-	stackSets, err := svc.ListStackSetsRequest(&cloudformation.ListStackSetsInput{}).Send(context.Background())
+	stackSets, err := svc.ListStackSetsRequest(&cloudformation.ListStackSetsInput{}).Send(g.GetContext())
 	if err != nil {
 		return err
 	}
@@ -70,7 +68,7 @@ func (g *CloudFormationGenerator) InitResources() error {
 
 		stackSetInstances, err := svc.ListStackInstancesRequest(&cloudformation.ListStackInstancesInput{
 			StackSetName: stackSetSummary.StackSetName,
-		}).Send(context.Background())
+		}).Send(g.GetContext())
 		if err != nil {
 			return err
 		}
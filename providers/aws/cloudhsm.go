@@ -15,7 +15,6 @@
 package aws
 
 import (
-	"context"
 	"github.com/aws/aws-sdk-go-v2/service/cloudhsmv2"
 
 	"github.com/GoogleCloudPlatform/terraformer/terraformutils"
@@ -36,7 +35,7 @@ func (g *CloudHsmGenerator) InitResources() error {
 	svc := cloudhsmv2.New(config)
 
 	p := cloudhsmv2.NewDescribeClustersPaginator(svc.DescribeClustersRequest(&cloudhsmv2.DescribeClustersInput{}))
-	for p.Next(context.Background()) {
+	for p.Next(g.GetContext()) {
 		for _, cluster := range p.CurrentPage().Clusters {
 			g.Resources = append(g.Resources, terraformutils.NewSimpleResource(
 				aws.StringValue(cluster.ClusterId),
@@ -15,8 +15,6 @@
 package aws
 
 import (
-	"context"
-
 	"github.com/GoogleCloudPlatform/terraformer/terraformutils"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/cloudtrail"
@@ -48,7 +46,7 @@ func (g *CloudTrailGenerator) InitResources() error {
 		return e
 	}
 	svc := cloudtrail.New(config)
-	output, err := svc.DescribeTrailsRequest(&cloudtrail.DescribeTrailsInput{}).Send(context.Background())
+	output, err := svc.DescribeTrailsRequest(&cloudtrail.DescribeTrailsInput{}).Send(g.GetContext())
 	if err != nil {
 		return err
 	}
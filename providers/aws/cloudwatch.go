@@ -15,8 +15,6 @@
 package aws
 
 import (
-	"context"
-
 	"github.com/GoogleCloudPlatform/terraformer/terraformutils"
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatchevents"
@@ -58,7 +56,7 @@ func (g *CloudWatchGenerator) createMetricAlarms(cloudwatchSvc *cloudwatch.Clien
 	for {
 		output, err := cloudwatchSvc.DescribeAlarmsRequest(&cloudwatch.DescribeAlarmsInput{
 			NextToken: nextToken,
-		}).Send(context.Background())
+		}).Send(g.GetContext())
 		if err != nil {
 			return err
 		}
@@ -83,7 +81,7 @@ func (g *CloudWatchGenerator) createDashboards(cloudwatchSvc *cloudwatch.Client)
 	for {
 		output, err := cloudwatchSvc.ListDashboardsRequest(&cloudwatch.ListDashboardsInput{
 			NextToken: nextToken,
-		}).Send(context.Background())
+		}).Send(g.GetContext())
 		if err != nil {
 			return err
 		}
@@ -108,7 +106,7 @@ func (g *CloudWatchGenerator) createRules(cloudwatcheventsSvc *cloudwatchevents.
 	for {
 		output, err := cloudwatcheventsSvc.ListRulesRequest(&cloudwatchevents.ListRulesInput{
 			NextToken: listRulesNextToken,
-		}).Send(context.Background())
+		}).Send(g.GetContext())
 		if err != nil {
 			return err
 		}
@@ -125,7 +123,7 @@ func (g *CloudWatchGenerator) createRules(cloudwatcheventsSvc *cloudwatchevents.
 				targetResponse, err := cloudwatcheventsSvc.ListTargetsByRuleRequest(&cloudwatchevents.ListTargetsByRuleInput{
 					Rule:      rule.Name,
 					NextToken: listTargetsNextToken,
-				}).Send(context.Background())
+				}).Send(g.GetContext())
 				if err != nil {
 					return err
 				}
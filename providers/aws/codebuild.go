@@ -15,8 +15,6 @@
 package aws
 
 import (
-	"context"
-
 	"github.com/GoogleCloudPlatform/terraformer/terraformutils"
 	"github.com/aws/aws-sdk-go-v2/service/codebuild"
 )
@@ -46,7 +44,7 @@ func (g *CodeBuildGenerator) InitResources() error {
 		return e
 	}
 	svc := codebuild.New(config)
-	output, err := svc.ListProjectsRequest(&codebuild.ListProjectsInput{}).Send(context.Background())
+	output, err := svc.ListProjectsRequest(&codebuild.ListProjectsInput{}).Send(g.GetContext())
 	if err != nil {
 		return err
 	}
@@ -15,8 +15,6 @@
 package aws
 
 import (
-	"context"
-
 	"github.com/GoogleCloudPlatform/terraformer/terraformutils"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/codecommit"
@@ -36,7 +34,7 @@ func (g *CodeCommitGenerator) InitResources() error {
 	svc := codecommit.New(config)
 	p := codecommit.NewListRepositoriesPaginator(svc.ListRepositoriesRequest(&codecommit.ListRepositoriesInput{}))
 	var resources []terraformutils.Resource
-	for p.Next(context.Background()) {
+	for p.Next(g.GetContext()) {
 		for _, repository := range p.CurrentPage().Repositories {
 			resourceName := aws.StringValue(repository.RepositoryName)
 			resources = append(resources, terraformutils.NewSimpleResource(
@@ -15,7 +15,6 @@
 package aws
 
 import (
-	"context"
 	"fmt"
 
 	"github.com/GoogleCloudPlatform/terraformer/terraformutils"
@@ -36,7 +35,7 @@ func (g *CodeDeployGenerator) InitResources() error {
 	svc := codedeploy.New(config)
 	p := codedeploy.NewListApplicationsPaginator(svc.ListApplicationsRequest(&codedeploy.ListApplicationsInput{}))
 	var resources []terraformutils.Resource
-	for p.Next(context.Background()) {
+	for p.Next(g.GetContext()) {
 		for _, application := range p.CurrentPage().Applications {
 			resources = append(resources, terraformutils.NewSimpleResource(
 				fmt.Sprintf(":%s", application),
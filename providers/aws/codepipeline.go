@@ -15,8 +15,6 @@
 package aws
 
 import (
-	"context"
-
 	"github.com/GoogleCloudPlatform/terraformer/terraformutils"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/codepipeline"
@@ -30,7 +28,7 @@ type CodePipelineGenerator struct {
 
 func (g *CodePipelineGenerator) loadPipelines(svc *codepipeline.Client) error {
 	p := codepipeline.NewListPipelinesPaginator(svc.ListPipelinesRequest(&codepipeline.ListPipelinesInput{}))
-	for p.Next(context.Background()) {
+	for p.Next(g.GetContext()) {
 		for _, pipeline := range p.CurrentPage().Pipelines {
 			resourceName := aws.StringValue(pipeline.Name)
 			g.Resources = append(g.Resources, terraformutils.NewSimpleResource(
@@ -46,7 +44,7 @@ func (g *CodePipelineGenerator) loadPipelines(svc *codepipeline.Client) error {
 
 func (g *CodePipelineGenerator) loadWebhooks(svc *codepipeline.Client) error {
 	p := codepipeline.NewListWebhooksPaginator(svc.ListWebhooksRequest(&codepipeline.ListWebhooksInput{}))
-	for p.Next(context.Background()) {
+	for p.Next(g.GetContext()) {
 		for _, webhook := range p.CurrentPage().Webhooks {
 			resourceArn := aws.StringValue(webhook.Arn)
 			g.Resources = append(g.Resources, terraformutils.NewSimpleResource(
@@ -1,8 +1,6 @@
 package aws
 
 import (
-	"context"
-
 	"github.com/GoogleCloudPlatform/terraformer/terraformutils"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/cognitoidentity"
@@ -21,7 +19,7 @@ func (g *CognitoGenerator) loadIdentityPools(svc *cognitoidentity.Client) error
 		pools, err := svc.ListIdentityPoolsRequest(&cognitoidentity.ListIdentityPoolsInput{
 			NextToken:  nextToken,
 			MaxResults: aws.Int64(CognitoMaxResults),
-		}).Send(context.Background())
+		}).Send(g.GetContext())
 		if err != nil {
 			return err
 		}
@@ -50,7 +48,7 @@ func (g *CognitoGenerator) loadUserPools(svc *cognitoidentityprovider.Client) er
 	req := svc.ListUserPoolsRequest(&cognitoidentityprovider.ListUserPoolsInput{MaxResults: aws.Int64(CognitoMaxResults)})
 	p := cognitoidentityprovider.NewListUserPoolsPaginator(req)
 
-	for p.Next(context.Background()) {
+	for p.Next(g.GetContext()) {
 		page := p.CurrentPage()
 		for _, pool := range page.UserPools {
 			id := *pool.Id
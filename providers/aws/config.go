@@ -15,8 +15,6 @@
 package aws
 
 import (
-	"context"
-
 	"github.com/GoogleCloudPlatform/terraformer/terraformutils"
 	"github.com/aws/aws-sdk-go-v2/service/configservice"
 )
@@ -48,7 +46,7 @@ func (g *ConfigGenerator) InitResources() error {
 
 func (g *ConfigGenerator) addConfigurationRecorders(svc *configservice.Client) ([]string, error) {
 	configurationRecorders, err := svc.DescribeConfigurationRecordersRequest(
-		&configservice.DescribeConfigurationRecordersInput{}).Send(context.Background())
+		&configservice.DescribeConfigurationRecordersInput{}).Send(g.GetContext())
 
 	if err != nil {
 		return nil, err
@@ -76,7 +74,7 @@ func (g *ConfigGenerator) addConfigRules(svc *configservice.Client, configuratio
 		configRules, err := svc.DescribeConfigRulesRequest(
 			&configservice.DescribeConfigRulesInput{
 				NextToken: nextToken,
-			}).Send(context.Background())
+			}).Send(g.GetContext())
 
 		if err != nil {
 			return err
@@ -105,7 +103,7 @@ func (g *ConfigGenerator) addConfigRules(svc *configservice.Client, configuratio
 
 func (g *ConfigGenerator) addDeliveryChannels(svc *configservice.Client, configurationRecorderRefs []string) error {
 	deliveryChannels, err := svc.DescribeDeliveryChannelsRequest(
-		&configservice.DescribeDeliveryChannelsInput{}).Send(context.Background())
+		&configservice.DescribeDeliveryChannelsInput{}).Send(g.GetContext())
 
 	if err != nil {
 		return err
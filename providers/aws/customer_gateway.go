@@ -15,8 +15,6 @@
 package aws
 
 import (
-	"context"
-
 	"github.com/GoogleCloudPlatform/terraformer/terraformutils"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -52,7 +50,7 @@ func (g *CustomerGatewayGenerator) InitResources() error {
 		return e
 	}
 	svc := ec2.New(config)
-	cgws, err := svc.DescribeCustomerGatewaysRequest(&ec2.DescribeCustomerGatewaysInput{}).Send(context.Background())
+	cgws, err := svc.DescribeCustomerGatewaysRequest(&ec2.DescribeCustomerGatewaysInput{}).Send(g.GetContext())
 	if err != nil {
 		return err
 	}
@@ -15,8 +15,6 @@
 package aws
 
 import (
-	"context"
-
 	"github.com/GoogleCloudPlatform/terraformer/terraformutils"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/datapipeline"
@@ -36,7 +34,7 @@ func (g *DataPipelineGenerator) InitResources() error {
 	svc := datapipeline.New(config)
 	p := datapipeline.NewListPipelinesPaginator(svc.ListPipelinesRequest(&datapipeline.ListPipelinesInput{}))
 	var resources []terraformutils.Resource
-	for p.Next(context.Background()) {
+	for p.Next(g.GetContext()) {
 		for _, pipeline := range p.CurrentPage().PipelineIdList {
 			pipelineID := aws.StringValue(pipeline.Id)
 			pipelineName := aws.StringValue(pipeline.Name)
@@ -15,8 +15,6 @@
 package aws
 
 import (
-	"context"
-
 	"github.com/GoogleCloudPlatform/terraformer/terraformutils"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/devicefarm"
@@ -36,7 +34,7 @@ func (g *DeviceFarmGenerator) InitResources() error {
 	svc := devicefarm.New(config)
 	p := devicefarm.NewListProjectsPaginator(svc.ListProjectsRequest(&devicefarm.ListProjectsInput{}))
 	var resources []terraformutils.Resource
-	for p.Next(context.Background()) {
+	for p.Next(g.GetContext()) {
 		for _, project := range p.CurrentPage().Projects {
 			projectArn := aws.StringValue(project.Arn)
 			projectName := aws.StringValue(project.Name)
@@ -15,8 +15,6 @@
 package aws
 
 import (
-	"context"
-
 	"github.com/GoogleCloudPlatform/terraformer/terraformutils"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 )
@@ -34,7 +32,7 @@ func (g *DynamoDbGenerator) InitResources() error {
 	}
 	svc := dynamodb.New(config)
 	p := dynamodb.NewListTablesPaginator(svc.ListTablesRequest(&dynamodb.ListTablesInput{}))
-	for p.Next(context.Background()) {
+	for p.Next(g.GetContext()) {
 		for _, tableName := range p.CurrentPage().TableNames {
 			g.Resources = append(g.Resources, terraformutils.NewSimpleResource(
 				tableName,
@@ -15,7 +15,6 @@
 package aws
 
 import (
-	"context"
 	"fmt"
 	"strings"
 
@@ -54,14 +53,14 @@ func (g *EbsGenerator) InitResources() error {
 	p := ec2.NewDescribeVolumesPaginator(svc.DescribeVolumesRequest(&ec2.DescribeVolumesInput{
 		Filters: filters,
 	}))
-	for p.Next(context.Background()) {
+	for p.Next(g.GetContext()) {
 		for _, volume := range p.CurrentPage().Volumes {
 			isRootDevice := false // Let's leave root device configuration to be done in ec2_instance resources
 
 			for _, attachment := range volume.Attachments {
 				instances, _ := svc.DescribeInstancesRequest(&ec2.DescribeInstancesInput{
 					InstanceIds: []string{aws.StringValue(attachment.InstanceId)},
-				}).Send(context.Background())
+				}).Send(g.GetContext())
 				for _, reservation := range instances.Reservations {
 					for _, instance := range reservation.Instances {
 						if aws.StringValue(instance.RootDeviceName) == aws.StringValue(attachment.Device) {
@@ -15,7 +15,6 @@
 package aws
 
 import (
-	"context"
 	"strings"
 
 	"github.com/GoogleCloudPlatform/terraformer/terraformutils"
@@ -48,7 +47,7 @@ func (g *Ec2Generator) InitResources() error {
 	p := ec2.NewDescribeInstancesPaginator(svc.DescribeInstancesRequest(&ec2.DescribeInstancesInput{
 		Filters: filters,
 	}))
-	for p.Next(context.Background()) {
+	for p.Next(g.GetContext()) {
 		for _, reservation := range p.CurrentPage().Reservations {
 			for _, instance := range reservation.Instances {
 				name := ""
@@ -60,7 +59,7 @@ func (g *Ec2Generator) InitResources() error {
 				attr, err := svc.DescribeInstanceAttributeRequest(&ec2.DescribeInstanceAttributeInput{
 					Attribute:  ec2.InstanceAttributeNameUserData,
 					InstanceId: instance.InstanceId,
-				}).Send(context.Background())
+				}).Send(g.GetContext())
 				userDataBase64 := ""
 				if err == nil && attr.UserData != nil && attr.UserData.Value != nil {
 					userDataBase64 = aws.StringValue(attr.UserData.Value)
@@ -15,8 +15,6 @@
 package aws
 
 import (
-	"context"
-
 	"github.com/GoogleCloudPlatform/terraformer/terraformutils"
 	"github.com/aws/aws-sdk-go-v2/service/ecr"
 )
@@ -35,7 +33,7 @@ func (g *EcrGenerator) InitResources() error {
 	svc := ecr.New(config)
 
 	p := ecr.NewDescribeRepositoriesPaginator(svc.DescribeRepositoriesRequest(&ecr.DescribeRepositoriesInput{}))
-	for p.Next(context.Background()) {
+	for p.Next(g.GetContext()) {
 		for _, repository := range p.CurrentPage().Repositories {
 			g.Resources = append(g.Resources, terraformutils.NewSimpleResource(
 				*repository.RepositoryName,
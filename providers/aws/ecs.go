@@ -15,7 +15,6 @@
 package aws
 
 import (
-	"context"
 	"fmt"
 	"strconv"
 	"strings"
@@ -39,7 +38,7 @@ func (g *EcsGenerator) InitResources() error {
 	svc := ecs.New(config)
 
 	p := ecs.NewListClustersPaginator(svc.ListClustersRequest(&ecs.ListClustersInput{}))
-	for p.Next(context.Background()) {
+	for p.Next(g.GetContext()) {
 		for _, clusterArn := range p.CurrentPage().ClusterArns {
 			arnParts := strings.Split(clusterArn, "/")
 			clusterName := arnParts[len(arnParts)-1]
@@ -55,7 +54,7 @@ func (g *EcsGenerator) InitResources() error {
 			servicePage := ecs.NewListServicesPaginator(svc.ListServicesRequest(&ecs.ListServicesInput{
 				Cluster: aws.String(clusterArn),
 			}))
-			for servicePage.Next(context.Background()) {
+			for servicePage.Next(g.GetContext()) {
 				for _, serviceArn := range servicePage.CurrentPage().ServiceArns {
 					arnParts := strings.Split(serviceArn, "/")
 					serviceName := arnParts[len(arnParts)-1]
@@ -65,7 +64,7 @@ func (g *EcsGenerator) InitResources() error {
 							serviceName,
 						},
 						Cluster: aws.String(clusterArn),
-					}).Send(context.Background())
+					}).Send(g.GetContext())
 					if err != nil {
 						fmt.Println(err.Error())
 						continue
@@ -100,7 +99,7 @@ func (g *EcsGenerator) InitResources() error {
 
 	taskDefinitionsMap := map[string]terraformutils.Resource{}
 	taskDefinitionsPage := ecs.NewListTaskDefinitionsPaginator(svc.ListTaskDefinitionsRequest(&ecs.ListTaskDefinitionsInput{}))
-	for taskDefinitionsPage.Next(context.Background()) {
+	for taskDefinitionsPage.Next(g.GetContext()) {
 		for _, taskDefinitionArn := range taskDefinitionsPage.CurrentPage().TaskDefinitionArns {
 			arnParts := strings.Split(taskDefinitionArn, ":")
 			definitionWithFamily := arnParts[len(arnParts)-2]
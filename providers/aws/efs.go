@@ -15,7 +15,6 @@
 package aws
 
 import (
-	"context"
 	"fmt"
 
 	"github.com/GoogleCloudPlatform/terraformer/terraformutils"
@@ -49,7 +48,7 @@ func (g *EfsGenerator) InitResources() error {
 
 func (g *EfsGenerator) loadFileSystem(svc *efs.Client) error {
 	p := efs.NewDescribeFileSystemsPaginator(svc.DescribeFileSystemsRequest(&efs.DescribeFileSystemsInput{}))
-	for p.Next(context.Background()) {
+	for p.Next(g.GetContext()) {
 		for _, fileSystem := range p.CurrentPage().FileSystems {
 			g.Resources = append(g.Resources, terraformutils.NewSimpleResource(
 				aws.StringValue(fileSystem.FileSystemId),
@@ -60,7 +59,7 @@ func (g *EfsGenerator) loadFileSystem(svc *efs.Client) error {
 
 			targetsResponse, err := svc.DescribeMountTargetsRequest(&efs.DescribeMountTargetsInput{
 				FileSystemId: fileSystem.FileSystemId,
-			}).Send(context.Background())
+			}).Send(g.GetContext())
 			if err != nil {
 				fmt.Println(err.Error())
 				continue
@@ -76,7 +75,7 @@ func (g *EfsGenerator) loadFileSystem(svc *efs.Client) error {
 
 			policyResponse, err := svc.DescribeFileSystemPolicyRequest(&efs.DescribeFileSystemPolicyInput{
 				FileSystemId: fileSystem.FileSystemId,
-			}).Send(context.Background())
+			}).Send(g.GetContext())
 			if err != nil {
 				fmt.Println(err.Error())
 				continue
@@ -102,7 +101,7 @@ POLICY`, escapedPolicy),
 
 func (g *EfsGenerator) loadMountTarget(svc *efs.Client) error {
 	p := efs.NewDescribeFileSystemsPaginator(svc.DescribeFileSystemsRequest(&efs.DescribeFileSystemsInput{}))
-	for p.Next(context.Background()) {
+	for p.Next(g.GetContext()) {
 		for _, fileSystem := range p.CurrentPage().FileSystems {
 			id := aws.StringValue(fileSystem.FileSystemId)
 			g.Resources = append(g.Resources, terraformutils.NewSimpleResource(
@@ -118,7 +117,7 @@ func (g *EfsGenerator) loadMountTarget(svc *efs.Client) error {
 
 func (g *EfsGenerator) loadAccessPoint(svc *efs.Client) error {
 	p := efs.NewDescribeAccessPointsPaginator(svc.DescribeAccessPointsRequest(&efs.DescribeAccessPointsInput{}))
-	for p.Next(context.Background()) {
+	for p.Next(g.GetContext()) {
 		for _, fileSystem := range p.CurrentPage().AccessPoints {
 			id := aws.StringValue(fileSystem.AccessPointId)
 			g.Resources = append(g.Resources, terraformutils.NewSimpleResource(
@@ -15,7 +15,6 @@
 package aws
 
 import (
-	"context"
 	"log"
 
 	"github.com/GoogleCloudPlatform/terraformer/terraformutils"
@@ -32,7 +31,7 @@ type ElasticIPGenerator struct {
 
 func (g *ElasticIPGenerator) createElasticIpsResources(svc *ec2.Client) []terraformutils.Resource {
 	resources := []terraformutils.Resource{}
-	addresses, err := svc.DescribeAddressesRequest(&ec2.DescribeAddressesInput{}).Send(context.Background())
+	addresses, err := svc.DescribeAddressesRequest(&ec2.DescribeAddressesInput{}).Send(g.GetContext())
 
 	if err != nil {
 		log.Println(err)
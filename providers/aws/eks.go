@@ -15,8 +15,6 @@
 package aws
 
 import (
-	"context"
-
 	"github.com/GoogleCloudPlatform/terraformer/terraformutils"
 	"github.com/aws/aws-sdk-go-v2/service/eks"
 )
@@ -34,7 +32,7 @@ func (g *EksGenerator) InitResources() error {
 	}
 	svc := eks.New(config)
 	p := eks.NewListClustersPaginator(svc.ListClustersRequest(&eks.ListClustersInput{}))
-	for p.Next(context.Background()) {
+	for p.Next(g.GetContext()) {
 		for _, clusterName := range p.CurrentPage().Clusters {
 			g.Resources = append(g.Resources, terraformutils.NewSimpleResource(
 				clusterName,
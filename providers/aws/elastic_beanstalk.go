@@ -15,8 +15,6 @@
 package aws
 
 import (
-	"context"
-
 	"github.com/GoogleCloudPlatform/terraformer/terraformutils"
 	"github.com/aws/aws-sdk-go-v2/service/elasticbeanstalk"
 )
@@ -44,7 +42,7 @@ func (g *BeanstalkGenerator) InitResources() error {
 
 func (g *BeanstalkGenerator) addApplications(client *elasticbeanstalk.Client) error {
 	request := client.DescribeApplicationsRequest(&elasticbeanstalk.DescribeApplicationsInput{})
-	response, err := request.Send(context.Background())
+	response, err := request.Send(g.GetContext())
 	if err != nil {
 		return err
 	}
@@ -62,7 +60,7 @@ func (g *BeanstalkGenerator) addApplications(client *elasticbeanstalk.Client) er
 
 func (g *BeanstalkGenerator) addEnvironments(client *elasticbeanstalk.Client) error {
 	request := client.DescribeEnvironmentsRequest(&elasticbeanstalk.DescribeEnvironmentsInput{})
-	response, err := request.Send(context.Background())
+	response, err := request.Send(g.GetContext())
 	if err != nil {
 		return err
 	}
@@ -15,7 +15,6 @@
 package aws
 
 import (
-	"context"
 	"strings"
 
 	"github.com/GoogleCloudPlatform/terraformer/terraformutils"
@@ -33,7 +32,7 @@ type ElastiCacheGenerator struct {
 
 func (g *ElastiCacheGenerator) loadCacheClusters(svc *elasticache.Client) error {
 	p := elasticache.NewDescribeCacheClustersPaginator(svc.DescribeCacheClustersRequest(&elasticache.DescribeCacheClustersInput{}))
-	for p.Next(context.Background()) {
+	for p.Next(g.GetContext()) {
 		for _, cluster := range p.CurrentPage().CacheClusters {
 			resourceName := aws.StringValue(cluster.CacheClusterId)
 			resource := terraformutils.NewSimpleResource(
@@ -76,7 +75,7 @@ func (g *ElastiCacheGenerator) loadCacheClusters(svc *elasticache.Client) error
 
 func (g *ElastiCacheGenerator) loadParameterGroups(svc *elasticache.Client) error {
 	p := elasticache.NewDescribeCacheParameterGroupsPaginator(svc.DescribeCacheParameterGroupsRequest(&elasticache.DescribeCacheParameterGroupsInput{}))
-	for p.Next(context.Background()) {
+	for p.Next(g.GetContext()) {
 		for _, parameterGroup := range p.CurrentPage().CacheParameterGroups {
 			resourceName := aws.StringValue(parameterGroup.CacheParameterGroupName)
 			if strings.Contains(resourceName, ".") {
@@ -96,7 +95,7 @@ func (g *ElastiCacheGenerator) loadParameterGroups(svc *elasticache.Client) erro
 
 func (g *ElastiCacheGenerator) loadSubnetGroups(svc *elasticache.Client) error {
 	p := elasticache.NewDescribeCacheSubnetGroupsPaginator(svc.DescribeCacheSubnetGroupsRequest(&elasticache.DescribeCacheSubnetGroupsInput{}))
-	for p.Next(context.Background()) {
+	for p.Next(g.GetContext()) {
 		for _, subnet := range p.CurrentPage().CacheSubnetGroups {
 			resourceName := aws.StringValue(subnet.CacheSubnetGroupName)
 			g.Resources = append(g.Resources, terraformutils.NewSimpleResource(
@@ -113,7 +112,7 @@ func (g *ElastiCacheGenerator) loadSubnetGroups(svc *elasticache.Client) error {
 
 func (g *ElastiCacheGenerator) loadReplicationGroups(svc *elasticache.Client) error {
 	p := elasticache.NewDescribeReplicationGroupsPaginator(svc.DescribeReplicationGroupsRequest(&elasticache.DescribeReplicationGroupsInput{}))
-	for p.Next(context.Background()) {
+	for p.Next(g.GetContext()) {
 		for _, replicationGroup := range p.CurrentPage().ReplicationGroups {
 			resourceName := aws.StringValue(replicationGroup.ReplicationGroupId)
 			g.Resources = append(g.Resources, terraformutils.NewSimpleResource(
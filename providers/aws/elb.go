@@ -15,8 +15,6 @@
 package aws
 
 import (
-	"context"
-
 	"github.com/GoogleCloudPlatform/terraformer/terraformutils"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancing"
@@ -39,7 +37,7 @@ func (g *ElbGenerator) InitResources() error {
 	}
 	svc := elasticloadbalancing.New(config)
 	p := elasticloadbalancing.NewDescribeLoadBalancersPaginator(svc.DescribeLoadBalancersRequest(&elasticloadbalancing.DescribeLoadBalancersInput{}))
-	for p.Next(context.Background()) {
+	for p.Next(g.GetContext()) {
 		for _, loadBalancer := range p.CurrentPage().LoadBalancerDescriptions {
 			resourceName := aws.StringValue(loadBalancer.LoadBalancerName)
 			resource := terraformutils.NewSimpleResource(
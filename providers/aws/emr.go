@@ -15,8 +15,6 @@
 package aws
 
 import (
-	"context"
-
 	"github.com/GoogleCloudPlatform/terraformer/terraformutils"
 	"github.com/aws/aws-sdk-go-v2/service/emr"
 )
@@ -44,7 +42,7 @@ func (g *EmrGenerator) InitResources() error {
 
 func (g *EmrGenerator) addClusters(client *emr.Client) error {
 	p := emr.NewListClustersPaginator(client.ListClustersRequest(&emr.ListClustersInput{}))
-	for p.Next(context.Background()) {
+	for p.Next(g.GetContext()) {
 		for _, cluster := range p.CurrentPage().Clusters {
 			g.Resources = append(g.Resources, terraformutils.NewSimpleResource(
 				*cluster.Id,
@@ -60,7 +58,7 @@ func (g *EmrGenerator) addClusters(client *emr.Client) error {
 
 func (g *EmrGenerator) addSecurityConfigurations(client *emr.Client) error {
 	p := emr.NewListSecurityConfigurationsPaginator(client.ListSecurityConfigurationsRequest(&emr.ListSecurityConfigurationsInput{}))
-	for p.Next(context.Background()) {
+	for p.Next(g.GetContext()) {
 		for _, securityConfiguration := range p.CurrentPage().SecurityConfigurations {
 			g.Resources = append(g.Resources, terraformutils.NewSimpleResource(
 				*securityConfiguration.Name,
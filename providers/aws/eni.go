@@ -15,8 +15,6 @@
 package aws
 
 import (
-	"context"
-
 	"github.com/GoogleCloudPlatform/terraformer/terraformutils"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -53,7 +51,7 @@ func (g *EniGenerator) InitResources() error {
 	svc := ec2.New(config)
 	p := ec2.NewDescribeNetworkInterfacesPaginator(
 		svc.DescribeNetworkInterfacesRequest(&ec2.DescribeNetworkInterfacesInput{}))
-	for p.Next(context.Background()) {
+	for p.Next(g.GetContext()) {
 		g.Resources = append(g.Resources, g.createResources(p.CurrentPage())...)
 	}
 	return p.Err()
@@ -15,8 +15,6 @@
 package aws
 
 import (
-	"context"
-
 	"github.com/GoogleCloudPlatform/terraformer/terraformutils"
 	"github.com/aws/aws-sdk-go-v2/aws"
 
@@ -36,7 +34,7 @@ func (g *EsGenerator) InitResources() error {
 	}
 	svc := es.New(config)
 
-	domainNames, err := svc.ListDomainNamesRequest(&es.ListDomainNamesInput{}).Send(context.Background())
+	domainNames, err := svc.ListDomainNamesRequest(&es.ListDomainNamesInput{}).Send(g.GetContext())
 	if err != nil {
 		return err
 	}
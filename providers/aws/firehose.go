@@ -15,8 +15,6 @@
 package aws
 
 import (
-	"context"
-
 	"github.com/GoogleCloudPlatform/terraformer/terraformutils"
 	"github.com/aws/aws-sdk-go-v2/service/firehose"
 )
@@ -50,7 +48,7 @@ func (g *FirehoseGenerator) InitResources() error {
 	svc := firehose.New(config)
 	var streamNames []string
 	for {
-		output, err := svc.ListDeliveryStreamsRequest(&firehose.ListDeliveryStreamsInput{}).Send(context.Background())
+		output, err := svc.ListDeliveryStreamsRequest(&firehose.ListDeliveryStreamsInput{}).Send(g.GetContext())
 		if err != nil {
 			return err
 		}
@@ -15,8 +15,6 @@
 package aws
 
 import (
-	"context"
-
 	"github.com/GoogleCloudPlatform/terraformer/terraformutils"
 	"github.com/aws/aws-sdk-go-v2/service/glue"
 )
@@ -28,7 +26,7 @@ type GlueGenerator struct {
 func (g *GlueGenerator) loadGlueCrawlers(svc *glue.Client) error {
 	var GlueCrawlerAllowEmptyValues = []string{"tags."}
 	p := glue.NewGetCrawlersPaginator(svc.GetCrawlersRequest(&glue.GetCrawlersInput{}))
-	for p.Next(context.Background()) {
+	for p.Next(g.GetContext()) {
 		for _, crawler := range p.CurrentPage().Crawlers {
 			resource := terraformutils.NewSimpleResource(*crawler.Name, *crawler.Name,
 				"aws_glue_crawler",
@@ -43,7 +41,7 @@ func (g *GlueGenerator) loadGlueCrawlers(svc *glue.Client) error {
 func (g *GlueGenerator) loadGlueCatalogDatabase(svc *glue.Client, account *string) (databaseNames []*string, error error) {
 	var GlueCatalogDatabaseAllowEmptyValues = []string{"tags."}
 	p := glue.NewGetDatabasesPaginator(svc.GetDatabasesRequest(&glue.GetDatabasesInput{}))
-	for p.Next(context.Background()) {
+	for p.Next(g.GetContext()) {
 		for _, catalogDatabase := range p.CurrentPage().DatabaseList {
 			// format of ID is "CATALOG-ID:DATABASE-NAME".
 			// CATALOG-ID is AWS Account ID
@@ -66,7 +64,7 @@ func (g *GlueGenerator) loadGlueCatalogTable(svc *glue.Client, account *string,
 	// https://docs.aws.amazon.com/cli/latest/reference/glue/create-database.html#options
 	var GlueCatalogTableAllowEmptyValues = []string{"tags."}
 	p := glue.NewGetTablesPaginator(svc.GetTablesRequest(&glue.GetTablesInput{DatabaseName: databaseName}))
-	for p.Next(context.Background()) {
+	for p.Next(g.GetContext()) {
 		for _, catalogTable := range p.CurrentPage().TableList {
 			databaseTable := *databaseName + ":" + *catalogTable.Name
 			id := *account + ":" + databaseTable
@@ -15,7 +15,6 @@
 package aws
 
 import (
-	"context"
 	"fmt"
 	"log"
 	"strings"
@@ -72,7 +71,7 @@ func (g *IamGenerator) InitResources() error {
 
 func (g *IamGenerator) getRoles(svc *iam.Client) error {
 	p := iam.NewListRolesPaginator(svc.ListRolesRequest(&iam.ListRolesInput{}))
-	for p.Next(context.Background()) {
+	for p.Next(g.GetContext()) {
 		for _, role := range p.CurrentPage().Roles {
 			roleName := aws.StringValue(role.RoleName)
 			g.Resources = append(g.Resources, terraformutils.NewSimpleResource(
@@ -82,7 +81,7 @@ func (g *IamGenerator) getRoles(svc *iam.Client) error {
 				"aws",
 				IamAllowEmptyValues))
 			rolePoliciesPage := iam.NewListRolePoliciesPaginator(svc.ListRolePoliciesRequest(&iam.ListRolePoliciesInput{RoleName: role.RoleName}))
-			for rolePoliciesPage.Next(context.Background()) {
+			for rolePoliciesPage.Next(g.GetContext()) {
 				for _, policyName := range rolePoliciesPage.CurrentPage().PolicyNames {
 					g.Resources = append(g.Resources, terraformutils.NewSimpleResource(
 						roleName+":"+policyName,
@@ -99,7 +98,7 @@ func (g *IamGenerator) getRoles(svc *iam.Client) error {
 			roleAttachedPoliciesPage := iam.NewListAttachedRolePoliciesPaginator(svc.ListAttachedRolePoliciesRequest(&iam.ListAttachedRolePoliciesInput{
 				RoleName: &roleName,
 			}))
-			for roleAttachedPoliciesPage.Next(context.Background()) {
+			for roleAttachedPoliciesPage.Next(g.GetContext()) {
 				for _, attachedPolicy := range roleAttachedPoliciesPage.CurrentPage().AttachedPolicies {
 					g.Resources = append(g.Resources, terraformutils.NewResource(
 						roleName+"/"+*attachedPolicy.PolicyArn,
@@ -125,7 +124,7 @@ func (g *IamGenerator) getRoles(svc *iam.Client) error {
 
 func (g *IamGenerator) getUsers(svc *iam.Client) error {
 	p := iam.NewListUsersPaginator(svc.ListUsersRequest(&iam.ListUsersInput{}))
-	for p.Next(context.Background()) {
+	for p.Next(g.GetContext()) {
 		for _, user := range p.CurrentPage().Users {
 			resourceName := aws.StringValue(user.UserName)
 			g.Resources = append(g.Resources, terraformutils.NewResource(
@@ -157,7 +156,7 @@ func (g *IamGenerator) getUsers(svc *iam.Client) error {
 
 func (g *IamGenerator) getUserGroup(svc *iam.Client, userName *string) error {
 	p := iam.NewListGroupsForUserPaginator(svc.ListGroupsForUserRequest(&iam.ListGroupsForUserInput{UserName: userName}))
-	for p.Next(context.Background()) {
+	for p.Next(g.GetContext()) {
 		for _, group := range p.CurrentPage().Groups {
 			userGroupMembership := *userName + "/" + *group.GroupName
 			g.Resources = append(g.Resources, terraformutils.NewResource(
@@ -180,7 +179,7 @@ func (g *IamGenerator) getUserGroup(svc *iam.Client, userName *string) error {
 
 func (g *IamGenerator) getUserPolices(svc *iam.Client, userName *string) error {
 	p := iam.NewListUserPoliciesPaginator(svc.ListUserPoliciesRequest(&iam.ListUserPoliciesInput{UserName: userName}))
-	for p.Next(context.Background()) {
+	for p.Next(g.GetContext()) {
 		for _, policy := range p.CurrentPage().PolicyNames {
 			resourceName := aws.StringValue(userName) + "_" + policy
 			resourceName = strings.ReplaceAll(resourceName, "@", "")
@@ -200,7 +199,7 @@ func (g *IamGenerator) getUserPolicyAttachment(svc *iam.Client, userName *string
 	p := iam.NewListAttachedUserPoliciesPaginator(svc.ListAttachedUserPoliciesRequest(&iam.ListAttachedUserPoliciesInput{
 		UserName: userName,
 	}))
-	for p.Next(context.Background()) {
+	for p.Next(g.GetContext()) {
 		for _, attachedPolicy := range p.CurrentPage().AttachedPolicies {
 			g.Resources = append(g.Resources, terraformutils.NewResource(
 				*userName+"/"+*attachedPolicy.PolicyArn,
@@ -220,7 +219,7 @@ func (g *IamGenerator) getUserPolicyAttachment(svc *iam.Client, userName *string
 
 func (g *IamGenerator) getPolicies(svc *iam.Client) error {
 	p := iam.NewListPoliciesPaginator(svc.ListPoliciesRequest(&iam.ListPoliciesInput{Scope: iam.PolicyScopeTypeLocal}))
-	for p.Next(context.Background()) {
+	for p.Next(g.GetContext()) {
 		for _, policy := range p.CurrentPage().Policies {
 			resourceName := aws.StringValue(policy.PolicyName)
 			policyARN := aws.StringValue(policy.Arn)
@@ -238,7 +237,7 @@ func (g *IamGenerator) getPolicies(svc *iam.Client) error {
 
 func (g *IamGenerator) getGroups(svc *iam.Client) error {
 	p := iam.NewListGroupsPaginator(svc.ListGroupsRequest(&iam.ListGroupsInput{}))
-	for p.Next(context.Background()) {
+	for p.Next(g.GetContext()) {
 		for _, group := range p.CurrentPage().Groups {
 			resourceName := aws.StringValue(group.GroupName)
 			g.Resources = append(g.Resources, terraformutils.NewSimpleResource(
@@ -256,7 +255,7 @@ func (g *IamGenerator) getGroups(svc *iam.Client) error {
 
 func (g *IamGenerator) getGroupPolicies(svc *iam.Client, group iam.Group) {
 	groupPoliciesPage := iam.NewListGroupPoliciesPaginator(svc.ListGroupPoliciesRequest(&iam.ListGroupPoliciesInput{GroupName: group.GroupName}))
-	for groupPoliciesPage.Next(context.Background()) {
+	for groupPoliciesPage.Next(g.GetContext()) {
 		for _, policy := range groupPoliciesPage.CurrentPage().PolicyNames {
 			id := *group.GroupName + ":" + policy
 			groupPolicyName := *group.GroupName + "_" + policy
@@ -278,7 +277,7 @@ func (g *IamGenerator) getGroupPolicies(svc *iam.Client, group iam.Group) {
 func (g *IamGenerator) getAttachedGroupPolicies(svc *iam.Client, group iam.Group) {
 	groupAttachedPoliciesPage := iam.NewListAttachedGroupPoliciesPaginator(svc.ListAttachedGroupPoliciesRequest(
 		&iam.ListAttachedGroupPoliciesInput{GroupName: group.GroupName}))
-	for groupAttachedPoliciesPage.Next(context.Background()) {
+	for groupAttachedPoliciesPage.Next(g.GetContext()) {
 		for _, attachedPolicy := range groupAttachedPoliciesPage.CurrentPage().AttachedPolicies {
 			if !strings.Contains(*attachedPolicy.PolicyArn, "arn:aws:iam::aws") {
 				continue // map only AWS managed policies since others should be managed by
@@ -304,7 +303,7 @@ func (g *IamGenerator) getAttachedGroupPolicies(svc *iam.Client, group iam.Group
 
 func (g *IamGenerator) getInstanceProfiles(svc *iam.Client) error {
 	p := iam.NewListInstanceProfilesPaginator(svc.ListInstanceProfilesRequest(&iam.ListInstanceProfilesInput{}))
-	for p.Next(context.Background()) {
+	for p.Next(g.GetContext()) {
 		for _, instanceProfile := range p.CurrentPage().InstanceProfiles {
 			resourceName := *instanceProfile.InstanceProfileName
 
@@ -15,8 +15,6 @@
 package aws
 
 import (
-	"context"
-
 	"github.com/GoogleCloudPlatform/terraformer/terraformutils"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -56,7 +54,7 @@ func (g *IgwGenerator) InitResources() error {
 	}
 	svc := ec2.New(config)
 	p := ec2.NewDescribeInternetGatewaysPaginator(svc.DescribeInternetGatewaysRequest(&ec2.DescribeInternetGatewaysInput{}))
-	for p.Next(context.Background()) {
+	for p.Next(g.GetContext()) {
 		g.Resources = append(g.Resources, g.createResources(p.CurrentPage())...)
 	}
 	return p.Err()
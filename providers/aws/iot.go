@@ -15,8 +15,6 @@
 package aws
 
 import (
-	"context"
-
 	"github.com/GoogleCloudPlatform/terraformer/terraformutils"
 	"github.com/aws/aws-sdk-go-v2/service/iot"
 )
@@ -51,7 +49,7 @@ func (g *IotGenerator) InitResources() error {
 }
 
 func (g *IotGenerator) loadThingTypes(svc *iot.Client) error {
-	output, err := svc.ListThingTypesRequest(&iot.ListThingTypesInput{}).Send(context.Background())
+	output, err := svc.ListThingTypesRequest(&iot.ListThingTypesInput{}).Send(g.GetContext())
 	if err != nil {
 		return err
 	}
@@ -72,7 +70,7 @@ func (g *IotGenerator) loadThingTypes(svc *iot.Client) error {
 }
 
 func (g *IotGenerator) loadThings(svc *iot.Client) error {
-	output, err := svc.ListThingsRequest(&iot.ListThingsInput{}).Send(context.Background())
+	output, err := svc.ListThingsRequest(&iot.ListThingsInput{}).Send(g.GetContext())
 	if err != nil {
 		return err
 	}
@@ -93,7 +91,7 @@ func (g *IotGenerator) loadThings(svc *iot.Client) error {
 }
 
 func (g *IotGenerator) loadTopicRules(svc *iot.Client) error {
-	output, err := svc.ListTopicRulesRequest(&iot.ListTopicRulesInput{}).Send(context.Background())
+	output, err := svc.ListTopicRulesRequest(&iot.ListTopicRulesInput{}).Send(g.GetContext())
 	if err != nil {
 		return err
 	}
@@ -109,7 +107,7 @@ func (g *IotGenerator) loadTopicRules(svc *iot.Client) error {
 }
 
 func (g *IotGenerator) loadRoleAliases(svc *iot.Client) error {
-	output, err := svc.ListRoleAliasesRequest(&iot.ListRoleAliasesInput{}).Send(context.Background())
+	output, err := svc.ListRoleAliasesRequest(&iot.ListRoleAliasesInput{}).Send(g.GetContext())
 	if err != nil {
 		return err
 	}
@@ -15,8 +15,6 @@
 package aws
 
 import (
-	"context"
-
 	"github.com/GoogleCloudPlatform/terraformer/terraformutils"
 	"github.com/aws/aws-sdk-go-v2/service/kinesis"
 )
@@ -49,7 +47,7 @@ func (g *KinesisGenerator) InitResources() error {
 	}
 	svc := kinesis.New(config)
 	p := kinesis.NewListStreamsPaginator(svc.ListStreamsRequest(&kinesis.ListStreamsInput{}))
-	for p.Next(context.Background()) {
+	for p.Next(g.GetContext()) {
 		g.Resources = append(g.Resources, g.createResources(p.CurrentPage().StreamNames)...)
 	}
 	return p.Err()
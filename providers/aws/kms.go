@@ -15,8 +15,6 @@
 package aws
 
 import (
-	"context"
-
 	"github.com/GoogleCloudPlatform/terraformer/terraformutils"
 	"github.com/aws/aws-sdk-go-v2/service/kms"
 )
@@ -44,7 +42,7 @@ func (g *KmsGenerator) InitResources() error {
 
 func (g *KmsGenerator) addKeys(client *kms.Client) error {
 	p := kms.NewListKeysPaginator(client.ListKeysRequest(&kms.ListKeysInput{}))
-	for p.Next(context.Background()) {
+	for p.Next(g.GetContext()) {
 		for _, key := range p.CurrentPage().Keys {
 			resource := terraformutils.NewResource(
 				*key.KeyId,
@@ -57,7 +55,6 @@ func (g *KmsGenerator) addKeys(client *kms.Client) error {
 				kmsAllowEmptyValues,
 				map[string]interface{}{},
 			)
-			resource.SlowQueryRequired = true
 			g.Resources = append(g.Resources, resource)
 		}
 	}
@@ -66,7 +63,7 @@ func (g *KmsGenerator) addKeys(client *kms.Client) error {
 
 func (g *KmsGenerator) addAliases(client *kms.Client) error {
 	p := kms.NewListAliasesPaginator(client.ListAliasesRequest(&kms.ListAliasesInput{}))
-	for p.Next(context.Background()) {
+	for p.Next(g.GetContext()) {
 		for _, alias := range p.CurrentPage().Aliases {
 			resource := terraformutils.NewSimpleResource(
 				*alias.AliasName,
@@ -75,7 +72,6 @@ func (g *KmsGenerator) addAliases(client *kms.Client) error {
 				"aws",
 				kmsAllowEmptyValues,
 			)
-			resource.SlowQueryRequired = true
 			g.Resources = append(g.Resources, resource)
 		}
 	}
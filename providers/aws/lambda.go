@@ -15,8 +15,6 @@
 package aws
 
 import (
-	"context"
-
 	"github.com/GoogleCloudPlatform/terraformer/terraformutils"
 	"github.com/aws/aws-sdk-go-v2/service/lambda"
 )
@@ -71,7 +69,7 @@ func (g *LambdaGenerator) PostConvertHook() error {
 
 func (g *LambdaGenerator) addFunctions(svc *lambda.Client) error {
 	p := lambda.NewListFunctionsPaginator(svc.ListFunctionsRequest(&lambda.ListFunctionsInput{}))
-	for p.Next(context.Background()) {
+	for p.Next(g.GetContext()) {
 		for _, function := range p.CurrentPage().Functions {
 			g.Resources = append(g.Resources, terraformutils.NewResource(
 				*function.FunctionArn,
@@ -89,7 +87,7 @@ func (g *LambdaGenerator) addFunctions(svc *lambda.Client) error {
 				&lambda.ListFunctionEventInvokeConfigsInput{
 					FunctionName: function.FunctionName,
 				}))
-			for pi.Next(context.Background()) {
+			for pi.Next(g.GetContext()) {
 				for _, functionEventInvokeConfig := range pi.CurrentPage().FunctionEventInvokeConfigs {
 					g.Resources = append(g.Resources, terraformutils.NewSimpleResource(
 						*function.FunctionArn,
@@ -110,7 +108,7 @@ func (g *LambdaGenerator) addFunctions(svc *lambda.Client) error {
 
 func (g *LambdaGenerator) addEventSourceMappings(svc *lambda.Client) error {
 	p := lambda.NewListEventSourceMappingsPaginator(svc.ListEventSourceMappingsRequest(&lambda.ListEventSourceMappingsInput{}))
-	for p.Next(context.Background()) {
+	for p.Next(g.GetContext()) {
 		for _, mapping := range p.CurrentPage().EventSourceMappings {
 			g.Resources = append(g.Resources, terraformutils.NewResource(
 				*mapping.UUID,
@@ -131,12 +129,12 @@ func (g *LambdaGenerator) addEventSourceMappings(svc *lambda.Client) error {
 
 func (g *LambdaGenerator) addLayerVersions(svc *lambda.Client) error {
 	pl := lambda.NewListLayersPaginator(svc.ListLayersRequest(&lambda.ListLayersInput{}))
-	for pl.Next(context.Background()) {
+	for pl.Next(g.GetContext()) {
 		for _, layer := range pl.CurrentPage().Layers {
 			pv := lambda.NewListLayerVersionsPaginator(svc.ListLayerVersionsRequest(&lambda.ListLayerVersionsInput{
 				LayerName: layer.LayerName,
 			}))
-			for pv.Next(context.Background()) {
+			for pv.Next(g.GetContext()) {
 				for _, layerVersion := range pv.CurrentPage().LayerVersions {
 					g.Resources = append(g.Resources, terraformutils.NewSimpleResource(
 						*layerVersion.LayerVersionArn,
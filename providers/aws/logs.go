@@ -15,7 +15,6 @@
 package aws
 
 import (
-	"context"
 	"strconv"
 
 	"github.com/GoogleCloudPlatform/terraformer/terraformutils"
@@ -64,7 +63,7 @@ func (g *LogsGenerator) InitResources() error {
 	}
 	svc := cloudwatchlogs.New(config)
 
-	logGroups, err := svc.DescribeLogGroupsRequest(&cloudwatchlogs.DescribeLogGroupsInput{}).Send(context.Background())
+	logGroups, err := svc.DescribeLogGroupsRequest(&cloudwatchlogs.DescribeLogGroupsInput{}).Send(g.GetContext())
 	if err != nil {
 		return err
 	}
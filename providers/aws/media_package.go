@@ -15,8 +15,6 @@
 package aws
 
 import (
-	"context"
-
 	"github.com/GoogleCloudPlatform/terraformer/terraformutils"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/mediapackage"
@@ -36,7 +34,7 @@ func (g *MediaPackageGenerator) InitResources() error {
 	svc := mediapackage.New(config)
 	p := mediapackage.NewListChannelsPaginator(svc.ListChannelsRequest(&mediapackage.ListChannelsInput{}))
 	var resources []terraformutils.Resource
-	for p.Next(context.Background()) {
+	for p.Next(g.GetContext()) {
 		for _, channel := range p.CurrentPage().Channels {
 			channelID := aws.StringValue(channel.Id)
 			resources = append(resources, terraformutils.NewSimpleResource(
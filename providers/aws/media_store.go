@@ -15,8 +15,6 @@
 package aws
 
 import (
-	"context"
-
 	"github.com/GoogleCloudPlatform/terraformer/terraformutils"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/mediastore"
@@ -36,7 +34,7 @@ func (g *MediaStoreGenerator) InitResources() error {
 	svc := mediastore.New(config)
 	p := mediastore.NewListContainersPaginator(svc.ListContainersRequest(&mediastore.ListContainersInput{}))
 	var resources []terraformutils.Resource
-	for p.Next(context.Background()) {
+	for p.Next(g.GetContext()) {
 		for _, container := range p.CurrentPage().Containers {
 			containerName := aws.StringValue(container.Name)
 			resources = append(resources, terraformutils.NewSimpleResource(
@@ -15,8 +15,6 @@
 package aws
 
 import (
-	"context"
-
 	"github.com/GoogleCloudPlatform/terraformer/terraformutils"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/kafka"
@@ -35,7 +33,7 @@ func (g *MskGenerator) InitResources() error {
 	}
 	svc := kafka.New(config)
 	p := kafka.NewListClustersPaginator(svc.ListClustersRequest(&kafka.ListClustersInput{}))
-	for p.Next(context.Background()) {
+	for p.Next(g.GetContext()) {
 		for _, clusterInfo := range p.CurrentPage().ClusterInfoList {
 			g.Resources = append(g.Resources, terraformutils.NewSimpleResource(
 				aws.StringValue(clusterInfo.ClusterArn),
@@ -15,7 +15,6 @@
 package aws
 
 import (
-	"context"
 	"fmt"
 
 	"github.com/GoogleCloudPlatform/terraformer/terraformutils"
@@ -32,7 +31,7 @@ type OrganizationGenerator struct {
 
 func (g *OrganizationGenerator) traverseNode(svc *organizations.Client, parentID string) {
 	accountsForParent, err := svc.ListAccountsForParentRequest(
-		&organizations.ListAccountsForParentInput{ParentId: aws.String(parentID)}).Send(context.Background())
+		&organizations.ListAccountsForParentInput{ParentId: aws.String(parentID)}).Send(g.GetContext())
 	if err != nil {
 		return
 	}
@@ -64,7 +63,7 @@ func (g *OrganizationGenerator) traverseNode(svc *organizations.Client, parentID
 	}
 
 	unitsForParent, err := svc.ListOrganizationalUnitsForParentRequest(
-		&organizations.ListOrganizationalUnitsForParentInput{ParentId: aws.String(parentID)}).Send(context.Background())
+		&organizations.ListOrganizationalUnitsForParentInput{ParentId: aws.String(parentID)}).Send(g.GetContext())
 	if err != nil {
 		return
 	}
@@ -92,7 +91,7 @@ func (g *OrganizationGenerator) InitResources() error {
 	}
 	svc := organizations.New(config)
 
-	roots, err := svc.ListRootsRequest(&organizations.ListRootsInput{}).Send(context.Background())
+	roots, err := svc.ListRootsRequest(&organizations.ListRootsInput{}).Send(g.GetContext())
 	if err != nil {
 		return err
 	}
@@ -105,7 +104,7 @@ func (g *OrganizationGenerator) InitResources() error {
 	p := organizations.NewListPoliciesPaginator(svc.ListPoliciesRequest(&organizations.ListPoliciesInput{
 		Filter: organizations.PolicyTypeServiceControlPolicy,
 	}))
-	for p.Next(context.Background()) {
+	for p.Next(g.GetContext()) {
 		for _, policy := range p.CurrentPage().Policies {
 			policyID := aws.StringValue(policy.Id)
 			policyName := aws.StringValue(policy.Name)
@@ -123,7 +122,7 @@ func (g *OrganizationGenerator) InitResources() error {
 			))
 
 			targetsForPolicy, err := svc.ListTargetsForPolicyRequest(
-				&organizations.ListTargetsForPolicyInput{PolicyId: policy.Id}).Send(context.Background())
+				&organizations.ListTargetsForPolicyInput{PolicyId: policy.Id}).Send(g.GetContext())
 			if err != nil {
 				fmt.Println(err.Error())
 				continue
@@ -15,8 +15,6 @@
 package aws
 
 import (
-	"context"
-
 	"github.com/GoogleCloudPlatform/terraformer/terraformutils"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/qldb"
@@ -36,7 +34,7 @@ func (g *QLDBGenerator) InitResources() error {
 	svc := qldb.New(config)
 	p := qldb.NewListLedgersPaginator(svc.ListLedgersRequest(&qldb.ListLedgersInput{}))
 	var resources []terraformutils.Resource
-	for p.Next(context.Background()) {
+	for p.Next(g.GetContext()) {
 		for _, ledger := range p.CurrentPage().Ledgers {
 			ledgerName := aws.StringValue(ledger.Name)
 			resources = append(resources, terraformutils.NewSimpleResource(
@@ -15,7 +15,6 @@
 package aws
 
 import (
-	"context"
 	"strings"
 
 	"github.com/GoogleCloudPlatform/terraformer/terraformutils"
@@ -33,7 +32,7 @@ type RDSGenerator struct {
 
 func (g *RDSGenerator) loadDBInstances(svc *rds.Client) error {
 	p := rds.NewDescribeDBInstancesPaginator(svc.DescribeDBInstancesRequest(&rds.DescribeDBInstancesInput{}))
-	for p.Next(context.Background()) {
+	for p.Next(g.GetContext()) {
 		for _, db := range p.CurrentPage().DBInstances {
 			resourceName := aws.StringValue(db.DBInstanceIdentifier)
 			g.Resources = append(g.Resources, terraformutils.NewSimpleResource(
@@ -50,7 +49,7 @@ func (g *RDSGenerator) loadDBInstances(svc *rds.Client) error {
 
 func (g *RDSGenerator) loadDBParameterGroups(svc *rds.Client) error {
 	p := rds.NewDescribeDBParameterGroupsPaginator(svc.DescribeDBParameterGroupsRequest(&rds.DescribeDBParameterGroupsInput{}))
-	for p.Next(context.Background()) {
+	for p.Next(g.GetContext()) {
 		for _, parameterGroup := range p.CurrentPage().DBParameterGroups {
 			resourceName := aws.StringValue(parameterGroup.DBParameterGroupName)
 			if strings.Contains(resourceName, ".") {
@@ -70,7 +69,7 @@ func (g *RDSGenerator) loadDBParameterGroups(svc *rds.Client) error {
 
 func (g *RDSGenerator) loadDBSubnetGroups(svc *rds.Client) error {
 	p := rds.NewDescribeDBSubnetGroupsPaginator(svc.DescribeDBSubnetGroupsRequest(&rds.DescribeDBSubnetGroupsInput{}))
-	for p.Next(context.Background()) {
+	for p.Next(g.GetContext()) {
 		for _, subnet := range p.CurrentPage().DBSubnetGroups {
 			resourceName := aws.StringValue(subnet.DBSubnetGroupName)
 			g.Resources = append(g.Resources, terraformutils.NewSimpleResource(
@@ -87,7 +86,7 @@ func (g *RDSGenerator) loadDBSubnetGroups(svc *rds.Client) error {
 
 func (g *RDSGenerator) loadOptionGroups(svc *rds.Client) error {
 	p := rds.NewDescribeOptionGroupsPaginator(svc.DescribeOptionGroupsRequest(&rds.DescribeOptionGroupsInput{}))
-	for p.Next(context.Background()) {
+	for p.Next(g.GetContext()) {
 		for _, optionGroup := range p.CurrentPage().OptionGroupsList {
 			resourceName := aws.StringValue(optionGroup.OptionGroupName)
 			if strings.Contains(resourceName, ".") || strings.Contains(resourceName, ":") {
@@ -107,7 +106,7 @@ func (g *RDSGenerator) loadOptionGroups(svc *rds.Client) error {
 
 func (g *RDSGenerator) loadEventSubscription(svc *rds.Client) error {
 	p := rds.NewDescribeEventSubscriptionsPaginator(svc.DescribeEventSubscriptionsRequest(&rds.DescribeEventSubscriptionsInput{}))
-	for p.Next(context.Background()) {
+	for p.Next(g.GetContext()) {
 		for _, eventSubscription := range p.CurrentPage().EventSubscriptionsList {
 			resourceName := aws.StringValue(eventSubscription.CustomerAwsId)
 			g.Resources = append(g.Resources, terraformutils.NewSimpleResource(
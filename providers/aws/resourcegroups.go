@@ -15,8 +15,6 @@
 package aws
 
 import (
-	"context"
-
 	"github.com/GoogleCloudPlatform/terraformer/terraformutils"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/resourcegroups"
@@ -36,7 +34,7 @@ func (g *ResourceGroupsGenerator) InitResources() error {
 	svc := resourcegroups.New(config)
 	p := resourcegroups.NewListGroupsPaginator(svc.ListGroupsRequest(&resourcegroups.ListGroupsInput{}))
 	var resources []terraformutils.Resource
-	for p.Next(context.Background()) {
+	for p.Next(g.GetContext()) {
 		for _, group := range p.CurrentPage().Groups {
 			groupName := aws.StringValue(group.Name)
 			resources = append(resources, terraformutils.NewSimpleResource(
@@ -15,7 +15,6 @@
 package aws
 
 import (
-	"context"
 	"fmt"
 	"log"
 	"strings"
@@ -37,7 +36,7 @@ type Route53Generator struct {
 func (g *Route53Generator) createZonesResources(svc *route53.Client) []terraformutils.Resource {
 	resources := []terraformutils.Resource{}
 	p := route53.NewListHostedZonesPaginator(svc.ListHostedZonesRequest(&route53.ListHostedZonesInput{}))
-	for p.Next(context.Background()) {
+	for p.Next(g.GetContext()) {
 		for _, zone := range p.CurrentPage().HostedZones {
 			zoneID := cleanZoneID(aws.StringValue(zone.Id))
 			resources = append(resources, terraformutils.NewResource(
@@ -62,14 +61,14 @@ func (g *Route53Generator) createZonesResources(svc *route53.Client) []terraform
 	return resources
 }
 
-func (Route53Generator) createRecordsResources(svc *route53.Client, zoneID string) []terraformutils.Resource {
+func (g Route53Generator) createRecordsResources(svc *route53.Client, zoneID string) []terraformutils.Resource {
 	var resources []terraformutils.Resource
 	listParams := &route53.ListResourceRecordSetsInput{
 		HostedZoneId: aws.String(zoneID),
 	}
 
 	p := route53.NewListResourceRecordSetsPaginator(svc.ListResourceRecordSetsRequest(listParams))
-	for p.Next(context.Background()) {
+	for p.Next(g.GetContext()) {
 		for _, record := range p.CurrentPage().ResourceRecordSets {
 			recordName := wildcardUnescape(aws.StringValue(record.Name))
 			typeString, _ := record.Type.MarshalValue()
@@ -15,7 +15,6 @@
 package aws
 
 import (
-	"context"
 	"fmt"
 	"log"
 
@@ -43,7 +42,7 @@ func (g *S3Generator) createResources(config aws.Config, buckets *s3.ListBuckets
 	svc := s3.New(config)
 	for _, bucket := range buckets.Buckets {
 		resourceName := aws.StringValue(bucket.Name)
-		location, err := svc.GetBucketLocationRequest(&s3.GetBucketLocationInput{Bucket: bucket.Name}).Send(context.Background())
+		location, err := svc.GetBucketLocationRequest(&s3.GetBucketLocationInput{Bucket: bucket.Name}).Send(g.GetContext())
 		if err != nil {
 			log.Println(err)
 			continue
@@ -58,7 +57,7 @@ func (g *S3Generator) createResources(config aws.Config, buckets *s3.ListBuckets
 			// try get policy
 			policy, err := svc.GetBucketPolicyRequest(&s3.GetBucketPolicyInput{
 				Bucket: bucket.Name,
-			}).Send(context.Background())
+			}).Send(g.GetContext())
 
 			if err != nil {
 				if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() != "NoSuchBucketPolicy" {
@@ -91,7 +90,7 @@ func (g *S3Generator) InitResources() error {
 	}
 	svc := s3.New(config)
 
-	buckets, err := svc.ListBucketsRequest(&s3.ListBucketsInput{}).Send(context.Background())
+	buckets, err := svc.ListBucketsRequest(&s3.ListBucketsInput{}).Send(g.GetContext())
 	if err != nil {
 		return err
 	}
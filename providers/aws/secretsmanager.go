@@ -15,8 +15,6 @@
 package aws
 
 import (
-	"context"
-
 	"github.com/GoogleCloudPlatform/terraformer/terraformutils"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
@@ -36,7 +34,7 @@ func (g *SecretsManagerGenerator) InitResources() error {
 	svc := secretsmanager.New(config)
 	p := secretsmanager.NewListSecretsPaginator(svc.ListSecretsRequest(&secretsmanager.ListSecretsInput{}))
 	var resources []terraformutils.Resource
-	for p.Next(context.Background()) {
+	for p.Next(g.GetContext()) {
 		for _, secret := range p.CurrentPage().SecretList {
 			secretArn := aws.StringValue(secret.ARN)
 			secretName := aws.StringValue(secret.Name)
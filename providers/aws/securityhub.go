@@ -15,7 +15,6 @@
 package aws
 
 import (
-	"context"
 	"strings"
 
 	"github.com/GoogleCloudPlatform/terraformer/terraformutils"
@@ -56,7 +55,7 @@ func (g *SecurityhubGenerator) InitResources() error {
 }
 
 func (g *SecurityhubGenerator) addAccount(client *securityhub.Client, accountNumber string) (bool, error) {
-	_, err := client.GetEnabledStandardsRequest(&securityhub.GetEnabledStandardsInput{}).Send(context.Background())
+	_, err := client.GetEnabledStandardsRequest(&securityhub.GetEnabledStandardsInput{}).Send(g.GetContext())
 
 	if err != nil {
 		errorMsg := err.Error()
@@ -78,7 +77,7 @@ func (g *SecurityhubGenerator) addAccount(client *securityhub.Client, accountNum
 func (g *SecurityhubGenerator) addMembers(svc *securityhub.Client, accountNumber string) error {
 	p := securityhub.NewListMembersPaginator(svc.ListMembersRequest(&securityhub.ListMembersInput{}))
 
-	for p.Next(context.Background()) {
+	for p.Next(g.GetContext()) {
 		page := p.CurrentPage()
 		for _, member := range page.Members {
 			id := *member.AccountId
@@ -105,7 +104,7 @@ func (g *SecurityhubGenerator) addStandardsSubscription(svc *securityhub.Client,
 	p := securityhub.NewGetEnabledStandardsPaginator(
 		svc.GetEnabledStandardsRequest(&securityhub.GetEnabledStandardsInput{}))
 
-	for p.Next(context.Background()) {
+	for p.Next(g.GetContext()) {
 		page := p.CurrentPage()
 		for _, standardsSubscription := range page.StandardsSubscriptions {
 			id := *standardsSubscription.StandardsSubscriptionArn
@@ -15,8 +15,6 @@
 package aws
 
 import (
-	"context"
-
 	"github.com/GoogleCloudPlatform/terraformer/terraformutils"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/servicecatalog"
@@ -36,7 +34,7 @@ func (g *ServiceCatalogGenerator) InitResources() error {
 	svc := servicecatalog.New(config)
 	p := servicecatalog.NewListPortfoliosPaginator(svc.ListPortfoliosRequest(&servicecatalog.ListPortfoliosInput{}))
 	var resources []terraformutils.Resource
-	for p.Next(context.Background()) {
+	for p.Next(g.GetContext()) {
 		for _, portfolio := range p.CurrentPage().PortfolioDetails {
 			portfolioID := aws.StringValue(portfolio.Id)
 			portfolioName := aws.StringValue(portfolio.DisplayName)
@@ -15,8 +15,6 @@
 package aws
 
 import (
-	"context"
-
 	"github.com/GoogleCloudPlatform/terraformer/terraformutils"
 	"github.com/aws/aws-sdk-go-v2/service/ses"
 	"github.com/aws/aws-sdk-go/aws"
@@ -58,7 +56,7 @@ func (g *SesGenerator) loadDomainIdentities(svc *ses.Client) error {
 	p := ses.NewListIdentitiesPaginator(svc.ListIdentitiesRequest(&ses.ListIdentitiesInput{
 		IdentityType: "Domain",
 	}))
-	for p.Next(context.Background()) {
+	for p.Next(g.GetContext()) {
 		for _, identity := range p.CurrentPage().Identities {
 			g.Resources = append(g.Resources, terraformutils.NewSimpleResource(
 				identity,
@@ -75,7 +73,7 @@ func (g *SesGenerator) loadMailIdentities(svc *ses.Client) error {
 	p := ses.NewListIdentitiesPaginator(svc.ListIdentitiesRequest(&ses.ListIdentitiesInput{
 		IdentityType: "EmailAddress",
 	}))
-	for p.Next(context.Background()) {
+	for p.Next(g.GetContext()) {
 		for _, identity := range p.CurrentPage().Identities {
 			g.Resources = append(g.Resources, terraformutils.NewSimpleResource(
 				identity,
@@ -89,7 +87,7 @@ func (g *SesGenerator) loadMailIdentities(svc *ses.Client) error {
 }
 
 func (g *SesGenerator) loadTemplates(svc *ses.Client) error {
-	templates, err := svc.ListTemplatesRequest(&ses.ListTemplatesInput{}).Send(context.Background())
+	templates, err := svc.ListTemplatesRequest(&ses.ListTemplatesInput{}).Send(g.GetContext())
 	if err != nil {
 		return err
 	}
@@ -106,7 +104,7 @@ func (g *SesGenerator) loadTemplates(svc *ses.Client) error {
 }
 
 func (g *SesGenerator) loadConfigurationSets(svc *ses.Client) error {
-	configurationSets, err := svc.ListConfigurationSetsRequest(&ses.ListConfigurationSetsInput{}).Send(context.Background())
+	configurationSets, err := svc.ListConfigurationSetsRequest(&ses.ListConfigurationSetsInput{}).Send(g.GetContext())
 	if err != nil {
 		return err
 	}
@@ -123,7 +121,7 @@ func (g *SesGenerator) loadConfigurationSets(svc *ses.Client) error {
 }
 
 func (g *SesGenerator) loadRuleSets(svc *ses.Client) error {
-	ruleSets, err := svc.ListReceiptRuleSetsRequest(&ses.ListReceiptRuleSetsInput{}).Send(context.Background())
+	ruleSets, err := svc.ListReceiptRuleSetsRequest(&ses.ListReceiptRuleSetsInput{}).Send(g.GetContext())
 	if err != nil {
 		return err
 	}
@@ -138,7 +136,7 @@ func (g *SesGenerator) loadRuleSets(svc *ses.Client) error {
 			sesAllowEmptyValues))
 		rules, err := svc.DescribeReceiptRuleSetRequest(&ses.DescribeReceiptRuleSetInput{
 			RuleSetName: ruleSet.Name,
-		}).Send(context.Background())
+		}).Send(g.GetContext())
 		if err != nil {
 			return err
 		}
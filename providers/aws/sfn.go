@@ -1,8 +1,6 @@
 package aws
 
 import (
-	"context"
-
 	"github.com/GoogleCloudPlatform/terraformer/terraformutils"
 	"github.com/aws/aws-sdk-go-v2/service/sfn"
 )
@@ -21,7 +19,7 @@ func (g *SfnGenerator) InitResources() error {
 	svc := sfn.New(config)
 
 	p := sfn.NewListStateMachinesPaginator(svc.ListStateMachinesRequest(&sfn.ListStateMachinesInput{}))
-	for p.Next(context.Background()) {
+	for p.Next(g.GetContext()) {
 		for _, stateMachine := range p.CurrentPage().StateMachines {
 			g.Resources = append(g.Resources, terraformutils.NewSimpleResource(
 				*stateMachine.StateMachineArn,
@@ -38,7 +36,7 @@ func (g *SfnGenerator) InitResources() error {
 	}
 
 	pActivity := sfn.NewListActivitiesPaginator(svc.ListActivitiesRequest(&sfn.ListActivitiesInput{}))
-	for pActivity.Next(context.Background()) {
+	for pActivity.Next(g.GetContext()) {
 		for _, stateMachine := range pActivity.CurrentPage().Activities {
 			g.Resources = append(g.Resources, terraformutils.NewSimpleResource(
 				*stateMachine.ActivityArn,
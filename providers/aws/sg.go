@@ -16,7 +16,6 @@ package aws
 
 import (
 	"bytes"
-	"context"
 	"fmt"
 	"os"
 	"sort"
@@ -247,7 +246,7 @@ func (g *SecurityGenerator) InitResources() error {
 	svc := ec2.New(config)
 	p := ec2.NewDescribeSecurityGroupsPaginator(svc.DescribeSecurityGroupsRequest(&ec2.DescribeSecurityGroupsInput{}))
 	var resourcesToFilter []ec2.SecurityGroup
-	for p.Next(context.Background()) {
+	for p.Next(g.GetContext()) {
 		resourcesToFilter = append(resourcesToFilter, p.CurrentPage().SecurityGroups...)
 	}
 	sort.Slice(resourcesToFilter, func(i, j int) bool {
@@ -15,7 +15,6 @@
 package aws
 
 import (
-	"context"
 	"fmt"
 	"log"
 	"strings"
@@ -44,7 +43,7 @@ func (g *SnsGenerator) InitResources() error {
 	}
 	svc := sns.New(config)
 	p := sns.NewListTopicsPaginator(svc.ListTopicsRequest(&sns.ListTopicsInput{}))
-	for p.Next(context.Background()) {
+	for p.Next(g.GetContext()) {
 		for _, topic := range p.CurrentPage().Topics {
 			arnParts := strings.Split(aws.StringValue(topic.TopicArn), ":")
 			topicName := arnParts[len(arnParts)-1]
@@ -60,7 +59,7 @@ func (g *SnsGenerator) InitResources() error {
 			topicSubsPage := sns.NewListSubscriptionsByTopicPaginator(svc.ListSubscriptionsByTopicRequest(&sns.ListSubscriptionsByTopicInput{
 				TopicArn: topic.TopicArn,
 			}))
-			for topicSubsPage.Next(context.Background()) {
+			for topicSubsPage.Next(g.GetContext()) {
 				for _, subscription := range topicSubsPage.CurrentPage().Subscriptions {
 					subscriptionArnParts := strings.Split(aws.StringValue(subscription.SubscriptionArn), ":")
 					subscriptionID := subscriptionArnParts[len(subscriptionArnParts)-1]
@@ -1,8 +1,6 @@
 package aws
 
 import (
-	"context"
-
 	"github.com/GoogleCloudPlatform/terraformer/terraformutils"
 	"github.com/aws/aws-sdk-go-v2/service/swf"
 )
@@ -20,7 +18,7 @@ func (g *SWFGenerator) InitResources() error {
 	svc := swf.New(config)
 	for _, status := range regStatuses {
 		p := swf.NewListDomainsPaginator(svc.ListDomainsRequest(&swf.ListDomainsInput{RegistrationStatus: status}))
-		for p.Next(context.Background()) {
+		for p.Next(g.GetContext()) {
 			for _, domain := range p.CurrentPage().DomainInfos {
 				g.Resources = append(g.Resources, terraformutils.NewSimpleResource(
 					*domain.Name,
@@ -15,7 +15,6 @@
 package aws
 
 import (
-	"context"
 	"log"
 
 	"github.com/GoogleCloudPlatform/terraformer/terraformutils"
@@ -32,7 +31,7 @@ type TransitGatewayGenerator struct {
 
 func (g *TransitGatewayGenerator) getTransitGateways(svc *ec2.Client) error {
 	p := ec2.NewDescribeTransitGatewaysPaginator(svc.DescribeTransitGatewaysRequest(&ec2.DescribeTransitGatewaysInput{}))
-	for p.Next(context.Background()) {
+	for p.Next(g.GetContext()) {
 		for _, tgw := range p.CurrentPage().TransitGateways {
 			g.Resources = append(g.Resources, terraformutils.NewSimpleResource(
 				aws.StringValue(tgw.TransitGatewayId),
@@ -48,7 +47,7 @@ func (g *TransitGatewayGenerator) getTransitGateways(svc *ec2.Client) error {
 
 func (g *TransitGatewayGenerator) getTransitGatewayRouteTables(svc *ec2.Client) error {
 	p := ec2.NewDescribeTransitGatewayRouteTablesPaginator(svc.DescribeTransitGatewayRouteTablesRequest(&ec2.DescribeTransitGatewayRouteTablesInput{}))
-	for p.Next(context.Background()) {
+	for p.Next(g.GetContext()) {
 		for _, tgwrt := range p.CurrentPage().TransitGatewayRouteTables {
 			// Default route table are automatically created on the tgw creation
 			if *tgwrt.DefaultAssociationRouteTable {
@@ -69,7 +68,7 @@ func (g *TransitGatewayGenerator) getTransitGatewayRouteTables(svc *ec2.Client)
 
 func (g *TransitGatewayGenerator) getTransitGatewayVpcAttachments(svc *ec2.Client) error {
 	p := ec2.NewDescribeTransitGatewayVpcAttachmentsPaginator(svc.DescribeTransitGatewayVpcAttachmentsRequest(&ec2.DescribeTransitGatewayVpcAttachmentsInput{}))
-	for p.Next(context.Background()) {
+	for p.Next(g.GetContext()) {
 		for _, tgwa := range p.CurrentPage().TransitGatewayVpcAttachments {
 			g.Resources = append(g.Resources, terraformutils.NewSimpleResource(
 				aws.StringValue(tgwa.TransitGatewayAttachmentId),
@@ -15,8 +15,6 @@
 package aws
 
 import (
-	"context"
-
 	"github.com/GoogleCloudPlatform/terraformer/terraformutils"
 	"github.com/aws/aws-sdk-go-v2/service/waf"
 )
@@ -75,7 +73,7 @@ func (g *WafGenerator) InitResources() error {
 }
 
 func (g *WafGenerator) loadWebACL(svc *waf.Client) error {
-	output, err := svc.ListWebACLsRequest(&waf.ListWebACLsInput{}).Send(context.Background())
+	output, err := svc.ListWebACLsRequest(&waf.ListWebACLsInput{}).Send(g.GetContext())
 	if err != nil {
 		return err
 	}
@@ -91,7 +89,7 @@ func (g *WafGenerator) loadWebACL(svc *waf.Client) error {
 }
 
 func (g *WafGenerator) loadByteMatchSet(svc *waf.Client) error {
-	output, err := svc.ListByteMatchSetsRequest(&waf.ListByteMatchSetsInput{}).Send(context.Background())
+	output, err := svc.ListByteMatchSetsRequest(&waf.ListByteMatchSetsInput{}).Send(g.GetContext())
 	if err != nil {
 		return err
 	}
@@ -107,7 +105,7 @@ func (g *WafGenerator) loadByteMatchSet(svc *waf.Client) error {
 }
 
 func (g *WafGenerator) loadGeoMatchSet(svc *waf.Client) error {
-	output, err := svc.ListGeoMatchSetsRequest(&waf.ListGeoMatchSetsInput{}).Send(context.Background())
+	output, err := svc.ListGeoMatchSetsRequest(&waf.ListGeoMatchSetsInput{}).Send(g.GetContext())
 	if err != nil {
 		return err
 	}
@@ -123,7 +121,7 @@ func (g *WafGenerator) loadGeoMatchSet(svc *waf.Client) error {
 }
 
 func (g *WafGenerator) loadIPSet(svc *waf.Client) error {
-	output, err := svc.ListIPSetsRequest(&waf.ListIPSetsInput{}).Send(context.Background())
+	output, err := svc.ListIPSetsRequest(&waf.ListIPSetsInput{}).Send(g.GetContext())
 	if err != nil {
 		return err
 	}
@@ -139,7 +137,7 @@ func (g *WafGenerator) loadIPSet(svc *waf.Client) error {
 }
 
 func (g *WafGenerator) loadRateBasedRules(svc *waf.Client) error {
-	output, err := svc.ListRateBasedRulesRequest(&waf.ListRateBasedRulesInput{}).Send(context.Background())
+	output, err := svc.ListRateBasedRulesRequest(&waf.ListRateBasedRulesInput{}).Send(g.GetContext())
 	if err != nil {
 		return err
 	}
@@ -155,7 +153,7 @@ func (g *WafGenerator) loadRateBasedRules(svc *waf.Client) error {
 }
 
 func (g *WafGenerator) loadRegexMatchSets(svc *waf.Client) error {
-	output, err := svc.ListRegexMatchSetsRequest(&waf.ListRegexMatchSetsInput{}).Send(context.Background())
+	output, err := svc.ListRegexMatchSetsRequest(&waf.ListRegexMatchSetsInput{}).Send(g.GetContext())
 	if err != nil {
 		return err
 	}
@@ -171,7 +169,7 @@ func (g *WafGenerator) loadRegexMatchSets(svc *waf.Client) error {
 }
 
 func (g *WafGenerator) loadRegexPatternSets(svc *waf.Client) error {
-	output, err := svc.ListRegexPatternSetsRequest(&waf.ListRegexPatternSetsInput{}).Send(context.Background())
+	output, err := svc.ListRegexPatternSetsRequest(&waf.ListRegexPatternSetsInput{}).Send(g.GetContext())
 	if err != nil {
 		return err
 	}
@@ -187,7 +185,7 @@ func (g *WafGenerator) loadRegexPatternSets(svc *waf.Client) error {
 }
 
 func (g *WafGenerator) loadWafRules(svc *waf.Client) error {
-	output, err := svc.ListRulesRequest(&waf.ListRulesInput{}).Send(context.Background())
+	output, err := svc.ListRulesRequest(&waf.ListRulesInput{}).Send(g.GetContext())
 	if err != nil {
 		return err
 	}
@@ -203,7 +201,7 @@ func (g *WafGenerator) loadWafRules(svc *waf.Client) error {
 }
 
 func (g *WafGenerator) loadWafRuleGroups(svc *waf.Client) error {
-	output, err := svc.ListRuleGroupsRequest(&waf.ListRuleGroupsInput{}).Send(context.Background())
+	output, err := svc.ListRuleGroupsRequest(&waf.ListRuleGroupsInput{}).Send(g.GetContext())
 	if err != nil {
 		return err
 	}
@@ -219,7 +217,7 @@ func (g *WafGenerator) loadWafRuleGroups(svc *waf.Client) error {
 }
 
 func (g *WafGenerator) loadSizeConstraintSets(svc *waf.Client) error {
-	output, err := svc.ListSizeConstraintSetsRequest(&waf.ListSizeConstraintSetsInput{}).Send(context.Background())
+	output, err := svc.ListSizeConstraintSetsRequest(&waf.ListSizeConstraintSetsInput{}).Send(g.GetContext())
 	if err != nil {
 		return err
 	}
@@ -235,7 +233,7 @@ func (g *WafGenerator) loadSizeConstraintSets(svc *waf.Client) error {
 }
 
 func (g *WafGenerator) loadSQLInjectionMatchSets(svc *waf.Client) error {
-	output, err := svc.ListSqlInjectionMatchSetsRequest(&waf.ListSqlInjectionMatchSetsInput{}).Send(context.Background())
+	output, err := svc.ListSqlInjectionMatchSetsRequest(&waf.ListSqlInjectionMatchSetsInput{}).Send(g.GetContext())
 	if err != nil {
 		return err
 	}
@@ -251,7 +249,7 @@ func (g *WafGenerator) loadSQLInjectionMatchSets(svc *waf.Client) error {
 }
 
 func (g *WafGenerator) loadXSSMatchSet(svc *waf.Client) error {
-	output, err := svc.ListXssMatchSetsRequest(&waf.ListXssMatchSetsInput{}).Send(context.Background())
+	output, err := svc.ListXssMatchSetsRequest(&waf.ListXssMatchSetsInput{}).Send(g.GetContext())
 	if err != nil {
 		return err
 	}
@@ -15,8 +15,6 @@
 package aws
 
 import (
-	"context"
-
 	"github.com/GoogleCloudPlatform/terraformer/terraformutils"
 	"github.com/aws/aws-sdk-go-v2/service/wafregional"
 )
@@ -74,7 +72,7 @@ func (g *WafRegionalGenerator) InitResources() error {
 }
 
 func (g *WafRegionalGenerator) loadWebACL(svc *wafregional.Client) error {
-	output, err := svc.ListWebACLsRequest(&wafregional.ListWebACLsInput{}).Send(context.Background())
+	output, err := svc.ListWebACLsRequest(&wafregional.ListWebACLsInput{}).Send(g.GetContext())
 	if err != nil {
 		return err
 	}
@@ -90,7 +88,7 @@ func (g *WafRegionalGenerator) loadWebACL(svc *wafregional.Client) error {
 }
 
 func (g *WafRegionalGenerator) loadByteMatchSet(svc *wafregional.Client) error {
-	output, err := svc.ListByteMatchSetsRequest(&wafregional.ListByteMatchSetsInput{}).Send(context.Background())
+	output, err := svc.ListByteMatchSetsRequest(&wafregional.ListByteMatchSetsInput{}).Send(g.GetContext())
 	if err != nil {
 		return err
 	}
@@ -106,7 +104,7 @@ func (g *WafRegionalGenerator) loadByteMatchSet(svc *wafregional.Client) error {
 }
 
 func (g *WafRegionalGenerator) loadGeoMatchSet(svc *wafregional.Client) error {
-	output, err := svc.ListGeoMatchSetsRequest(&wafregional.ListGeoMatchSetsInput{}).Send(context.Background())
+	output, err := svc.ListGeoMatchSetsRequest(&wafregional.ListGeoMatchSetsInput{}).Send(g.GetContext())
 	if err != nil {
 		return err
 	}
@@ -122,7 +120,7 @@ func (g *WafRegionalGenerator) loadGeoMatchSet(svc *wafregional.Client) error {
 }
 
 func (g *WafRegionalGenerator) loadIPSet(svc *wafregional.Client) error {
-	output, err := svc.ListIPSetsRequest(&wafregional.ListIPSetsInput{}).Send(context.Background())
+	output, err := svc.ListIPSetsRequest(&wafregional.ListIPSetsInput{}).Send(g.GetContext())
 	if err != nil {
 		return err
 	}
@@ -138,7 +136,7 @@ func (g *WafRegionalGenerator) loadIPSet(svc *wafregional.Client) error {
 }
 
 func (g *WafRegionalGenerator) loadRateBasedRules(svc *wafregional.Client) error {
-	output, err := svc.ListRateBasedRulesRequest(&wafregional.ListRateBasedRulesInput{}).Send(context.Background())
+	output, err := svc.ListRateBasedRulesRequest(&wafregional.ListRateBasedRulesInput{}).Send(g.GetContext())
 	if err != nil {
 		return err
 	}
@@ -154,7 +152,7 @@ func (g *WafRegionalGenerator) loadRateBasedRules(svc *wafregional.Client) error
 }
 
 func (g *WafRegionalGenerator) loadRegexMatchSets(svc *wafregional.Client) error {
-	output, err := svc.ListRegexMatchSetsRequest(&wafregional.ListRegexMatchSetsInput{}).Send(context.Background())
+	output, err := svc.ListRegexMatchSetsRequest(&wafregional.ListRegexMatchSetsInput{}).Send(g.GetContext())
 	if err != nil {
 		return err
 	}
@@ -170,7 +168,7 @@ func (g *WafRegionalGenerator) loadRegexMatchSets(svc *wafregional.Client) error
 }
 
 func (g *WafRegionalGenerator) loadRegexPatternSets(svc *wafregional.Client) error {
-	output, err := svc.ListRegexPatternSetsRequest(&wafregional.ListRegexPatternSetsInput{}).Send(context.Background())
+	output, err := svc.ListRegexPatternSetsRequest(&wafregional.ListRegexPatternSetsInput{}).Send(g.GetContext())
 	if err != nil {
 		return err
 	}
@@ -186,7 +184,7 @@ func (g *WafRegionalGenerator) loadRegexPatternSets(svc *wafregional.Client) err
 }
 
 func (g *WafRegionalGenerator) loadWafRules(svc *wafregional.Client) error {
-	output, err := svc.ListRulesRequest(&wafregional.ListRulesInput{}).Send(context.Background())
+	output, err := svc.ListRulesRequest(&wafregional.ListRulesInput{}).Send(g.GetContext())
 	if err != nil {
 		return err
 	}
@@ -202,7 +200,7 @@ func (g *WafRegionalGenerator) loadWafRules(svc *wafregional.Client) error {
 }
 
 func (g *WafRegionalGenerator) loadWafRuleGroups(svc *wafregional.Client) error {
-	output, err := svc.ListRuleGroupsRequest(&wafregional.ListRuleGroupsInput{}).Send(context.Background())
+	output, err := svc.ListRuleGroupsRequest(&wafregional.ListRuleGroupsInput{}).Send(g.GetContext())
 	if err != nil {
 		return err
 	}
@@ -218,7 +216,7 @@ func (g *WafRegionalGenerator) loadWafRuleGroups(svc *wafregional.Client) error
 }
 
 func (g *WafRegionalGenerator) loadSizeConstraintSets(svc *wafregional.Client) error {
-	output, err := svc.ListSizeConstraintSetsRequest(&wafregional.ListSizeConstraintSetsInput{}).Send(context.Background())
+	output, err := svc.ListSizeConstraintSetsRequest(&wafregional.ListSizeConstraintSetsInput{}).Send(g.GetContext())
 	if err != nil {
 		return err
 	}
@@ -234,7 +232,7 @@ func (g *WafRegionalGenerator) loadSizeConstraintSets(svc *wafregional.Client) e
 }
 
 func (g *WafRegionalGenerator) loadSQLInjectionMatchSets(svc *wafregional.Client) error {
-	output, err := svc.ListSqlInjectionMatchSetsRequest(&wafregional.ListSqlInjectionMatchSetsInput{}).Send(context.Background())
+	output, err := svc.ListSqlInjectionMatchSetsRequest(&wafregional.ListSqlInjectionMatchSetsInput{}).Send(g.GetContext())
 	if err != nil {
 		return err
 	}
@@ -250,7 +248,7 @@ func (g *WafRegionalGenerator) loadSQLInjectionMatchSets(svc *wafregional.Client
 }
 
 func (g *WafRegionalGenerator) loadXSSMatchSet(svc *wafregional.Client) error {
-	output, err := svc.ListXssMatchSetsRequest(&wafregional.ListXssMatchSetsInput{}).Send(context.Background())
+	output, err := svc.ListXssMatchSetsRequest(&wafregional.ListXssMatchSetsInput{}).Send(g.GetContext())
 	if err != nil {
 		return err
 	}
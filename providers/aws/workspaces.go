@@ -15,8 +15,6 @@
 package aws
 
 import (
-	"context"
-
 	"github.com/GoogleCloudPlatform/terraformer/terraformutils"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/workspaces"
@@ -45,7 +43,7 @@ func (g *WorkspacesGenerator) InitResources() error {
 
 func (g *WorkspacesGenerator) loadWorkspaces(svc *workspaces.Client) error {
 	p := workspaces.NewDescribeWorkspacesPaginator(svc.DescribeWorkspacesRequest(&workspaces.DescribeWorkspacesInput{}))
-	for p.Next(context.Background()) {
+	for p.Next(g.GetContext()) {
 		for _, workspace := range p.CurrentPage().Workspaces {
 			directoryID := aws.StringValue(workspace.DirectoryId)
 			workspaceID := aws.StringValue(workspace.WorkspaceId)
@@ -69,7 +67,7 @@ func (g *WorkspacesGenerator) loadWorkspaces(svc *workspaces.Client) error {
 func (g *WorkspacesGenerator) loadWorkspacesIPGroup(svc *workspaces.Client) error {
 	var nextToken *string
 	for {
-		response, err := svc.DescribeIpGroupsRequest(&workspaces.DescribeIpGroupsInput{NextToken: nextToken}).Send(context.Background())
+		response, err := svc.DescribeIpGroupsRequest(&workspaces.DescribeIpGroupsInput{NextToken: nextToken}).Send(g.GetContext())
 		if err != nil {
 			return err
 		}
@@ -1,8 +1,6 @@
 package aws
 
 import (
-	"context"
-
 	"github.com/GoogleCloudPlatform/terraformer/terraformutils"
 	"github.com/aws/aws-sdk-go-v2/service/xray"
 )
@@ -21,7 +19,7 @@ func (g *XrayGenerator) InitResources() error {
 	svc := xray.New(config)
 
 	p := xray.NewGetSamplingRulesPaginator(svc.GetSamplingRulesRequest(&xray.GetSamplingRulesInput{}))
-	for p.Next(context.Background()) {
+	for p.Next(g.GetContext()) {
 		for _, samplingRule := range p.CurrentPage().SamplingRuleRecords {
 			// NOTE: Builtin rule with unmodifiable name and 10000 prirority (lowest)
 			if *samplingRule.SamplingRule.RuleName != "Default" {
@@ -15,7 +15,6 @@
 package azure
 
 import (
-	"context"
 	"log"
 
 	"github.com/Azure/azure-sdk-for-go/services/analysisservices/mgmt/2017-08-01/analysisservices"
@@ -31,7 +30,7 @@ type AnalysisGenerator struct {
 func (g *AnalysisGenerator) listServiceServers() ([]terraformutils.Resource, error) {
 	log.Println("\tImporting Service Servers")
 	var resources []terraformutils.Resource
-	ctx := context.Background()
+	ctx := g.GetContext()
 	AnalysisClient := analysisservices.NewServersClient(g.Args["config"].(authentication.Config).SubscriptionID)
 	AnalysisClient.Authorizer = g.Args["authorizer"].(autorest.Authorizer)
 
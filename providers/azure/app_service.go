@@ -1,7 +1,6 @@
 package azure
 
 import (
-	"context"
 	"log"
 
 	"github.com/Azure/go-autorest/autorest"
@@ -17,7 +16,7 @@ type AppServiceGenerator struct {
 
 func (g AppServiceGenerator) listApps() ([]terraformutils.Resource, error) {
 	var resources []terraformutils.Resource
-	ctx := context.Background()
+	ctx := g.GetContext()
 
 	appServiceClient := web.NewAppsClient(g.Args["config"].(authentication.Config).SubscriptionID)
 	appServiceClient.Authorizer = g.Args["authorizer"].(autorest.Authorizer)
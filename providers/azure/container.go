@@ -15,7 +15,6 @@
 package azure
 
 import (
-	"context"
 	"log"
 
 	"github.com/Azure/azure-sdk-for-go/services/containerinstance/mgmt/2018-10-01/containerinstance"
@@ -31,7 +30,7 @@ type ContainerGenerator struct {
 
 func (g *ContainerGenerator) listAndAddForContainerGroup() ([]terraformutils.Resource, error) {
 	var resources []terraformutils.Resource
-	ctx := context.Background()
+	ctx := g.GetContext()
 	subscriptionID := g.Args["config"].(authentication.Config).SubscriptionID
 	ContainerGroupsClient := containerinstance.NewContainerGroupsClient(subscriptionID)
 	ContainerGroupsClient.Authorizer = g.Args["authorizer"].(autorest.Authorizer)
@@ -69,7 +68,7 @@ func (g *ContainerGenerator) listAndAddForContainerGroup() ([]terraformutils.Res
 
 func (g *ContainerGenerator) listRegistryWebhooks(resourceGroupName string, registryName string) ([]terraformutils.Resource, error) {
 	var resources []terraformutils.Resource
-	ctx := context.Background()
+	ctx := g.GetContext()
 	subscriptionID := g.Args["config"].(authentication.Config).SubscriptionID
 	WebhooksClient := containerregistry.NewWebhooksClient(subscriptionID)
 	WebhooksClient.Authorizer = g.Args["authorizer"].(autorest.Authorizer)
@@ -97,7 +96,7 @@ func (g *ContainerGenerator) listRegistryWebhooks(resourceGroupName string, regi
 
 func (g *ContainerGenerator) listAndAddForContainerRegistry() ([]terraformutils.Resource, error) {
 	var resources []terraformutils.Resource
-	ctx := context.Background()
+	ctx := g.GetContext()
 	subscriptionID := g.Args["config"].(authentication.Config).SubscriptionID
 	ContainerRegistriesClient := containerregistry.NewRegistriesClient(subscriptionID)
 	ContainerRegistriesClient.Authorizer = g.Args["authorizer"].(autorest.Authorizer)
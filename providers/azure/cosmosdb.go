@@ -15,7 +15,6 @@
 package azure
 
 import (
-	"context"
 	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/services/cosmos-db/mgmt/2020-03-01/documentdb"
@@ -31,7 +30,7 @@ type CosmosDBGenerator struct {
 func (g *CosmosDBGenerator) listSQLDatabasesAndContainersBehind(resourceGroupName string, accountName string) ([]terraformutils.Resource, []terraformutils.Resource, error) {
 	var resourcesDatabase []terraformutils.Resource
 	var resourcesContainer []terraformutils.Resource
-	ctx := context.Background()
+	ctx := g.GetContext()
 	subscriptionID := g.Args["config"].(authentication.Config).SubscriptionID
 	SQLResourcesClient := documentdb.NewSQLResourcesClient(subscriptionID, subscriptionID)
 	SQLResourcesClient.Authorizer = g.Args["authorizer"].(autorest.Authorizer)
@@ -79,7 +78,7 @@ func (g *CosmosDBGenerator) listSQLDatabasesAndContainersBehind(resourceGroupNam
 
 func (g *CosmosDBGenerator) listTables(resourceGroupName string, accountName string) ([]terraformutils.Resource, error) {
 	var resources []terraformutils.Resource
-	ctx := context.Background()
+	ctx := g.GetContext()
 	subscriptionID := g.Args["config"].(authentication.Config).SubscriptionID
 	// NOTE:
 	// there will be a parameter simplification for interface if we update the package
@@ -106,7 +105,7 @@ func (g *CosmosDBGenerator) listTables(resourceGroupName string, accountName str
 
 func (g *CosmosDBGenerator) listAndAddForDatabaseAccounts() ([]terraformutils.Resource, error) {
 	var resources []terraformutils.Resource
-	ctx := context.Background()
+	ctx := g.GetContext()
 	subscriptionID := g.Args["config"].(authentication.Config).SubscriptionID
 	// NOTE:
 	// there will be a parameter simplification for interface if we update the package
@@ -15,7 +15,6 @@
 package azure
 
 import (
-	"context"
 	"fmt"
 	"strings"
 
@@ -33,7 +32,7 @@ type DatabasesGenerator struct {
 }
 
 func (g *DatabasesGenerator) getMariaDBServers() ([]mariadb.Server, error) {
-	ctx := context.Background()
+	ctx := g.GetContext()
 	SubscriptionID := g.Args["config"].(authentication.Config).SubscriptionID
 	Authorizer := g.Args["authorizer"].(autorest.Authorizer)
 
@@ -77,7 +76,7 @@ func (g *DatabasesGenerator) createMariaDBServerResources(servers []mariadb.Serv
 
 func (g *DatabasesGenerator) createMariaDBConfigurationResources(servers []mariadb.Server) ([]terraformutils.Resource, error) {
 	var resources []terraformutils.Resource
-	ctx := context.Background()
+	ctx := g.GetContext()
 	SubscriptionID := g.Args["config"].(authentication.Config).SubscriptionID
 	Authorizer := g.Args["authorizer"].(autorest.Authorizer)
 
@@ -109,7 +108,7 @@ func (g *DatabasesGenerator) createMariaDBConfigurationResources(servers []maria
 
 func (g *DatabasesGenerator) createMariaDBDatabaseResources(servers []mariadb.Server) ([]terraformutils.Resource, error) {
 	var resources []terraformutils.Resource
-	ctx := context.Background()
+	ctx := g.GetContext()
 	SubscriptionID := g.Args["config"].(authentication.Config).SubscriptionID
 	Authorizer := g.Args["authorizer"].(autorest.Authorizer)
 
@@ -141,7 +140,7 @@ func (g *DatabasesGenerator) createMariaDBDatabaseResources(servers []mariadb.Se
 
 func (g *DatabasesGenerator) createMariaDBFirewallRuleResources(servers []mariadb.Server) ([]terraformutils.Resource, error) {
 	var resources []terraformutils.Resource
-	ctx := context.Background()
+	ctx := g.GetContext()
 	SubscriptionID := g.Args["config"].(authentication.Config).SubscriptionID
 	Authorizer := g.Args["authorizer"].(autorest.Authorizer)
 
@@ -172,7 +171,7 @@ func (g *DatabasesGenerator) createMariaDBFirewallRuleResources(servers []mariad
 
 func (g *DatabasesGenerator) createMariaDBVirtualNetworkRuleResources(servers []mariadb.Server) ([]terraformutils.Resource, error) {
 	var resources []terraformutils.Resource
-	ctx := context.Background()
+	ctx := g.GetContext()
 	SubscriptionID := g.Args["config"].(authentication.Config).SubscriptionID
 	Authorizer := g.Args["authorizer"].(autorest.Authorizer)
 
@@ -206,7 +205,7 @@ func (g *DatabasesGenerator) createMariaDBVirtualNetworkRuleResources(servers []
 }
 
 func (g *DatabasesGenerator) getMySQLServers() ([]mysql.Server, error) {
-	ctx := context.Background()
+	ctx := g.GetContext()
 	SubscriptionID := g.Args["config"].(authentication.Config).SubscriptionID
 	Authorizer := g.Args["authorizer"].(autorest.Authorizer)
 
@@ -251,7 +250,7 @@ func (g *DatabasesGenerator) createMySQLServerResources(servers []mysql.Server)
 
 func (g *DatabasesGenerator) createMySQLConfigurationResources(servers []mysql.Server) ([]terraformutils.Resource, error) {
 	var resources []terraformutils.Resource
-	ctx := context.Background()
+	ctx := g.GetContext()
 	SubscriptionID := g.Args["config"].(authentication.Config).SubscriptionID
 	Authorizer := g.Args["authorizer"].(autorest.Authorizer)
 
@@ -283,7 +282,7 @@ func (g *DatabasesGenerator) createMySQLConfigurationResources(servers []mysql.S
 
 func (g *DatabasesGenerator) createMySQLDatabaseResources(servers []mysql.Server) ([]terraformutils.Resource, error) {
 	var resources []terraformutils.Resource
-	ctx := context.Background()
+	ctx := g.GetContext()
 	SubscriptionID := g.Args["config"].(authentication.Config).SubscriptionID
 	Authorizer := g.Args["authorizer"].(autorest.Authorizer)
 
@@ -314,7 +313,7 @@ func (g *DatabasesGenerator) createMySQLDatabaseResources(servers []mysql.Server
 
 func (g *DatabasesGenerator) createMySQLFirewallRuleResources(servers []mysql.Server) ([]terraformutils.Resource, error) {
 	var resources []terraformutils.Resource
-	ctx := context.Background()
+	ctx := g.GetContext()
 	SubscriptionID := g.Args["config"].(authentication.Config).SubscriptionID
 	Authorizer := g.Args["authorizer"].(autorest.Authorizer)
 
@@ -346,7 +345,7 @@ func (g *DatabasesGenerator) createMySQLFirewallRuleResources(servers []mysql.Se
 
 func (g *DatabasesGenerator) createMySQLVirtualNetworkRuleResources(servers []mysql.Server) ([]terraformutils.Resource, error) {
 	var resources []terraformutils.Resource
-	ctx := context.Background()
+	ctx := g.GetContext()
 	SubscriptionID := g.Args["config"].(authentication.Config).SubscriptionID
 	Authorizer := g.Args["authorizer"].(autorest.Authorizer)
 
@@ -383,7 +382,7 @@ func (g *DatabasesGenerator) createMySQLVirtualNetworkRuleResources(servers []my
 }
 
 func (g *DatabasesGenerator) getPostgreSQLServers() ([]postgresql.Server, error) {
-	ctx := context.Background()
+	ctx := g.GetContext()
 	SubscriptionID := g.Args["config"].(authentication.Config).SubscriptionID
 	Authorizer := g.Args["authorizer"].(autorest.Authorizer)
 
@@ -429,7 +428,7 @@ func (g *DatabasesGenerator) createPostgreSQLServerResources(servers []postgresq
 
 func (g *DatabasesGenerator) createPostgreSQLDatabaseResources(servers []postgresql.Server) ([]terraformutils.Resource, error) {
 	var resources []terraformutils.Resource
-	ctx := context.Background()
+	ctx := g.GetContext()
 	SubscriptionID := g.Args["config"].(authentication.Config).SubscriptionID
 	Authorizer := g.Args["authorizer"].(autorest.Authorizer)
 
@@ -460,7 +459,7 @@ func (g *DatabasesGenerator) createPostgreSQLDatabaseResources(servers []postgre
 
 func (g *DatabasesGenerator) createPostgreSQLConfigurationResources(servers []postgresql.Server) ([]terraformutils.Resource, error) {
 	var resources []terraformutils.Resource
-	ctx := context.Background()
+	ctx := g.GetContext()
 	SubscriptionID := g.Args["config"].(authentication.Config).SubscriptionID
 	Authorizer := g.Args["authorizer"].(autorest.Authorizer)
 	Client := postgresql.NewConfigurationsClient(SubscriptionID)
@@ -490,7 +489,7 @@ func (g *DatabasesGenerator) createPostgreSQLConfigurationResources(servers []po
 
 func (g *DatabasesGenerator) createPostgreSQLFirewallRuleResources(servers []postgresql.Server) ([]terraformutils.Resource, error) {
 	var resources []terraformutils.Resource
-	ctx := context.Background()
+	ctx := g.GetContext()
 	SubscriptionID := g.Args["config"].(authentication.Config).SubscriptionID
 	Authorizer := g.Args["authorizer"].(autorest.Authorizer)
 
@@ -521,7 +520,7 @@ func (g *DatabasesGenerator) createPostgreSQLFirewallRuleResources(servers []pos
 
 func (g *DatabasesGenerator) createPostgreSQLVirtualNetworkRuleResources(servers []postgresql.Server) ([]terraformutils.Resource, error) {
 	var resources []terraformutils.Resource
-	ctx := context.Background()
+	ctx := g.GetContext()
 	SubscriptionID := g.Args["config"].(authentication.Config).SubscriptionID
 	Authorizer := g.Args["authorizer"].(autorest.Authorizer)
 
@@ -553,7 +552,7 @@ func (g *DatabasesGenerator) createPostgreSQLVirtualNetworkRuleResources(servers
 
 func (g *DatabasesGenerator) getSQLServers() ([]sql.Server, error) {
 	var servers []sql.Server
-	ctx := context.Background()
+	ctx := g.GetContext()
 	SubscriptionID := g.Args["config"].(authentication.Config).SubscriptionID
 	Authorizer := g.Args["authorizer"].(autorest.Authorizer)
 
@@ -604,7 +603,7 @@ func (g *DatabasesGenerator) createSQLServerResources(servers []sql.Server) ([]t
 
 func (g *DatabasesGenerator) createSQLDatabaseResources(servers []sql.Server) ([]terraformutils.Resource, error) {
 	var resources []terraformutils.Resource
-	ctx := context.Background()
+	ctx := g.GetContext()
 	SubscriptionID := g.Args["config"].(authentication.Config).SubscriptionID
 	Authorizer := g.Args["authorizer"].(autorest.Authorizer)
 
@@ -635,7 +634,7 @@ func (g *DatabasesGenerator) createSQLDatabaseResources(servers []sql.Server) ([
 
 func (g *DatabasesGenerator) createSQLFirewallRuleResources(servers []sql.Server) ([]terraformutils.Resource, error) {
 	var resources []terraformutils.Resource
-	ctx := context.Background()
+	ctx := g.GetContext()
 	SubscriptionID := g.Args["config"].(authentication.Config).SubscriptionID
 	Authorizer := g.Args["authorizer"].(autorest.Authorizer)
 
@@ -666,7 +665,7 @@ func (g *DatabasesGenerator) createSQLFirewallRuleResources(servers []sql.Server
 
 func (g *DatabasesGenerator) createSQLVirtualNetworkRuleResources(servers []sql.Server) ([]terraformutils.Resource, error) {
 	var resources []terraformutils.Resource
-	ctx := context.Background()
+	ctx := g.GetContext()
 	SubscriptionID := g.Args["config"].(authentication.Config).SubscriptionID
 	Authorizer := g.Args["authorizer"].(autorest.Authorizer)
 
@@ -701,7 +700,7 @@ func (g *DatabasesGenerator) createSQLVirtualNetworkRuleResources(servers []sql.
 
 func (g *DatabasesGenerator) createSQLElasticPoolResources(servers []sql.Server) ([]terraformutils.Resource, error) {
 	var resources []terraformutils.Resource
-	ctx := context.Background()
+	ctx := g.GetContext()
 	SubscriptionID := g.Args["config"].(authentication.Config).SubscriptionID
 	Authorizer := g.Args["authorizer"].(autorest.Authorizer)
 
@@ -732,7 +731,7 @@ func (g *DatabasesGenerator) createSQLElasticPoolResources(servers []sql.Server)
 
 func (g *DatabasesGenerator) createSQLFailoverResources(servers []sql.Server) ([]terraformutils.Resource, error) {
 	var resources []terraformutils.Resource
-	ctx := context.Background()
+	ctx := g.GetContext()
 	SubscriptionID := g.Args["config"].(authentication.Config).SubscriptionID
 	Authorizer := g.Args["authorizer"].(autorest.Authorizer)
 
@@ -766,7 +765,7 @@ func (g *DatabasesGenerator) createSQLFailoverResources(servers []sql.Server) ([
 
 func (g *DatabasesGenerator) createSQLADAdministratorResources(servers []sql.Server) ([]terraformutils.Resource, error) {
 	var resources []terraformutils.Resource
-	ctx := context.Background()
+	ctx := g.GetContext()
 	SubscriptionID := g.Args["config"].(authentication.Config).SubscriptionID
 	Authorizer := g.Args["authorizer"].(autorest.Authorizer)
 
@@ -15,7 +15,6 @@
 package azure
 
 import (
-	"context"
 	"log"
 
 	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2019-07-01/compute"
@@ -47,7 +46,7 @@ func (g DiskGenerator) createResources(diskListIterator compute.DiskListIterator
 }
 
 func (g *DiskGenerator) InitResources() error {
-	ctx := context.Background()
+	ctx := g.GetContext()
 	disksClient := compute.NewDisksClient(g.Args["config"].(authentication.Config).SubscriptionID)
 
 	disksClient.Authorizer = g.Args["authorizer"].(autorest.Authorizer)
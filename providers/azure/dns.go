@@ -15,7 +15,6 @@
 package azure
 
 import (
-	"context"
 	"log"
 	"strings"
 
@@ -31,7 +30,7 @@ type DNSGenerator struct {
 
 func (g *DNSGenerator) listRecordSets(resourceGroupName string, zoneName string, top *int32) ([]terraformutils.Resource, error) {
 	var resources []terraformutils.Resource
-	ctx := context.Background()
+	ctx := g.GetContext()
 	subscriptionID := g.Args["config"].(authentication.Config).SubscriptionID
 	RecordSetsClient := dns.NewRecordSetsClient(subscriptionID)
 	RecordSetsClient.Authorizer = g.Args["authorizer"].(autorest.Authorizer)
@@ -77,7 +76,7 @@ func (g *DNSGenerator) listRecordSets(resourceGroupName string, zoneName string,
 
 func (g *DNSGenerator) listAndAddForDNSZone() ([]terraformutils.Resource, error) {
 	var resources []terraformutils.Resource
-	ctx := context.Background()
+	ctx := g.GetContext()
 	subscriptionID := g.Args["config"].(authentication.Config).SubscriptionID
 	DNSZonesClient := dns.NewZonesClient(subscriptionID)
 	DNSZonesClient.Authorizer = g.Args["authorizer"].(autorest.Authorizer)
@@ -73,7 +73,7 @@ func (g KeyVaultGenerator) createResourcesByResourceGroup(ctx context.Context, r
 }
 
 func (g *KeyVaultGenerator) InitResources() error {
-	ctx := context.Background()
+	ctx := g.GetContext()
 	vaultsClient := keyvault.NewVaultsClient(g.Args["config"].(authentication.Config).SubscriptionID)
 
 	vaultsClient.Authorizer = g.Args["authorizer"].(autorest.Authorizer)
@@ -15,7 +15,6 @@
 package azure
 
 import (
-	"context"
 	"log"
 	"regexp"
 
@@ -31,7 +30,7 @@ type LoadBalancerGenerator struct {
 
 func (g *LoadBalancerGenerator) listLoadBalancerProbes(resourceGroupName string, loadBalancerName string) ([]terraformutils.Resource, error) {
 	var resources []terraformutils.Resource
-	ctx := context.Background()
+	ctx := g.GetContext()
 	subscriptionID := g.Args["config"].(authentication.Config).SubscriptionID
 
 	LoadBalancerProbesClient := network.NewLoadBalancerProbesClient(subscriptionID)
@@ -75,7 +74,7 @@ func (g *LoadBalancerGenerator) listLoadBalancerProbes(resourceGroupName string,
 
 func (g *LoadBalancerGenerator) listInboundNatRules(resourceGroupName string, loadBalancerName string) ([]terraformutils.Resource, error) {
 	var resources []terraformutils.Resource
-	ctx := context.Background()
+	ctx := g.GetContext()
 	subscriptionID := g.Args["config"].(authentication.Config).SubscriptionID
 
 	InboundNatRulesClient := network.NewInboundNatRulesClient(subscriptionID)
@@ -114,7 +113,7 @@ func (g *LoadBalancerGenerator) listInboundNatRules(resourceGroupName string, lo
 
 func (g *LoadBalancerGenerator) listLoadBalancerBackendAddressPools(resourceGroupName string, loadBalancerName string) ([]terraformutils.Resource, error) {
 	var resources []terraformutils.Resource
-	ctx := context.Background()
+	ctx := g.GetContext()
 	subscriptionID := g.Args["config"].(authentication.Config).SubscriptionID
 
 	LoadBalancerBackendAddressPoolsClient := network.NewLoadBalancerBackendAddressPoolsClient(subscriptionID)
@@ -152,7 +151,7 @@ func (g *LoadBalancerGenerator) listLoadBalancerBackendAddressPools(resourceGrou
 
 func (g *LoadBalancerGenerator) listAndAddForLoadBalancers() ([]terraformutils.Resource, error) {
 	var resources []terraformutils.Resource
-	ctx := context.Background()
+	ctx := g.GetContext()
 	subscriptionID := g.Args["config"].(authentication.Config).SubscriptionID
 
 	LoadBalancersClient := network.NewLoadBalancersClient(subscriptionID)
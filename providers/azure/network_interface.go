@@ -15,7 +15,6 @@
 package azure
 
 import (
-	"context"
 	"log"
 
 	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-08-01/network"
@@ -47,7 +46,7 @@ func (g NetworkInterfaceGenerator) createResources(interfaceListResult network.I
 }
 
 func (g *NetworkInterfaceGenerator) InitResources() error {
-	ctx := context.Background()
+	ctx := g.GetContext()
 	interfacesClient := network.NewInterfacesClient(g.Args["config"].(authentication.Config).SubscriptionID)
 
 	interfacesClient.Authorizer = g.Args["authorizer"].(autorest.Authorizer)
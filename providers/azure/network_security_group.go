@@ -15,7 +15,6 @@
 package azure
 
 import (
-	"context"
 	"log"
 
 	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-08-01/network"
@@ -47,7 +46,7 @@ func (g NetworkSecurityGroupGenerator) createResources(securityGroupListResult n
 }
 
 func (g *NetworkSecurityGroupGenerator) InitResources() error {
-	ctx := context.Background()
+	ctx := g.GetContext()
 	securityGroupsClient := network.NewSecurityGroupsClient(g.Args["config"].(authentication.Config).SubscriptionID)
 	securityGroupsClient.Authorizer = g.Args["authorizer"].(autorest.Authorizer)
 
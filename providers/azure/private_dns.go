@@ -15,7 +15,6 @@
 package azure
 
 import (
-	"context"
 	"log"
 	"strings"
 
@@ -31,7 +30,7 @@ type PrivateDNSGenerator struct {
 
 func (g *PrivateDNSGenerator) listRecordSets(resourceGroupName string, privateZoneName string, top *int32) ([]terraformutils.Resource, error) {
 	var resources []terraformutils.Resource
-	ctx := context.Background()
+	ctx := g.GetContext()
 	subscriptionID := g.Args["config"].(authentication.Config).SubscriptionID
 	RecordSetsClient := privatedns.NewRecordSetsClient(subscriptionID)
 	RecordSetsClient.Authorizer = g.Args["authorizer"].(autorest.Authorizer)
@@ -75,7 +74,7 @@ func (g *PrivateDNSGenerator) listRecordSets(resourceGroupName string, privateZo
 
 func (g *PrivateDNSGenerator) listVirtualNetworkLinks(resourceGroupName string, privateZoneName string, pageSize *int32) ([]terraformutils.Resource, error) {
 	var resources []terraformutils.Resource
-	ctx := context.Background()
+	ctx := g.GetContext()
 	subscriptionID := g.Args["config"].(authentication.Config).SubscriptionID
 	VirtualNetworkLinksClient := privatedns.NewVirtualNetworkLinksClient(subscriptionID)
 	VirtualNetworkLinksClient.Authorizer = g.Args["authorizer"].(autorest.Authorizer)
@@ -105,7 +104,7 @@ func (g *PrivateDNSGenerator) listVirtualNetworkLinks(resourceGroupName string,
 
 func (g *PrivateDNSGenerator) listAndAddForPrivateDNSZone() ([]terraformutils.Resource, error) {
 	var resources []terraformutils.Resource
-	ctx := context.Background()
+	ctx := g.GetContext()
 	subscriptionID := g.Args["config"].(authentication.Config).SubscriptionID
 	PrivateDNSZonesClient := privatedns.NewPrivateZonesClient(subscriptionID)
 	PrivateDNSZonesClient.Authorizer = g.Args["authorizer"].(autorest.Authorizer)
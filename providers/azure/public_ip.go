@@ -15,7 +15,6 @@
 package azure
 
 import (
-	"context"
 	"log"
 
 	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2020-03-01/network"
@@ -30,7 +29,7 @@ type PublicIPGenerator struct {
 
 func (g *PublicIPGenerator) listAndAddForPublicIPAddress() ([]terraformutils.Resource, error) {
 	var resources []terraformutils.Resource
-	ctx := context.Background()
+	ctx := g.GetContext()
 	subscriptionID := g.Args["config"].(authentication.Config).SubscriptionID
 	PublicIPAddressesClient := network.NewPublicIPAddressesClient(subscriptionID)
 	PublicIPAddressesClient.Authorizer = g.Args["authorizer"].(autorest.Authorizer)
@@ -67,7 +66,7 @@ func (g *PublicIPGenerator) listAndAddForPublicIPAddress() ([]terraformutils.Res
 
 func (g *PublicIPGenerator) listAndAddForPublicIPPrefix() ([]terraformutils.Resource, error) {
 	var resources []terraformutils.Resource
-	ctx := context.Background()
+	ctx := g.GetContext()
 	subscriptionID := g.Args["config"].(authentication.Config).SubscriptionID
 	PublicIPPrefixesClient := network.NewPublicIPPrefixesClient(subscriptionID)
 	PublicIPPrefixesClient.Authorizer = g.Args["authorizer"].(autorest.Authorizer)
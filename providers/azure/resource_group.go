@@ -15,7 +15,6 @@
 package azure
 
 import (
-	"context"
 	"log"
 
 	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2019-05-01/resources"
@@ -47,7 +46,7 @@ func (g ResourceGroupGenerator) createResources(groupListResultIterator resource
 }
 
 func (g *ResourceGroupGenerator) InitResources() error {
-	ctx := context.Background()
+	ctx := g.GetContext()
 	groupsClient := resources.NewGroupsClient(g.Args["config"].(authentication.Config).SubscriptionID)
 
 	groupsClient.Authorizer = g.Args["authorizer"].(autorest.Authorizer)
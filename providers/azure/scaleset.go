@@ -75,7 +75,7 @@ func (g ScaleSetGenerator) createResources(ctx context.Context, client compute.V
 }
 
 func (g *ScaleSetGenerator) InitResources() error {
-	ctx := context.Background()
+	ctx := g.GetContext()
 	ScaleSetClient := compute.NewVirtualMachineScaleSetsClient(g.Args["config"].(authentication.Config).SubscriptionID)
 
 	ScaleSetClient.Authorizer = g.Args["authorizer"].(autorest.Authorizer)
@@ -1,8 +1,6 @@
 package azure
 
 import (
-	"context"
-
 	"github.com/Azure/go-autorest/autorest"
 	"github.com/hashicorp/go-azure-helpers/authentication"
 
@@ -16,7 +14,7 @@ type SecurityCenterContactGenerator struct {
 
 func (g SecurityCenterContactGenerator) listContacts() ([]terraformutils.Resource, error) {
 	var resources []terraformutils.Resource
-	ctx := context.Background()
+	ctx := g.GetContext()
 	subscriptionID := g.Args["config"].(authentication.Config).SubscriptionID
 
 	securityCenterContactClient := security.NewContactsClient(subscriptionID, "")
@@ -69,7 +69,7 @@ func (g StorageAccountGenerator) createResources(ctx context.Context, client sto
 }
 
 func (g *StorageAccountGenerator) InitResources() error {
-	ctx := context.Background()
+	ctx := g.GetContext()
 	accountsClient := storage.NewAccountsClient(g.Args["config"].(authentication.Config).SubscriptionID)
 	accountsClient.Authorizer = g.Args["authorizer"].(autorest.Authorizer)
 	if rg := g.Args["resource_group"].(string); rg != "" {
@@ -76,7 +76,7 @@ func (g StorageBlobGenerator) getBlobsFromContainer(ctx context.Context, account
 
 func (g StorageBlobGenerator) listStorageBlobs() ([]terraformutils.Resource, error) {
 	var storageBlobsResources []terraformutils.Resource
-	ctx := context.Background()
+	ctx := g.GetContext()
 
 	blobContainerGenerator := NewStorageContainerGenerator(g.Args["config"].(authentication.Config).SubscriptionID, g.Args["authorizer"].(autorest.Authorizer), g.Args["resource_group"].(string))
 	blobContainersResources, err := blobContainerGenerator.ListBlobContainers()
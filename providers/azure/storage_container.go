@@ -1,7 +1,6 @@
 package azure
 
 import (
-	"context"
 	"fmt"
 
 	"github.com/Azure/azure-sdk-for-go/services/storage/mgmt/2019-04-01/storage"
@@ -32,7 +31,7 @@ func (g StorageContainerGenerator) ListBlobContainers() ([]terraformutils.Resour
 	var containerResources []terraformutils.Resource
 	blobContainersClient := storage.NewBlobContainersClient(g.Args["config"].(authentication.Config).SubscriptionID)
 	blobContainersClient.Authorizer = g.Args["authorizer"].(autorest.Authorizer)
-	ctx := context.Background()
+	ctx := g.GetContext()
 
 	accounts, err := g.getStorageAccounts()
 	if err != nil {
@@ -74,7 +73,7 @@ func (g StorageContainerGenerator) ListBlobContainers() ([]terraformutils.Resour
 }
 
 func (g *StorageContainerGenerator) getStorageAccounts() ([]storage.Account, error) {
-	ctx := context.Background()
+	ctx := g.GetContext()
 	accountsClient := storage.NewAccountsClient(g.Args["config"].(authentication.Config).SubscriptionID)
 
 	accountsClient.Authorizer = g.Args["authorizer"].(autorest.Authorizer)
@@ -15,7 +15,6 @@
 package azure
 
 import (
-	"context"
 	"log"
 
 	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2019-03-01/compute"
@@ -59,7 +58,7 @@ func (g VirtualMachineGenerator) createResources(virtualMachineListResultIterato
 }
 
 func (g *VirtualMachineGenerator) InitResources() error {
-	ctx := context.Background()
+	ctx := g.GetContext()
 	vmClient := compute.NewVirtualMachinesClient(g.Args["config"].(authentication.Config).SubscriptionID)
 
 	vmClient.Authorizer = g.Args["authorizer"].(autorest.Authorizer)
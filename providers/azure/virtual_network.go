@@ -47,7 +47,7 @@ func (g VirtualNetworkGenerator) createResources(ctx context.Context, iterator n
 }
 
 func (g *VirtualNetworkGenerator) InitResources() error {
-	ctx := context.Background()
+	ctx := g.GetContext()
 	virtualNetworkClient := network.NewVirtualNetworksClient(g.Args["config"].(authentication.Config).SubscriptionID)
 
 	virtualNetworkClient.Authorizer = g.Args["authorizer"].(autorest.Authorizer)
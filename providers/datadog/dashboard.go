@@ -15,7 +15,6 @@
 package datadog
 
 import (
-	"context"
 	"fmt"
 
 	datadogV1 "github.com/DataDog/datadog-api-client-go/api/v1/datadog"
@@ -58,7 +57,7 @@ func (g *DashboardGenerator) createResource(dashboardID string) terraformutils.R
 // Need Dashboard ID as ID for terraform resource
 func (g *DashboardGenerator) InitResources() error {
 	datadogClientV1 := g.Args["datadogClientV1"].(*datadogV1.APIClient)
-	authV1 := g.Args["authV1"].(context.Context)
+	authV1 := g.authContext("authV1")
 
 	resources := []terraformutils.Resource{}
 	for _, filter := range g.Filter {
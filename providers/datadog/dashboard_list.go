@@ -15,7 +15,6 @@
 package datadog
 
 import (
-	"context"
 	"fmt"
 	"strconv"
 
@@ -59,7 +58,7 @@ func (g *DashboardListGenerator) createResource(dashboardListID string) terrafor
 // Need DashboardList ID as ID for terraform resource
 func (g *DashboardListGenerator) InitResources() error {
 	datadogClientV1 := g.Args["datadogClientV1"].(*datadogV1.APIClient)
-	authV1 := g.Args["authV1"].(context.Context)
+	authV1 := g.authContext("authV1")
 
 	dlResponse, _, err := datadogClientV1.DashboardListsApi.ListDashboardLists(authV1).Execute()
 	if err != nil {
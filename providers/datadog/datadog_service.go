@@ -14,8 +14,30 @@
 
 package datadog
 
-import "github.com/GoogleCloudPlatform/terraformer/terraformutils"
+import (
+	"context"
+
+	"github.com/GoogleCloudPlatform/terraformer/terraformutils"
+)
 
 type DatadogService struct { //nolint
 	terraformutils.Service
 }
+
+// authContext returns the auth context saved in the args under key, ending with the context of the service
+func (s *DatadogService) authContext(key string) context.Context {
+	return serviceAuthContext{Context: s.GetContext(), auth: s.Args[key].(context.Context)}
+}
+
+// serviceAuthContext holds the API keys and servers of an auth context and the cancellation of a service
+type serviceAuthContext struct {
+	context.Context
+	auth context.Context
+}
+
+func (c serviceAuthContext) Value(key interface{}) interface{} {
+	if value := c.auth.Value(key); value != nil {
+		return value
+	}
+	return c.Context.Value(key)
+}
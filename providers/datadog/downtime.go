@@ -15,7 +15,6 @@
 package datadog
 
 import (
-	"context"
 	"fmt"
 	"strconv"
 
@@ -59,7 +58,7 @@ func (g *DowntimeGenerator) createResource(downtimeID string) terraformutils.Res
 // Need Downtime ID as ID for terraform resource
 func (g *DowntimeGenerator) InitResources() error {
 	datadogClientV1 := g.Args["datadogClientV1"].(*datadogV1.APIClient)
-	authV1 := g.Args["authV1"].(context.Context)
+	authV1 := g.authContext("authV1")
 
 	resources := []terraformutils.Resource{}
 	for _, filter := range g.Filter {
@@ -15,7 +15,6 @@
 package datadog
 
 import (
-	"context"
 	"fmt"
 
 	datadogV1 "github.com/DataDog/datadog-api-client-go/api/v1/datadog"
@@ -62,7 +61,7 @@ func (g *IntegrationAWSLambdaARNGenerator) createResource(resourceID string) ter
 // Need IntegrationAWSLambdaARN ID formatted as '<account_id>:<role_name>' as ID for terraform resource
 func (g *IntegrationAWSLambdaARNGenerator) InitResources() error {
 	datadogClientV1 := g.Args["datadogClientV1"].(*datadogV1.APIClient)
-	authV1 := g.Args["authV1"].(context.Context)
+	authV1 := g.authContext("authV1")
 
 	logCollections, _, err := datadogClientV1.AWSLogsIntegrationApi.ListAWSLogsIntegrations(authV1).Execute()
 	if err != nil {
@@ -15,7 +15,6 @@
 package datadog
 
 import (
-	"context"
 	"fmt"
 
 	datadogV1 "github.com/DataDog/datadog-api-client-go/api/v1/datadog"
@@ -58,7 +57,7 @@ func (g *IntegrationAzureGenerator) createResource(resourceID string) terraformu
 // Need IntegrationAzure ID formatted as '<tenant_name>:<client_id>' as ID for terraform resource
 func (g *IntegrationAzureGenerator) InitResources() error {
 	datadogClientV1 := g.Args["datadogClientV1"].(*datadogV1.APIClient)
-	authV1 := g.Args["authV1"].(context.Context)
+	authV1 := g.authContext("authV1")
 
 	integrations, _, err := datadogClientV1.AzureIntegrationApi.ListAzureIntegration(authV1).Execute()
 	if err != nil {
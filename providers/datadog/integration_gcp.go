@@ -15,7 +15,6 @@
 package datadog
 
 import (
-	"context"
 	"fmt"
 
 	datadogV1 "github.com/DataDog/datadog-api-client-go/api/v1/datadog"
@@ -58,7 +57,7 @@ func (g *IntegrationGCPGenerator) createResource(resourceID string) terraformuti
 // Need IntegrationGCP ID formatted as '<tenant_name>:<client_id>' as ID for terraform resource
 func (g *IntegrationGCPGenerator) InitResources() error {
 	datadogClientV1 := g.Args["datadogClientV1"].(*datadogV1.APIClient)
-	authV1 := g.Args["authV1"].(context.Context)
+	authV1 := g.authContext("authV1")
 
 	integrations, _, err := datadogClientV1.GCPIntegrationApi.ListGCPIntegration(authV1).Execute()
 	if err != nil {
@@ -15,7 +15,6 @@
 package datadog
 
 import (
-	"context"
 	"fmt"
 
 	datadogV2 "github.com/DataDog/datadog-api-client-go/api/v2/datadog"
@@ -58,7 +57,7 @@ func (g *LogsArchiveGenerator) createResource(logsArchiveID string) terraformuti
 // Need LogsArchive ID as ID for terraform resource
 func (g *LogsArchiveGenerator) InitResources() error {
 	datadogClientV2 := g.Args["datadogClientV2"].(*datadogV2.APIClient)
-	authV2 := g.Args["authV2"].(context.Context)
+	authV2 := g.authContext("authV2")
 
 	resources := []terraformutils.Resource{}
 	for _, filter := range g.Filter {
@@ -15,7 +15,6 @@
 package datadog
 
 import (
-	"context"
 	"encoding/json"
 	"fmt"
 	"strings"
@@ -63,7 +62,7 @@ func (g *LogsCustomPipelineGenerator) createResource(logsCustomPipelineID string
 // Need LogsPipeline ID as ID for terraform resource
 func (g *LogsCustomPipelineGenerator) InitResources() error {
 	datadogClientV1 := g.Args["datadogClientV1"].(*datadogV1.APIClient)
-	authV1 := g.Args["authV1"].(context.Context)
+	authV1 := g.authContext("authV1")
 
 	resources := []terraformutils.Resource{}
 	for _, filter := range g.Filter {
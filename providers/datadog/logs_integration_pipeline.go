@@ -15,8 +15,6 @@
 package datadog
 
 import (
-	"context"
-
 	datadogV1 "github.com/DataDog/datadog-api-client-go/api/v1/datadog"
 	"github.com/GoogleCloudPlatform/terraformer/terraformutils"
 )
@@ -60,7 +58,7 @@ func (g *LogsIntegrationPipelineGenerator) createResource(logsIntegrationPipelin
 // Need LogsPipeline ID as ID for terraform resource
 func (g *LogsIntegrationPipelineGenerator) InitResources() error {
 	datadogClientV1 := g.Args["datadogClientV1"].(*datadogV1.APIClient)
-	authV1 := g.Args["authV1"].(context.Context)
+	authV1 := g.authContext("authV1")
 
 	logsIntegrationPipelines, _, err := datadogClientV1.LogsPipelinesApi.ListLogsPipelines(authV1).Execute()
 	if err != nil {
@@ -15,7 +15,6 @@
 package datadog
 
 import (
-	"context"
 	"fmt"
 	"strconv"
 
@@ -62,7 +61,7 @@ func (g *MonitorGenerator) createResource(monitorID string) terraformutils.Resou
 // Need Monitor ID as ID for terraform resource
 func (g *MonitorGenerator) InitResources() error {
 	datadogClientV1 := g.Args["datadogClientV1"].(*datadogV1.APIClient)
-	authV1 := g.Args["authV1"].(context.Context)
+	authV1 := g.authContext("authV1")
 
 	resources := []terraformutils.Resource{}
 	for _, filter := range g.Filter {
@@ -15,7 +15,6 @@
 package datadog
 
 import (
-	"context"
 	"fmt"
 
 	datadogV2 "github.com/DataDog/datadog-api-client-go/api/v2/datadog"
@@ -60,7 +59,7 @@ func (g *RoleGenerator) createResource(roleID string) terraformutils.Resource {
 // Need Role ID as ID for terraform resource
 func (g *RoleGenerator) InitResources() error {
 	datadogClientV2 := g.Args["datadogClientV2"].(*datadogV2.APIClient)
-	authV2 := g.Args["authV2"].(context.Context)
+	authV2 := g.authContext("authV2")
 
 	pageSize := int64(100)
 	pageNumber := int64(0)
@@ -15,7 +15,6 @@
 package datadog
 
 import (
-	"context"
 	"fmt"
 
 	datadogV2 "github.com/DataDog/datadog-api-client-go/api/v2/datadog"
@@ -62,7 +61,7 @@ func (g *SecurityMonitoringDefaultRuleGenerator) InitResources() error {
 	var securityMonitoringRuleResponses []datadogV2.SecurityMonitoringRuleResponse
 
 	datadogClientV2 := g.Args["datadogClientV2"].(*datadogV2.APIClient)
-	authV2 := g.Args["authV2"].(context.Context)
+	authV2 := g.authContext("authV2")
 
 	pageSize := int64(1000)
 	pageNumber := int64(0)
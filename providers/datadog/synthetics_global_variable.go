@@ -15,7 +15,6 @@
 package datadog
 
 import (
-	"context"
 	"fmt"
 	"log"
 
@@ -59,7 +58,7 @@ func (g *SyntheticsGlobalVariableGenerator) createResource(globalVariableID stri
 // Need SyntheticsGlobalVariable ID as ID for terraform resource
 func (g *SyntheticsGlobalVariableGenerator) InitResources() error {
 	datadogClientV1 := g.Args["datadogClientV1"].(*datadogV1.APIClient)
-	authV1 := g.Args["authV1"].(context.Context)
+	authV1 := g.authContext("authV1")
 
 	var globalVariableIDs []datadogV1.SyntheticsGlobalVariable
 	for _, filter := range g.Filter {
@@ -15,7 +15,6 @@
 package datadog
 
 import (
-	"context"
 	"fmt"
 	"regexp"
 
@@ -61,7 +60,7 @@ func (g *SyntheticsPrivateLocationGenerator) createResource(plID string) terrafo
 // Need SyntheticsPrivateLocation ID as ID for terraform resource
 func (g *SyntheticsPrivateLocationGenerator) InitResources() error {
 	datadogClientV1 := g.Args["datadogClientV1"].(*datadogV1.APIClient)
-	authV1 := g.Args["authV1"].(context.Context)
+	authV1 := g.authContext("authV1")
 
 	data, _, err := datadogClientV1.SyntheticsApi.ListLocations(authV1).Execute()
 	if err != nil {
@@ -64,7 +64,7 @@ func (g AutoscalersGenerator) createResources(ctx context.Context, autoscalersLi
 // from each autoscalers create 1 TerraformResource
 // Need autoscalers name as ID for terraform resource
 func (g *AutoscalersGenerator) InitResources() error {
-	ctx := context.Background()
+	ctx := g.GetContext()
 	computeService, err := compute.NewService(ctx)
 	if err != nil {
 		return err
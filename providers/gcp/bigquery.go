@@ -93,7 +93,7 @@ func (g *BigQueryGenerator) createResourcesTables(ctx context.Context, datasetID
 
 // Generate TerraformResources from GCP API,
 func (g *BigQueryGenerator) InitResources() error {
-	ctx := context.Background()
+	ctx := g.GetContext()
 	bigQueryService, err := bigquery.NewService(ctx)
 	if err != nil {
 		return err
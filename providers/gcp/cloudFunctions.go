@@ -57,7 +57,7 @@ func (g CloudFunctionsGenerator) createResources(ctx context.Context, functionsL
 // from each CloudFunctions create 1 TerraformResource
 // Need CloudFunctions name as ID for terraform resource
 func (g *CloudFunctionsGenerator) InitResources() error {
-	ctx := context.Background()
+	ctx := g.GetContext()
 	cloudfunctionsService, err := cloudfunctions.NewService(ctx)
 	if err != nil {
 		return err
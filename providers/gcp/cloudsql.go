@@ -15,8 +15,6 @@
 package gcp
 
 import (
-	"context"
-
 	"github.com/GoogleCloudPlatform/terraformer/terraformutils"
 
 	sqladmin "google.golang.org/api/sqladmin/v1beta4"
@@ -31,7 +29,7 @@ type CloudSQLGenerator struct {
 }
 
 func (g *CloudSQLGenerator) loadDBInstances(svc *sqladmin.Service, project string) error {
-	dbInstances, err := svc.Instances.List(project).Do()
+	dbInstances, err := svc.Instances.List(project).Context(g.GetContext()).Do()
 	if err != nil {
 		return err
 	}
@@ -58,7 +56,7 @@ func (g *CloudSQLGenerator) loadDBInstances(svc *sqladmin.Service, project strin
 }
 
 func (g *CloudSQLGenerator) loadDBs(svc *sqladmin.Service, instanceName, project string) error {
-	DBs, err := svc.Databases.List(project, instanceName).Do()
+	DBs, err := svc.Databases.List(project, instanceName).Context(g.GetContext()).Do()
 	if err != nil {
 		return err
 	}
@@ -86,7 +84,7 @@ func (g *CloudSQLGenerator) loadDBs(svc *sqladmin.Service, instanceName, project
 // Need dbinstance name as ID for terraform resource
 func (g *CloudSQLGenerator) InitResources() error {
 	project := g.GetArgs()["project"].(string)
-	ctx := context.Background()
+	ctx := g.GetContext()
 	svc, err := sqladmin.NewService(ctx)
 	if err != nil {
 		return err
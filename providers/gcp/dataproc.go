@@ -91,7 +91,7 @@ func (g DataprocGenerator) createJobResources(jobList *dataproc.ProjectsRegionsJ
 // from each DataprocGenerator create 1 TerraformResource
 // Need DataprocGenerator name as ID for terraform resource
 func (g *DataprocGenerator) InitResources() error {
-	ctx := context.Background()
+	ctx := g.GetContext()
 	dataprocService, err := dataproc.NewService(ctx)
 	if err != nil {
 		return err
@@ -62,7 +62,7 @@ func (g ExternalVpnGatewaysGenerator) createResources(ctx context.Context, exter
 // from each externalVpnGateways create 1 TerraformResource
 // Need externalVpnGateways name as ID for terraform resource
 func (g *ExternalVpnGatewaysGenerator) InitResources() error {
-	ctx := context.Background()
+	ctx := g.GetContext()
 	computeService, err := compute.NewService(ctx)
 	if err != nil {
 		return err
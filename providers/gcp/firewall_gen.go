@@ -62,7 +62,7 @@ func (g FirewallGenerator) createResources(ctx context.Context, firewallList *co
 // from each firewall create 1 TerraformResource
 // Need firewall name as ID for terraform resource
 func (g *FirewallGenerator) InitResources() error {
-	ctx := context.Background()
+	ctx := g.GetContext()
 	computeService, err := compute.NewService(ctx)
 	if err != nil {
 		return err
@@ -98,7 +98,7 @@ func (g {{.titleResourceName}}Generator) createResources(ctx context.Context, {{
 // from each {{.resource}} create 1 TerraformResource
 // Need {{.resource}} name as ID for terraform resource
 func (g *{{.titleResourceName}}Generator) InitResources() error {
-	ctx := context.Background()
+	ctx := g.GetContext()
 	computeService, err := compute.NewService(ctx)
 	if err != nil {
 		return err
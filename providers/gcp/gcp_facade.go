@@ -1,6 +1,8 @@
 package gcp
 
 import (
+	"context"
+
 	"github.com/GoogleCloudPlatform/terraformer/terraformutils"
 	"github.com/GoogleCloudPlatform/terraformer/terraformutils/providerwrapper"
 )
@@ -37,6 +39,14 @@ func (s *GCPFacade) InitialCleanup() {
 	s.service.InitialCleanup()
 }
 
+func (s *GCPFacade) SetManagedResources(managed *terraformutils.ManagedResources) {
+	s.service.SetManagedResources(managed)
+}
+
+func (s *GCPFacade) SetContext(ctx context.Context) {
+	s.service.SetContext(ctx)
+}
+
 func (s *GCPFacade) PostRefreshCleanup() {
 	s.service.PostRefreshCleanup()
 }
@@ -73,6 +73,19 @@ func (p *GCPProvider) Init(args []string) error {
 	return nil
 }
 
+// GetKnownValues names the project and region found in self links and attributes of generated resources
+func (p *GCPProvider) GetKnownValues() map[string]string {
+	values := map[string]string{"project": p.projectName}
+	if p.region.Name != "" {
+		values["region"] = p.region.Name
+	}
+	return values
+}
+
+func (p *GCPProvider) GetRegion() string {
+	return p.region.Name
+}
+
 func (p *GCPProvider) GetName() string {
 	if p.providerType != "" {
 		return "google-" + p.providerType
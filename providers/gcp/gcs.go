@@ -118,7 +118,7 @@ func (g *GcsGenerator) createBucketsResources(ctx context.Context, gcsService *s
 
 func (g *GcsGenerator) createNotificationResources(gcsService *storage.Service, bucket *storage.Bucket) []terraformutils.Resource {
 	resources := []terraformutils.Resource{}
-	notificationList, err := gcsService.Notifications.List(bucket.Name).Do()
+	notificationList, err := gcsService.Notifications.List(bucket.Name).Context(g.GetContext()).Do()
 	if err != nil {
 		log.Println(err)
 		return resources
@@ -169,7 +169,7 @@ func (g *GcsGenerator) createTransferJobsResources(ctx context.Context, storageT
 // from each bucket  create 1 TerraformResource
 // Need bucket name as ID for terraform resource
 func (g *GcsGenerator) InitResources() error {
-	ctx := context.Background()
+	ctx := g.GetContext()
 	gcsService, err := storage.NewService(ctx)
 	if err != nil {
 		log.Print(err)
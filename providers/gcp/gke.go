@@ -15,7 +15,6 @@
 package gcp
 
 import (
-	"context"
 	"fmt"
 	"log"
 	"strconv"
@@ -92,7 +91,7 @@ func (g *GkeGenerator) initNodePools(nodePools []*container.NodePool, clusterNam
 
 // Generate TerraformResources from GCP API,
 func (g *GkeGenerator) InitResources() error {
-	ctx := context.Background()
+	ctx := g.GetContext()
 	service, err := container.NewService(ctx)
 	if err != nil {
 		log.Print(err)
@@ -100,7 +99,7 @@ func (g *GkeGenerator) InitResources() error {
 	}
 	// GKE support zone and regional cluster, api use location, it's can be region or zone, for all "-"
 	location := fmt.Sprintf("projects/%s/locations/%s", g.GetArgs()["project"].(string), "-")
-	clusters, err := service.Projects.Locations.Clusters.List(location).Do()
+	clusters, err := service.Projects.Locations.Clusters.List(location).Context(ctx).Do()
 	if err != nil {
 		log.Print(err)
 		return err
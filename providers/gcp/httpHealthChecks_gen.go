@@ -62,7 +62,7 @@ func (g HttpHealthChecksGenerator) createResources(ctx context.Context, httpHeal
 // from each httpHealthChecks create 1 TerraformResource
 // Need httpHealthChecks name as ID for terraform resource
 func (g *HttpHealthChecksGenerator) InitResources() error {
-	ctx := context.Background()
+	ctx := g.GetContext()
 	computeService, err := compute.NewService(ctx)
 	if err != nil {
 		return err
@@ -62,7 +62,7 @@ func (g HttpsHealthChecksGenerator) createResources(ctx context.Context, httpsHe
 // from each httpsHealthChecks create 1 TerraformResource
 // Need httpsHealthChecks name as ID for terraform resource
 func (g *HttpsHealthChecksGenerator) InitResources() error {
-	ctx := context.Background()
+	ctx := g.GetContext()
 	computeService, err := compute.NewService(ctx)
 	if err != nil {
 		return err
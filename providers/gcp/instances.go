@@ -68,7 +68,7 @@ func (g InstancesGenerator) createResources(ctx context.Context, instancesList *
 // from each instances create 1 TerraformResource
 // Need instances name as ID for terraform resource
 func (g *InstancesGenerator) InitResources() error {
-	ctx := context.Background()
+	ctx := g.GetContext()
 	computeService, err := compute.NewService(ctx)
 	if err != nil {
 		return err
@@ -65,7 +65,7 @@ func (g MemoryStoreGenerator) createResources(ctx context.Context, redisInstance
 // from each redis create 1 TerraformResource
 // Need Redis name as ID for terraform resource
 func (g *MemoryStoreGenerator) InitResources() error {
-	ctx := context.Background()
+	ctx := g.GetContext()
 	redisService, err := redis.NewService(ctx)
 	if err != nil {
 		return err
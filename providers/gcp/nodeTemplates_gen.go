@@ -62,7 +62,7 @@ func (g NodeTemplatesGenerator) createResources(ctx context.Context, nodeTemplat
 // from each nodeTemplates create 1 TerraformResource
 // Need nodeTemplates name as ID for terraform resource
 func (g *NodeTemplatesGenerator) InitResources() error {
-	ctx := context.Background()
+	ctx := g.GetContext()
 	computeService, err := compute.NewService(ctx)
 	if err != nil {
 		return err
@@ -62,7 +62,7 @@ func (g PacketMirroringsGenerator) createResources(ctx context.Context, packetMi
 // from each packetMirrorings create 1 TerraformResource
 // Need packetMirrorings name as ID for terraform resource
 func (g *PacketMirroringsGenerator) InitResources() error {
-	ctx := context.Background()
+	ctx := g.GetContext()
 	computeService, err := compute.NewService(ctx)
 	if err != nil {
 		return err
@@ -62,7 +62,7 @@ func (g RegionAutoscalersGenerator) createResources(ctx context.Context, regionA
 // from each regionAutoscalers create 1 TerraformResource
 // Need regionAutoscalers name as ID for terraform resource
 func (g *RegionAutoscalersGenerator) InitResources() error {
-	ctx := context.Background()
+	ctx := g.GetContext()
 	computeService, err := compute.NewService(ctx)
 	if err != nil {
 		return err
@@ -62,7 +62,7 @@ func (g RegionInstanceGroupManagersGenerator) createResources(ctx context.Contex
 // from each regionInstanceGroupManagers create 1 TerraformResource
 // Need regionInstanceGroupManagers name as ID for terraform resource
 func (g *RegionInstanceGroupManagersGenerator) InitResources() error {
-	ctx := context.Background()
+	ctx := g.GetContext()
 	computeService, err := compute.NewService(ctx)
 	if err != nil {
 		return err
@@ -62,7 +62,7 @@ func (g RegionTargetHttpProxiesGenerator) createResources(ctx context.Context, r
 // from each regionTargetHttpProxies create 1 TerraformResource
 // Need regionTargetHttpProxies name as ID for terraform resource
 func (g *RegionTargetHttpProxiesGenerator) InitResources() error {
-	ctx := context.Background()
+	ctx := g.GetContext()
 	computeService, err := compute.NewService(ctx)
 	if err != nil {
 		return err
@@ -62,7 +62,7 @@ func (g RoutesGenerator) createResources(ctx context.Context, routesList *comput
 // from each routes create 1 TerraformResource
 // Need routes name as ID for terraform resource
 func (g *RoutesGenerator) InitResources() error {
-	ctx := context.Background()
+	ctx := g.GetContext()
 	computeService, err := compute.NewService(ctx)
 	if err != nil {
 		return err
@@ -62,7 +62,7 @@ func (g TargetHttpProxiesGenerator) createResources(ctx context.Context, targetH
 // from each targetHttpProxies create 1 TerraformResource
 // Need targetHttpProxies name as ID for terraform resource
 func (g *TargetHttpProxiesGenerator) InitResources() error {
-	ctx := context.Background()
+	ctx := g.GetContext()
 	computeService, err := compute.NewService(ctx)
 	if err != nil {
 		return err
@@ -62,7 +62,7 @@ func (g TargetTcpProxiesGenerator) createResources(ctx context.Context, targetTc
 // from each targetTcpProxies create 1 TerraformResource
 // Need targetTcpProxies name as ID for terraform resource
 func (g *TargetTcpProxiesGenerator) InitResources() error {
-	ctx := context.Background()
+	ctx := g.GetContext()
 	computeService, err := compute.NewService(ctx)
 	if err != nil {
 		return err
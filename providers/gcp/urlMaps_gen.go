@@ -62,7 +62,7 @@ func (g UrlMapsGenerator) createResources(ctx context.Context, urlMapsList *comp
 // from each urlMaps create 1 TerraformResource
 // Need urlMaps name as ID for terraform resource
 func (g *UrlMapsGenerator) InitResources() error {
-	ctx := context.Background()
+	ctx := g.GetContext()
 	computeService, err := compute.NewService(ctx)
 	if err != nil {
 		return err
@@ -62,7 +62,7 @@ func (g VpnTunnelsGenerator) createResources(ctx context.Context, vpnTunnelsList
 // from each vpnTunnels create 1 TerraformResource
 // Need vpnTunnels name as ID for terraform resource
 func (g *VpnTunnelsGenerator) InitResources() error {
-	ctx := context.Background()
+	ctx := g.GetContext()
 	computeService, err := compute.NewService(ctx)
 	if err != nil {
 		return err
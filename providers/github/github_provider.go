@@ -42,6 +42,11 @@ func (p GithubProvider) GetProviderData(arg ...string) map[string]interface{} {
 	}
 }
 
+// GetRateLimits keeps refreshes under the GitHub API secondary rate limits
+func (p GithubProvider) GetRateLimits() terraformutils.RateLimits {
+	return terraformutils.RateLimits{Parallelism: 5, Rate: 5}
+}
+
 func (p *GithubProvider) GetConfig() cty.Value {
 	return cty.ObjectVal(map[string]cty.Value{
 		"organization": cty.StringVal(p.organization),
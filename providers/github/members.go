@@ -15,7 +15,6 @@
 package github
 
 import (
-	"context"
 	"log"
 
 	"github.com/GoogleCloudPlatform/terraformer/terraformutils"
@@ -31,7 +30,7 @@ type MembersGenerator struct {
 
 // InitResources generates TerraformResources from Github API,
 func (g *MembersGenerator) InitResources() error {
-	ctx := context.Background()
+	ctx := g.GetContext()
 	ts := oauth2.StaticTokenSource(
 		&oauth2.Token{AccessToken: g.Args["token"].(string)},
 	)
@@ -59,7 +58,6 @@ func (g *MembersGenerator) InitResources() error {
 				"github",
 				[]string{},
 			)
-			resource.SlowQueryRequired = true
 			g.Resources = append(g.Resources, resource)
 		}
 
@@ -15,7 +15,6 @@
 package github
 
 import (
-	"context"
 	"log"
 	"strconv"
 
@@ -31,7 +30,7 @@ type OrganizationWebhooksGenerator struct {
 
 // Generate TerraformResources from Github API,
 func (g *OrganizationWebhooksGenerator) InitResources() error {
-	ctx := context.Background()
+	ctx := g.GetContext()
 	ts := oauth2.StaticTokenSource(
 		&oauth2.Token{AccessToken: g.Args["token"].(string)},
 	)
@@ -57,7 +56,6 @@ func (g *OrganizationWebhooksGenerator) InitResources() error {
 				"github",
 				[]string{},
 			)
-			resource.SlowQueryRequired = true
 			g.Resources = append(g.Resources, resource)
 		}
 
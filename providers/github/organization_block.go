@@ -15,7 +15,6 @@
 package github
 
 import (
-	"context"
 	"log"
 
 	"github.com/GoogleCloudPlatform/terraformer/terraformutils"
@@ -30,7 +29,7 @@ type OrganizationBlockGenerator struct {
 
 // Generate TerraformResources from Github API,
 func (g *OrganizationBlockGenerator) InitResources() error {
-	ctx := context.Background()
+	ctx := g.GetContext()
 	ts := oauth2.StaticTokenSource(
 		&oauth2.Token{AccessToken: g.Args["token"].(string)},
 	)
@@ -56,7 +55,6 @@ func (g *OrganizationBlockGenerator) InitResources() error {
 				"github",
 				[]string{},
 			)
-			resource.SlowQueryRequired = true
 			g.Resources = append(g.Resources, resource)
 		}
 
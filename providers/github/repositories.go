@@ -30,7 +30,7 @@ type RepositoriesGenerator struct {
 
 // Generate TerraformResources from github API,
 func (g *RepositoriesGenerator) InitResources() error {
-	ctx := context.Background()
+	ctx := g.GetContext()
 	ts := oauth2.StaticTokenSource(
 		&oauth2.Token{AccessToken: g.GetArgs()["token"].(string)},
 	)
@@ -56,7 +56,6 @@ func (g *RepositoriesGenerator) InitResources() error {
 				"github",
 				[]string{},
 			)
-			resource.SlowQueryRequired = true
 			g.Resources = append(g.Resources, resource)
 			g.Resources = append(g.Resources, g.createRepositoryWebhookResources(ctx, client, repo)...)
 			g.Resources = append(g.Resources, g.createRepositoryBranchProtectionResources(ctx, client, repo)...)
@@ -39,7 +39,6 @@ func (g *TeamsGenerator) createTeamsResources(ctx context.Context, teams []*gith
 			"github",
 			[]string{},
 		)
-		resource.SlowQueryRequired = true
 		resources = append(resources, resource)
 		resources = append(resources, g.createTeamMembersResources(ctx, team, client)...)
 		resources = append(resources, g.createTeamRepositoriesResources(ctx, team, client)...)
@@ -85,7 +84,7 @@ func (g *TeamsGenerator) createTeamRepositoriesResources(ctx context.Context, te
 
 // InitResources generates TerraformResources from Github API,
 func (g *TeamsGenerator) InitResources() error {
-	ctx := context.Background()
+	ctx := g.GetContext()
 	ts := oauth2.StaticTokenSource(
 		&oauth2.Token{AccessToken: g.Args["token"].(string)},
 	)
@@ -15,8 +15,6 @@
 package gmailfilter
 
 import (
-	"context"
-
 	"github.com/GoogleCloudPlatform/terraformer/terraformutils"
 	"google.golang.org/api/gmail/v1"
 )
@@ -41,7 +39,7 @@ func (g FilterGenerator) createResources(filters []*gmail.Filter) []terraformuti
 }
 
 func (g *FilterGenerator) InitResources() error {
-	ctx := context.Background()
+	ctx := g.GetContext()
 	gmailService, err := g.gmailService(ctx)
 	if err != nil {
 		return err
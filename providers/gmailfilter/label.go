@@ -15,7 +15,6 @@
 package gmailfilter
 
 import (
-	"context"
 	"strings"
 
 	"github.com/GoogleCloudPlatform/terraformer/terraformutils"
@@ -45,7 +44,7 @@ func (g LabelGenerator) createResources(labels []*gmail.Label) []terraformutils.
 }
 
 func (g *LabelGenerator) InitResources() error {
-	ctx := context.Background()
+	ctx := g.GetContext()
 	gmailService, err := g.gmailService(ctx)
 	if err != nil {
 		return err
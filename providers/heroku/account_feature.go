@@ -15,8 +15,6 @@
 package heroku
 
 import (
-	"context"
-
 	"github.com/GoogleCloudPlatform/terraformer/terraformutils"
 	heroku "github.com/heroku/heroku-go/v5"
 )
@@ -42,7 +40,7 @@ func (g AccountFeatureGenerator) createResources(accountFeatureList []heroku.Acc
 
 func (g *AccountFeatureGenerator) InitResources() error {
 	svc := g.generateService()
-	output, err := svc.AccountFeatureList(context.TODO(), &heroku.ListRange{Field: "id"})
+	output, err := svc.AccountFeatureList(g.GetContext(), &heroku.ListRange{Field: "id"})
 	if err != nil {
 		return err
 	}
@@ -15,8 +15,6 @@
 package heroku
 
 import (
-	"context"
-
 	"github.com/GoogleCloudPlatform/terraformer/terraformutils"
 	heroku "github.com/heroku/heroku-go/v5"
 )
@@ -40,7 +38,7 @@ func (g AddOnAttachmentGenerator) createResources(addOnAttachmentList []heroku.A
 
 func (g *AddOnAttachmentGenerator) InitResources() error {
 	svc := g.generateService()
-	output, err := svc.AddOnAttachmentList(context.TODO(), &heroku.ListRange{Field: "id"})
+	output, err := svc.AddOnAttachmentList(g.GetContext(), &heroku.ListRange{Field: "id"})
 	if err != nil {
 		return err
 	}
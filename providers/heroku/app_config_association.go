@@ -15,7 +15,6 @@
 package heroku
 
 import (
-	"context"
 	"fmt"
 	"log"
 
@@ -30,7 +29,7 @@ type AppConfigAssociationGenerator struct {
 func (g AppConfigAssociationGenerator) createResources(svc *heroku.Service, appList []heroku.App) []terraformutils.Resource {
 	var resources []terraformutils.Resource
 	for _, app := range appList {
-		output, err := svc.ConfigVarInfoForApp(context.TODO(), app.ID)
+		output, err := svc.ConfigVarInfoForApp(g.GetContext(), app.ID)
 		if err != nil {
 			log.Println(err)
 		}
@@ -54,7 +53,7 @@ func (g AppConfigAssociationGenerator) createResources(svc *heroku.Service, appL
 
 func (g *AppConfigAssociationGenerator) InitResources() error {
 	svc := g.generateService()
-	output, err := svc.AppList(context.TODO(), &heroku.ListRange{Field: "id"})
+	output, err := svc.AppList(g.GetContext(), &heroku.ListRange{Field: "id"})
 	if err != nil {
 		return err
 	}
@@ -15,7 +15,6 @@
 package heroku
 
 import (
-	"context"
 	"fmt"
 	"log"
 
@@ -30,7 +29,7 @@ type AppFeatureGenerator struct {
 func (g AppFeatureGenerator) createResources(svc *heroku.Service, appList []heroku.App) []terraformutils.Resource {
 	var resources []terraformutils.Resource
 	for _, app := range appList {
-		output, err := svc.AppFeatureList(context.TODO(), app.ID, &heroku.ListRange{Field: "id"})
+		output, err := svc.AppFeatureList(g.GetContext(), app.ID, &heroku.ListRange{Field: "id"})
 		if err != nil {
 			log.Println(err)
 		}
@@ -48,7 +47,7 @@ func (g AppFeatureGenerator) createResources(svc *heroku.Service, appList []hero
 
 func (g *AppFeatureGenerator) InitResources() error {
 	svc := g.generateService()
-	output, err := svc.AppList(context.TODO(), &heroku.ListRange{Field: "id"})
+	output, err := svc.AppList(g.GetContext(), &heroku.ListRange{Field: "id"})
 	if err != nil {
 		return err
 	}
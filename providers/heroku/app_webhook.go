@@ -15,7 +15,6 @@
 package heroku
 
 import (
-	"context"
 	"log"
 
 	"github.com/GoogleCloudPlatform/terraformer/terraformutils"
@@ -29,7 +28,7 @@ type AppWebhookGenerator struct {
 func (g AppWebhookGenerator) createResources(svc *heroku.Service, appList []heroku.App) []terraformutils.Resource {
 	var resources []terraformutils.Resource
 	for _, app := range appList {
-		output, err := svc.AppWebhookList(context.TODO(), app.ID, &heroku.ListRange{Field: "id"})
+		output, err := svc.AppWebhookList(g.GetContext(), app.ID, &heroku.ListRange{Field: "id"})
 		if err != nil {
 			log.Println(err)
 		}
@@ -49,7 +48,7 @@ func (g AppWebhookGenerator) createResources(svc *heroku.Service, appList []hero
 
 func (g *AppWebhookGenerator) InitResources() error {
 	svc := g.generateService()
-	output, err := svc.AppList(context.TODO(), &heroku.ListRange{Field: "id"})
+	output, err := svc.AppList(g.GetContext(), &heroku.ListRange{Field: "id"})
 	if err != nil {
 		return err
 	}
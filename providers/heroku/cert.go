@@ -15,7 +15,6 @@
 package heroku
 
 import (
-	"context"
 	"log"
 
 	"github.com/GoogleCloudPlatform/terraformer/terraformutils"
@@ -29,7 +28,7 @@ type CertGenerator struct {
 func (g CertGenerator) createResources(svc *heroku.Service, appList []heroku.App) []terraformutils.Resource {
 	var resources []terraformutils.Resource
 	for _, app := range appList {
-		output, err := svc.SSLEndpointList(context.TODO(), app.ID, &heroku.ListRange{Field: "id"})
+		output, err := svc.SSLEndpointList(g.GetContext(), app.ID, &heroku.ListRange{Field: "id"})
 		if err != nil {
 			log.Println(err)
 		}
@@ -49,7 +48,7 @@ func (g CertGenerator) createResources(svc *heroku.Service, appList []heroku.App
 
 func (g *CertGenerator) InitResources() error {
 	svc := g.generateService()
-	output, err := svc.AppList(context.TODO(), &heroku.ListRange{Field: "id"})
+	output, err := svc.AppList(g.GetContext(), &heroku.ListRange{Field: "id"})
 	if err != nil {
 		return err
 	}
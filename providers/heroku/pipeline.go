@@ -15,8 +15,6 @@
 package heroku
 
 import (
-	"context"
-
 	"github.com/GoogleCloudPlatform/terraformer/terraformutils"
 	heroku "github.com/heroku/heroku-go/v5"
 )
@@ -40,7 +38,7 @@ func (g PipelineGenerator) createResources(pipelineList []heroku.Pipeline) []ter
 
 func (g *PipelineGenerator) InitResources() error {
 	svc := g.generateService()
-	output, err := svc.PipelineList(context.TODO(), &heroku.ListRange{Field: "id"})
+	output, err := svc.PipelineList(g.GetContext(), &heroku.ListRange{Field: "id"})
 	if err != nil {
 		return err
 	}
@@ -15,8 +15,6 @@
 package heroku
 
 import (
-	"context"
-
 	"github.com/GoogleCloudPlatform/terraformer/terraformutils"
 	heroku "github.com/heroku/heroku-go/v5"
 )
@@ -40,7 +38,7 @@ func (g PipelineCouplingGenerator) createResources(pipelineCouplingList []heroku
 
 func (g *PipelineCouplingGenerator) InitResources() error {
 	svc := g.generateService()
-	output, err := svc.PipelineCouplingList(context.TODO(), &heroku.ListRange{Field: "id"})
+	output, err := svc.PipelineCouplingList(g.GetContext(), &heroku.ListRange{Field: "id"})
 	if err != nil {
 		return err
 	}
@@ -15,7 +15,6 @@
 package ibm
 
 import (
-	"context"
 	"fmt"
 	"os"
 
@@ -103,7 +102,7 @@ func (g *KPGenerator) InitResources() error {
 		g.Resources = append(g.Resources, g.loadKP(kp.ID, kp.Guid))
 		client.Config.InstanceID = kp.Guid
 
-		output, err := client.GetKeys(context.Background(), 100, 0)
+		output, err := client.GetKeys(g.GetContext(), 100, 0)
 		if err != nil {
 			return err
 		}
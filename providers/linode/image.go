@@ -15,8 +15,6 @@
 package linode
 
 import (
-	"context"
-
 	"github.com/GoogleCloudPlatform/terraformer/terraformutils"
 	"github.com/linode/linodego"
 )
@@ -40,7 +38,7 @@ func (g ImageGenerator) createResources(imageList []linodego.Image) []terraformu
 
 func (g *ImageGenerator) InitResources() error {
 	client := g.generateClient()
-	output, err := client.ListImages(context.Background(), nil)
+	output, err := client.ListImages(g.GetContext(), nil)
 	if err != nil {
 		return err
 	}
@@ -15,7 +15,6 @@
 package linode
 
 import (
-	"context"
 	"strconv"
 
 	"github.com/GoogleCloudPlatform/terraformer/terraformutils"
@@ -41,7 +40,7 @@ func (g InstanceGenerator) createResources(instanceList []linodego.Instance) []t
 
 func (g *InstanceGenerator) InitResources() error {
 	client := g.generateClient()
-	output, err := client.ListInstances(context.Background(), nil)
+	output, err := client.ListInstances(g.GetContext(), nil)
 	if err != nil {
 		return err
 	}
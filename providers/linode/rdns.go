@@ -15,8 +15,6 @@
 package linode
 
 import (
-	"context"
-
 	"github.com/GoogleCloudPlatform/terraformer/terraformutils"
 	"github.com/linode/linodego"
 )
@@ -40,7 +38,7 @@ func (g RDNSGenerator) createResources(instanceIPList []linodego.InstanceIP) []t
 
 func (g *RDNSGenerator) InitResources() error {
 	client := g.generateClient()
-	output, err := client.ListIPAddresses(context.Background(), nil)
+	output, err := client.ListIPAddresses(g.GetContext(), nil)
 	if err != nil {
 		return err
 	}
@@ -38,7 +38,6 @@ func (g *DashboardGenerator) createDashboardResources(client *newrelic.Client) e
 			"newrelic_dashboard",
 			g.ProviderName,
 			[]string{})
-		resource.SlowQueryRequired = true
 		g.Resources = append(g.Resources, resource)
 	}
 
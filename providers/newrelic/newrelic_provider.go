@@ -40,6 +40,13 @@ func (NewRelicProvider) GetResourceConnections() map[string]map[string][]string
 	return map[string]map[string][]string{}
 }
 
+// GetRateLimits slows down dashboards, read through a heavily throttled API
+func (NewRelicProvider) GetRateLimits() terraformutils.RateLimits {
+	return terraformutils.RateLimits{
+		ResourceTypes: map[string]float64{"newrelic_dashboard": 5},
+	}
+}
+
 func (p *NewRelicProvider) GetSupportedService() map[string]terraformutils.ServiceGenerator {
 	return map[string]terraformutils.ServiceGenerator{
 		"alert":      &AlertGenerator{},
@@ -15,8 +15,6 @@
 package vultr
 
 import (
-	"context"
-
 	"github.com/GoogleCloudPlatform/terraformer/terraformutils"
 	"github.com/vultr/govultr"
 )
@@ -40,7 +38,7 @@ func (g BareMetalServerGenerator) createResources(serverList []govultr.BareMetal
 
 func (g *BareMetalServerGenerator) InitResources() error {
 	client := g.generateClient()
-	output, err := client.BareMetalServer.List(context.Background())
+	output, err := client.BareMetalServer.List(g.GetContext())
 	if err != nil {
 		return err
 	}
@@ -15,8 +15,6 @@
 package vultr
 
 import (
-	"context"
-
 	"github.com/GoogleCloudPlatform/terraformer/terraformutils"
 	"github.com/vultr/govultr"
 )
@@ -40,7 +38,7 @@ func (g NetworkGenerator) createResources(networkList []govultr.Network) []terra
 
 func (g *NetworkGenerator) InitResources() error {
 	client := g.generateClient()
-	output, err := client.Network.List(context.Background())
+	output, err := client.Network.List(g.GetContext())
 	if err != nil {
 		return err
 	}
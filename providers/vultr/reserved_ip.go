@@ -15,8 +15,6 @@
 package vultr
 
 import (
-	"context"
-
 	"github.com/GoogleCloudPlatform/terraformer/terraformutils"
 	"github.com/vultr/govultr"
 )
@@ -40,7 +38,7 @@ func (g ReservedIPGenerator) createResources(ipList []govultr.ReservedIP) []terr
 
 func (g *ReservedIPGenerator) InitResources() error {
 	client := g.generateClient()
-	output, err := client.ReservedIP.List(context.Background())
+	output, err := client.ReservedIP.List(g.GetContext())
 	if err != nil {
 		return err
 	}
@@ -15,8 +15,6 @@
 package vultr
 
 import (
-	"context"
-
 	"github.com/GoogleCloudPlatform/terraformer/terraformutils"
 	"github.com/vultr/govultr"
 )
@@ -40,7 +38,7 @@ func (g SnapshotGenerator) createResources(snapshotList []govultr.Snapshot) []te
 
 func (g *SnapshotGenerator) InitResources() error {
 	client := g.generateClient()
-	output, err := client.Snapshot.List(context.Background())
+	output, err := client.Snapshot.List(g.GetContext())
 	if err != nil {
 		return err
 	}
@@ -15,8 +15,6 @@
 package vultr
 
 import (
-	"context"
-
 	"github.com/GoogleCloudPlatform/terraformer/terraformutils"
 	"github.com/vultr/govultr"
 )
@@ -40,7 +38,7 @@ func (g UserGenerator) createResources(userList []govultr.User) []terraformutils
 
 func (g *UserGenerator) InitResources() error {
 	client := g.generateClient()
-	output, err := client.User.List(context.Background())
+	output, err := client.User.List(g.GetContext())
 	if err != nil {
 		return err
 	}
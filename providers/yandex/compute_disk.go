@@ -15,8 +15,6 @@
 package yandex
 
 import (
-	"context"
-
 	"github.com/GoogleCloudPlatform/terraformer/terraformutils"
 	"github.com/yandex-cloud/go-genproto/yandex/cloud/compute/v1"
 	ycsdk "github.com/yandex-cloud/go-sdk"
@@ -30,7 +28,7 @@ func (g *DiskGenerator) loadDisks(sdk *ycsdk.SDK, folderID string) ([]*compute.D
 	disks := []*compute.Disk{}
 	pageToken := ""
 	for {
-		resp, err := sdk.Compute().Disk().List(context.Background(), &compute.ListDisksRequest{
+		resp, err := sdk.Compute().Disk().List(g.GetContext(), &compute.ListDisksRequest{
 			FolderId:  folderID,
 			PageSize:  defaultPageSize,
 			PageToken: pageToken,
@@ -52,7 +50,7 @@ func (g *DiskGenerator) loadDisks(sdk *ycsdk.SDK, folderID string) ([]*compute.D
 }
 
 func (g *DiskGenerator) InitResources() error {
-	sdk, err := ycsdk.Build(context.Background(), ycsdk.Config{
+	sdk, err := ycsdk.Build(g.GetContext(), ycsdk.Config{
 		Credentials: ycsdk.OAuthToken(g.Args["token"].(string)),
 	})
 	if err != nil {
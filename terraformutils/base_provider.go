@@ -32,6 +32,19 @@ type ProviderGenerator interface {
 	GetResourceConnections() map[string]map[string][]string
 }
 
+// RateLimits are the defaults of a provider for refreshing resources: number of workers,
+// requests per second overall and per resource type. 0 means the terraformer default, unlimited for rates.
+type RateLimits struct {
+	Parallelism   int
+	Rate          float64
+	ResourceTypes map[string]float64
+}
+
+// RateLimitedProvider is implemented by providers whose APIs need slower refreshes than the defaults
+type RateLimitedProvider interface {
+	GetRateLimits() RateLimits
+}
+
 type Provider struct {
 	Service ServiceGenerator
 	Config  cty.Value
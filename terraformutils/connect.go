@@ -14,7 +14,47 @@
 
 package terraformutils
 
+// LinkFunc renders the reference from a resource of service from to the key attribute of
+// resourceToMap, a resource of service to
+type LinkFunc func(from, to string, resourceToMap Resource, key string) string
+
 func ConnectServices(importResources map[string][]Resource, isServicePath bool, resourceConnections map[string]map[string][]string) map[string][]Resource {
+	return ConnectServicesWithLink(importResources, resourceConnections, ServiceLinkFunc(isServicePath))
+}
+
+// ServiceLinkFunc links services written to their own directory through their remote state,
+// resources in the same directory are referenced directly
+func ServiceLinkFunc(isServicePath bool) LinkFunc {
+	return func(from, to string, resourceToMap Resource, key string) string {
+		if !isServicePath || from == to {
+			return "${" + resourceToMap.Address() + "." + key + "}"
+		}
+		return RemoteStateLink(to, resourceToMap, key)
+	}
+}
+
+// RemoteConnections returns the connections between services in different directories, those exposed as outputs
+func RemoteConnections(resourceConnections map[string]map[string][]string, isServicePath bool) map[string]map[string][]string {
+	remote := map[string]map[string][]string{}
+	if !isServicePath {
+		return remote
+	}
+	for from, connections := range resourceConnections {
+		for to, pairs := range connections {
+			if from == to {
+				continue
+			}
+			if remote[from] == nil {
+				remote[from] = map[string][]string{}
+			}
+			remote[from][to] = pairs
+		}
+	}
+	return remote
+}
+
+// ConnectServicesWithLink replaces attribute values matching resources of connected services with references built by link
+func ConnectServicesWithLink(importResources map[string][]Resource, resourceConnections map[string]map[string][]string, link LinkFunc) map[string][]Resource {
 	for resource, connection := range resourceConnections {
 		if _, exist := importResources[resource]; exist {
 			for k, connectionPairs := range connection {
@@ -25,11 +65,7 @@ func ConnectServices(importResources map[string][]Resource, isServicePath bool,
 					for i := 0; i < len(connectionPairs)/2; i++ {
 						connectionPair := []string{connectionPairs[i*2], connectionPairs[i*2+1]}
 						for _, ccc := range cc {
-							if !isServicePath {
-								mapResource(importResources, resource, connectionPair, ccc, "local")
-							} else {
-								mapResource(importResources, resource, connectionPair, ccc, k)
-							}
+							mapResource(importResources, resource, connectionPair, ccc, k, link)
 						}
 					}
 				}
@@ -39,18 +75,27 @@ func ConnectServices(importResources map[string][]Resource, isServicePath bool,
 	return importResources
 }
 
-func mapResource(importResources map[string][]Resource, resource string, connectionPair []string, resourceToMap Resource, k string) {
+// OutputName is the name of the output exposing the key attribute of a resource to other services
+func OutputName(r Resource, key string) string {
+	return r.InstanceInfo.Type + "_" + r.ResourceName + "_" + key
+}
+
+// RemoteStateLink references the output of a resource through the terraform_remote_state data source of its service
+func RemoteStateLink(service string, r Resource, key string) string {
+	return "${data.terraform_remote_state." + service + ".outputs." + OutputName(r, key) + "}"
+}
+
+func mapResource(importResources map[string][]Resource, resource string, connectionPair []string, resourceToMap Resource, k string, link LinkFunc) {
 	for i := range importResources[resource] {
 		key := connectionPair[1]
 		if connectionPair[1] == "self_link" || connectionPair[1] == "id" {
 			key = resourceToMap.GetIDKey()
 		}
 		mappingResourceAttr := WalkAndGet(key, resourceToMap.InstanceState.Attributes)
-		keyValue := resourceToMap.InstanceInfo.Type + "_" + resourceToMap.ResourceName + "_" + key
-		linkValue := "${data.terraform_remote_state." + k + ".outputs." + keyValue + "}"
 
 		if len(mappingResourceAttr) == 1 {
 			resourceIdentifier := mappingResourceAttr[0].(string)
+			linkValue := link(resource, k, resourceToMap, key)
 			WalkAndOverride(connectionPair[0], resourceIdentifier, linkValue, importResources[resource][i].Item)
 		}
 	}
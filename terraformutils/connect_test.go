@@ -161,3 +161,73 @@ type MockedFlatmapParser struct {
 func (p *MockedFlatmapParser) Parse(ty cty.Type) (map[string]interface{}, error) {
 	return p.attributesParsed, nil
 }
+
+func TestModuleInputReference(t *testing.T) {
+	importResources := map[string][]Resource{
+		"type1": {prepare("ID1", "type1", map[string]string{
+			"type2_ref": "ID2",
+		}, map[string]interface{}{
+			"type2_ref": "ID2",
+		})},
+		"type2": {prepareNoAttrs("ID2", "type2")},
+	}
+
+	resourceConnections := map[string]map[string][]string{
+		"type1": {
+			"type2": {"type2_ref", "id"},
+		},
+	}
+	links := [][]string{}
+	resources := ConnectServicesWithLink(importResources, resourceConnections, func(from, to string, resourceToMap Resource, key string) string {
+		links = append(links, []string{from, to})
+		return "${var." + OutputName(resourceToMap, key) + "}"
+	})
+
+	if !reflect.DeepEqual(resources["type1"][0].Item, map[string]interface{}{
+		"type2_ref": "${var.type2_tfer--name-002D-type2_id}",
+	}) {
+		t.Errorf("failed to connect %v", resources["type1"][0].Item)
+	}
+	if !reflect.DeepEqual(links, [][]string{{"type1", "type2"}}) {
+		t.Errorf("unexpected links %v", links)
+	}
+}
+
+func TestSharedDirectoryReference(t *testing.T) {
+	importResources := map[string][]Resource{
+		"type1": {prepare("ID1", "type1", map[string]string{
+			"type2_ref": "ID2",
+		}, map[string]interface{}{
+			"type2_ref": "ID2",
+		})},
+		"type2": {prepareNoAttrs("ID2", "type2")},
+	}
+
+	resourceConnections := map[string]map[string][]string{
+		"type1": {
+			"type2": {"type2_ref", "id"},
+		},
+	}
+	resources := ConnectServices(importResources, false, resourceConnections)
+
+	if !reflect.DeepEqual(resources["type1"][0].Item, map[string]interface{}{
+		"type2_ref": "${type2.tfer--name-002D-type2.id}",
+	}) {
+		t.Errorf("failed to connect %v", resources["type1"][0].Item)
+	}
+	if remote := RemoteConnections(resourceConnections, false); len(remote) != 0 {
+		t.Errorf("unexpected remote connections %v", remote)
+	}
+}
+
+func TestRemoteConnections(t *testing.T) {
+	remote := RemoteConnections(map[string]map[string][]string{
+		"type1": {
+			"type1": {"parent_id", "id"},
+			"type2": {"type2_ref", "id"},
+		},
+	}, true)
+	if !reflect.DeepEqual(remote, map[string]map[string][]string{"type1": {"type2": {"type2_ref", "id"}}}) {
+		t.Errorf("unexpected remote connections %v", remote)
+	}
+}
@@ -0,0 +1,162 @@
+// Copyright 2018 The Terraformer Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package terraformutils
+
+import (
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// DriftReport groups the differences between a generated state and live infrastructure
+type DriftReport struct {
+	New     []DriftedResource `json:"new"`
+	Deleted []DriftedResource `json:"deleted"`
+	Changed []DriftedResource `json:"changed"`
+}
+
+type DriftedResource struct {
+	Address string            `json:"address"`
+	Type    string            `json:"type"`
+	ID      string            `json:"id"`
+	Changes []AttributeChange `json:"changes,omitempty"`
+}
+
+// AttributeChange is a flatmap attribute that differs, Before or After is nil when the attribute is missing
+type AttributeChange struct {
+	Attribute string  `json:"attribute"`
+	Before    *string `json:"before"`
+	After     *string `json:"after"`
+}
+
+func (d DriftReport) HasDrift() bool {
+	return len(d.New) > 0 || len(d.Deleted) > 0 || len(d.Changed) > 0
+}
+
+// Merge appends the drift of another directory to the report
+func (d *DriftReport) Merge(other DriftReport) {
+	d.New = append(d.New, other.New...)
+	d.Deleted = append(d.Deleted, other.Deleted...)
+	d.Changed = append(d.Changed, other.Changed...)
+}
+
+// DetectDrift compares resources read from a generated state with refreshed live resources.
+// Resources are matched by type and ID, attributes matching IgnoreKeys of either side are skipped.
+func DetectDrift(stored, live []Resource) DriftReport {
+	report := DriftReport{
+		New:     []DriftedResource{},
+		Deleted: []DriftedResource{},
+		Changed: []DriftedResource{},
+	}
+	storedByKey := map[string]Resource{}
+	for _, r := range stored {
+		storedByKey[resourceKey(r)] = r
+	}
+	seen := map[string]bool{}
+	for _, r := range live {
+		key := resourceKey(r)
+		seen[key] = true
+		storedResource, exist := storedByKey[key]
+		if !exist {
+			report.New = append(report.New, newDriftedResource(r))
+			continue
+		}
+		changes := attributeChanges(storedResource, r)
+		if len(changes) > 0 {
+			drifted := newDriftedResource(storedResource)
+			drifted.Changes = changes
+			report.Changed = append(report.Changed, drifted)
+		}
+	}
+	for _, r := range stored {
+		if !seen[resourceKey(r)] {
+			report.Deleted = append(report.Deleted, newDriftedResource(r))
+		}
+	}
+	for _, resources := range [][]DriftedResource{report.New, report.Deleted, report.Changed} {
+		sort.Slice(resources, func(i, j int) bool {
+			return resources[i].Address < resources[j].Address
+		})
+	}
+	return report
+}
+
+func newDriftedResource(r Resource) DriftedResource {
+	return DriftedResource{
+		Address: r.Address(),
+		Type:    r.InstanceInfo.Type,
+		ID:      r.InstanceState.ID,
+	}
+}
+
+func attributeChanges(stored, live Resource) []AttributeChange {
+	ignoreKeys := []*regexp.Regexp{}
+	for _, pattern := range append(append([]string{}, stored.IgnoreKeys...), live.IgnoreKeys...) {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			log.Printf("invalid ignore key %s for %s: %s", pattern, live.Address(), err)
+			continue
+		}
+		ignoreKeys = append(ignoreKeys, re)
+	}
+	keys := map[string]bool{}
+	for k := range stored.InstanceState.Attributes {
+		keys[k] = true
+	}
+	for k := range live.InstanceState.Attributes {
+		keys[k] = true
+	}
+	changes := []AttributeChange{}
+	for k := range keys {
+		if isIgnoredDriftKey(k, ignoreKeys) {
+			continue
+		}
+		before, beforeExist := stored.InstanceState.Attributes[k]
+		after, afterExist := live.InstanceState.Attributes[k]
+		if beforeExist == afterExist && before == after {
+			continue
+		}
+		// empty and missing values are the same once written to configuration
+		if before == "" && after == "" {
+			continue
+		}
+		change := AttributeChange{Attribute: k}
+		if beforeExist {
+			change.Before = &before
+		}
+		if afterExist {
+			change.After = &after
+		}
+		changes = append(changes, change)
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Attribute < changes[j].Attribute
+	})
+	return changes
+}
+
+func isIgnoredDriftKey(key string, ignoreKeys []*regexp.Regexp) bool {
+	// element counts follow the elements themselves
+	if key == "id" || strings.HasSuffix(key, ".#") || strings.HasSuffix(key, ".%") {
+		return true
+	}
+	for _, re := range ignoreKeys {
+		if re.MatchString(key) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,84 @@
+// Copyright 2018 The Terraformer Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package terraformutils
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDetectDrift(t *testing.T) {
+	stored := []Resource{
+		prepare("ID1", "type1", map[string]string{"name": "a", "tags.%": "1", "tags.Owner": "me", "etag": "1"}, map[string]interface{}{}),
+		prepare("ID2", "type1", map[string]string{"name": "b"}, map[string]interface{}{}),
+		prepare("ID3", "type2", map[string]string{"name": "c"}, map[string]interface{}{}),
+	}
+	live := []Resource{
+		prepare("ID1", "type1", map[string]string{"name": "a", "tags.%": "1", "tags.Owner": "you", "etag": "2", "description": ""}, map[string]interface{}{}),
+		prepare("ID3", "type2", map[string]string{"name": "c", "size": "10"}, map[string]interface{}{}),
+		prepare("ID4", "type2", map[string]string{"name": "d"}, map[string]interface{}{}),
+	}
+	live[0].IgnoreKeys = []string{"^etag$"}
+
+	report := DetectDrift(stored, live)
+	if !report.HasDrift() {
+		t.Fatal("expected drift")
+	}
+	if len(report.New) != 1 || report.New[0].ID != "ID4" {
+		t.Errorf("unexpected new resources %v", report.New)
+	}
+	if len(report.Deleted) != 1 || report.Deleted[0].ID != "ID2" {
+		t.Errorf("unexpected deleted resources %v", report.Deleted)
+	}
+	owner, you, size := "me", "you", "10"
+	expected := []DriftedResource{
+		{
+			Address: "type1.tfer--name-002D-type1",
+			Type:    "type1",
+			ID:      "ID1",
+			Changes: []AttributeChange{{Attribute: "tags.Owner", Before: &owner, After: &you}},
+		},
+		{
+			Address: "type2.tfer--name-002D-type2",
+			Type:    "type2",
+			ID:      "ID3",
+			Changes: []AttributeChange{{Attribute: "size", After: &size}},
+		},
+	}
+	if !reflect.DeepEqual(report.Changed, expected) {
+		t.Errorf("unexpected changes %v", report.Changed)
+	}
+
+	if DetectDrift(stored, stored).HasDrift() {
+		t.Error("expected no drift between identical resources")
+	}
+}
+
+func TestDetectDriftNormalization(t *testing.T) {
+	stored := []Resource{prepare("ID1", "type1", map[string]string{"name": "a", "etag": "1", "password": ""}, map[string]interface{}{})}
+	live := []Resource{prepare("ID1", "type1", map[string]string{"name": "a", "etag": "2", "password": "hunter22"}, map[string]interface{}{})}
+	stored[0].IgnoreKeys = []string{"^etag$"}
+	patterns, err := CompileSecretPatterns([]string{`^type1\.password$`})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	StripSecrets(stored, nil, patterns)
+	StripSecrets(live, nil, patterns)
+
+	if report := DetectDrift(stored, live); report.HasDrift() {
+		t.Errorf("ignore keys of the stored resource and stripped secrets expected to be skipped, got %v", report.Changed)
+	}
+}
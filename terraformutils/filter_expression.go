@@ -0,0 +1,566 @@
+// Copyright 2018 The Terraformer Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package terraformutils
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// ExpressionFilterPrefix starts filters written as expressions, e.g. Expr=tags.Env != 'prod',
+// optionally after Type=<service>; as other filters
+const ExpressionFilterPrefix = "Expr="
+
+var comparisonOperators = map[string]bool{"==": true, "!=": true, "=~": true, "!~": true, "<": true, "<=": true, ">": true, ">=": true}
+
+// dateLayouts are the formats of dates in expressions and attributes
+var dateLayouts = []string{time.RFC3339Nano, "2006-01-02T15:04:05", "2006-01-02 15:04:05", "2006-01-02"}
+
+// FilterExpression is a parsed filter expression. Paths are looked up in the attributes of the
+// refreshed state, then in Item, and match when any of their values satisfies the comparison.
+type FilterExpression struct {
+	Raw  string
+	root filterNode
+}
+
+type filterNode interface {
+	match(r Resource) bool
+}
+
+type notNode struct {
+	node filterNode
+}
+
+type andNode struct {
+	left, right filterNode
+}
+
+type orNode struct {
+	left, right filterNode
+}
+
+type hasNode struct {
+	path string
+}
+
+type comparisonNode struct {
+	path     string
+	operator string
+	values   []filterValue
+	regexp   *regexp.Regexp
+}
+
+// filterValue is a literal, number and date are set when the literal is one
+type filterValue struct {
+	text   string
+	number *float64
+	date   *time.Time
+}
+
+// ParseFilterExpression parses expressions such as
+// tags.Env != 'prod' && (name =~ '^team-a-' || created_date > date('2021-01-01'))
+func ParseFilterExpression(raw string) (*FilterExpression, error) {
+	tokens, err := tokenizeFilter(raw)
+	if err != nil {
+		return nil, err
+	}
+	p := &filterParser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if !p.done() {
+		return nil, fmt.Errorf("unexpected %q at position %d", p.peek().text, p.peek().position)
+	}
+	return &FilterExpression{Raw: raw, root: root}, nil
+}
+
+// Match returns whether the resource satisfies the expression
+func (e *FilterExpression) Match(r Resource) bool {
+	return e.root.match(r)
+}
+
+func (e *FilterExpression) String() string {
+	return e.Raw
+}
+
+func (n notNode) match(r Resource) bool {
+	return !n.node.match(r)
+}
+
+func (n andNode) match(r Resource) bool {
+	return n.left.match(r) && n.right.match(r)
+}
+
+func (n orNode) match(r Resource) bool {
+	return n.left.match(r) || n.right.match(r)
+}
+
+func (n hasNode) match(r Resource) bool {
+	if n.path == "id" {
+		return r.InstanceState != nil && r.InstanceState.ID != ""
+	}
+	if r.InstanceState != nil && WalkAndCheckField(n.path, r.InstanceState.Attributes) {
+		return true
+	}
+	return WalkAndCheckField(n.path, r.Item)
+}
+
+func (n comparisonNode) match(r Resource) bool {
+	values := filterPathValues(n.path, r)
+	switch n.operator {
+	case "!=":
+		return !n.any(values, "==")
+	case "!~":
+		return !n.any(values, "=~")
+	default:
+		return n.any(values, n.operator)
+	}
+}
+
+func (n comparisonNode) any(values []string, operator string) bool {
+	for _, value := range values {
+		for _, literal := range n.values {
+			if compareFilterValue(value, operator, literal, n.regexp) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// filterPathValues returns the string values of path, id is the ID of the resource
+func filterPathValues(path string, r Resource) []string {
+	if path == "id" && r.InstanceState != nil {
+		return []string{r.InstanceState.ID}
+	}
+	var values []interface{}
+	if r.InstanceState != nil {
+		values = WalkAndGet(path, r.InstanceState.Attributes)
+	}
+	if len(values) == 0 {
+		values = WalkAndGet(path, r.Item)
+	}
+	strs := []string{}
+	for _, value := range values {
+		if s, ok := value.(string); ok {
+			strs = append(strs, s)
+		}
+	}
+	return strs
+}
+
+// compareFilterValue compares an attribute with a literal as a date or number when the literal is one
+func compareFilterValue(value, operator string, literal filterValue, re *regexp.Regexp) bool {
+	if operator == "=~" {
+		return re.MatchString(value)
+	}
+	var comparison int
+	switch {
+	case literal.date != nil:
+		date, ok := parseFilterDate(value)
+		if !ok {
+			return false
+		}
+		comparison = compareTimes(date, *literal.date)
+	case literal.number != nil:
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return operator == "==" && value == literal.text
+		}
+		comparison = compareNumbers(number, *literal.number)
+	default:
+		comparison = strings.Compare(value, literal.text)
+	}
+	switch operator {
+	case "==", "in":
+		return comparison == 0
+	case "<":
+		return comparison < 0
+	case "<=":
+		return comparison <= 0
+	case ">":
+		return comparison > 0
+	case ">=":
+		return comparison >= 0
+	}
+	return false
+}
+
+func compareTimes(a, b time.Time) int {
+	switch {
+	case a.Before(b):
+		return -1
+	case a.After(b):
+		return 1
+	}
+	return 0
+}
+
+func compareNumbers(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func parseFilterDate(value string) (time.Time, bool) {
+	for _, layout := range dateLayouts {
+		if date, err := time.Parse(layout, value); err == nil {
+			return date, true
+		}
+	}
+	return time.Time{}, false
+}
+
+type filterToken struct {
+	kind     string
+	text     string
+	position int
+}
+
+const (
+	tokenPath     = "path"
+	tokenString   = "string"
+	tokenNumber   = "number"
+	tokenOperator = "operator"
+)
+
+func isPathRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("_.-:/@*", r)
+}
+
+func tokenizeFilter(raw string) ([]filterToken, error) {
+	tokens := []filterToken{}
+	runes := []rune(raw)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '\'' || r == '"' || r == '`':
+			start := i
+			text := strings.Builder{}
+			for i++; i < len(runes) && runes[i] != r; i++ {
+				// only quotes and backslashes are escaped, regular expressions keep theirs
+				if runes[i] == '\\' && i+1 < len(runes) && (runes[i+1] == r || runes[i+1] == '\\') {
+					i++
+				}
+				text.WriteRune(runes[i])
+			}
+			if i == len(runes) {
+				return nil, fmt.Errorf("unterminated string at position %d", start)
+			}
+			i++
+			kind := tokenString
+			if r == '`' {
+				kind = tokenPath
+			}
+			tokens = append(tokens, filterToken{kind: kind, text: text.String(), position: start})
+		case unicode.IsDigit(r) || (r == '-' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			start := i
+			for i++; i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.'); i++ {
+			}
+			tokens = append(tokens, filterToken{kind: tokenNumber, text: string(runes[start:i]), position: start})
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i++; i < len(runes) && isPathRune(runes[i]); i++ {
+			}
+			tokens = append(tokens, filterToken{kind: tokenPath, text: string(runes[start:i]), position: start})
+		default:
+			operator := ""
+			for _, candidate := range []string{"&&", "||", "==", "!=", "=~", "!~", "<=", ">=", "<", ">", "!", "(", ")", "[", "]", ","} {
+				if strings.HasPrefix(string(runes[i:]), candidate) {
+					operator = candidate
+					break
+				}
+			}
+			if operator == "" {
+				return nil, fmt.Errorf("unexpected %q at position %d", r, i)
+			}
+			tokens = append(tokens, filterToken{kind: tokenOperator, text: operator, position: i})
+			i += len(operator)
+		}
+	}
+	return tokens, nil
+}
+
+type filterParser struct {
+	tokens []filterToken
+	next   int
+}
+
+func (p *filterParser) done() bool {
+	return p.next >= len(p.tokens)
+}
+
+func (p *filterParser) peek() filterToken {
+	if p.done() {
+		return filterToken{text: "end of expression", position: -1}
+	}
+	return p.tokens[p.next]
+}
+
+func (p *filterParser) accept(kind, text string) bool {
+	if !p.done() && p.peek().kind == kind && p.peek().text == text {
+		p.next++
+		return true
+	}
+	return false
+}
+
+func (p *filterParser) expect(kind, text string) error {
+	if !p.accept(kind, text) {
+		return p.unexpected(text)
+	}
+	return nil
+}
+
+func (p *filterParser) unexpected(expected string) error {
+	token := p.peek()
+	if token.position < 0 {
+		return fmt.Errorf("expected %s, got end of expression", expected)
+	}
+	return fmt.Errorf("expected %s, got %q at position %d", expected, token.text, token.position)
+}
+
+func (p *filterParser) parseOr() (filterNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept(tokenOperator, "||") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orNode{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseAnd() (filterNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept(tokenOperator, "&&") {
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = andNode{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseUnary() (filterNode, error) {
+	if p.accept(tokenOperator, "!") {
+		node, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{node: node}, nil
+	}
+	if p.accept(tokenOperator, "(") {
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		return node, p.expect(tokenOperator, ")")
+	}
+	return p.parseComparison()
+}
+
+func (p *filterParser) parseComparison() (filterNode, error) {
+	token := p.peek()
+	if p.done() || token.kind != tokenPath {
+		return nil, p.unexpected("attribute")
+	}
+	p.next++
+	if token.text == "has" && p.accept(tokenOperator, "(") {
+		path := p.peek()
+		if p.done() || path.kind != tokenPath {
+			return nil, p.unexpected("attribute")
+		}
+		p.next++
+		return hasNode{path: path.text}, p.expect(tokenOperator, ")")
+	}
+	node := comparisonNode{path: token.text}
+	operator := p.peek()
+	switch {
+	case operator.kind == tokenOperator && comparisonOperators[operator.text]:
+		p.next++
+		node.operator = operator.text
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		node.values = []filterValue{value}
+	case operator.kind == tokenPath && operator.text == "in":
+		p.next++
+		node.operator = "in"
+		values, err := p.parseList()
+		if err != nil {
+			return nil, err
+		}
+		node.values = values
+	default:
+		return nil, p.unexpected("comparison operator after " + token.text)
+	}
+	if node.operator == "=~" || node.operator == "!~" {
+		re, err := regexp.Compile(node.values[0].text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regular expression %q: %w", node.values[0].text, err)
+		}
+		node.regexp = re
+	}
+	return node, nil
+}
+
+func (p *filterParser) parseList() ([]filterValue, error) {
+	if err := p.expect(tokenOperator, "["); err != nil {
+		return nil, err
+	}
+	values := []filterValue{}
+	for !p.accept(tokenOperator, "]") {
+		if len(values) > 0 {
+			if err := p.expect(tokenOperator, ","); err != nil {
+				return nil, err
+			}
+		}
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+// parseValue parses a string, a number, true or false, or date('2021-01-01') and ago('720h') dates
+func (p *filterParser) parseValue() (filterValue, error) {
+	token := p.peek()
+	if p.done() {
+		return filterValue{}, p.unexpected("value")
+	}
+	p.next++
+	switch token.kind {
+	case tokenString:
+		return filterValue{text: token.text}, nil
+	case tokenNumber:
+		number, err := strconv.ParseFloat(token.text, 64)
+		if err != nil {
+			return filterValue{}, fmt.Errorf("invalid number %q at position %d", token.text, token.position)
+		}
+		return filterValue{text: token.text, number: &number}, nil
+	case tokenPath:
+		switch token.text {
+		case "true", "false":
+			return filterValue{text: token.text}, nil
+		case "date", "ago":
+			if err := p.expect(tokenOperator, "("); err != nil {
+				return filterValue{}, err
+			}
+			argument := p.peek()
+			if p.done() || argument.kind != tokenString {
+				return filterValue{}, p.unexpected("string")
+			}
+			p.next++
+			if err := p.expect(tokenOperator, ")"); err != nil {
+				return filterValue{}, err
+			}
+			date, err := dateFunction(token.text, argument.text)
+			if err != nil {
+				return filterValue{}, err
+			}
+			return filterValue{text: argument.text, date: &date}, nil
+		}
+	}
+	p.next--
+	return filterValue{}, p.unexpected("value")
+}
+
+func dateFunction(function, argument string) (time.Time, error) {
+	if function == "ago" {
+		duration, err := time.ParseDuration(argument)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return time.Now().Add(-duration), nil
+	}
+	date, ok := parseFilterDate(argument)
+	if !ok {
+		return time.Time{}, fmt.Errorf("invalid date %q, expected e.g. 2021-01-01 or 2021-01-01T00:00:00Z", argument)
+	}
+	return date, nil
+}
+
+// splitExpressionFilter returns the expression and service of Expr=<expression> and
+// Type=<service>;Expr=<expression> filters
+func splitExpressionFilter(rawFilter string) (expression, serviceName string, ok bool) {
+	if strings.HasPrefix(rawFilter, ExpressionFilterPrefix) {
+		return strings.TrimPrefix(rawFilter, ExpressionFilterPrefix), "", true
+	}
+	if !strings.HasPrefix(rawFilter, "Type=") {
+		return "", "", false
+	}
+	parts := strings.SplitN(rawFilter, ";", 2)
+	if len(parts) != 2 || !strings.HasPrefix(parts[1], ExpressionFilterPrefix) {
+		return "", "", false
+	}
+	return strings.TrimPrefix(parts[1], ExpressionFilterPrefix), strings.TrimPrefix(parts[0], "Type="), true
+}
+
+// ValidateFilters returns the error of the first expression filter failing to parse, other filters
+// are only logged when invalid
+func ValidateFilters(rawFilters []string) error {
+	for _, rawFilter := range rawFilters {
+		if expression, _, ok := splitExpressionFilter(rawFilter); ok {
+			if _, err := ParseFilterExpression(expression); err != nil {
+				return fmt.Errorf("invalid filter %s: %w", rawFilter, err)
+			}
+		}
+	}
+	return nil
+}
+
+// ReadFilterFile returns the filters of a file, one per line, skipping blank lines and # comments
+func ReadFilterFile(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	filters := []string{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		filters = append(filters, line)
+	}
+	return filters, scanner.Err()
+}
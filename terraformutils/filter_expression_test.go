@@ -0,0 +1,146 @@
+// Copyright 2018 The Terraformer Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package terraformutils
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func filterResource() Resource {
+	return Resource{
+		InstanceInfo: &terraform.InstanceInfo{Type: "aws_instance"},
+		InstanceState: &terraform.InstanceState{
+			ID: "i-123456",
+			Attributes: map[string]string{
+				"id":          "i-123456",
+				"name":        "team-a-web",
+				"tags.Env":    "staging",
+				"cpu_count":   "4",
+				"launch_time": "2021-03-04T10:00:00Z",
+			},
+		},
+		Item: map[string]interface{}{
+			"security_groups": []interface{}{"sg-111111", "sg-222222"},
+		},
+	}
+}
+
+func TestFilterExpressionMatch(t *testing.T) {
+	for expression, expected := range map[string]bool{
+		`tags.Env != 'prod'`:                                 true,
+		`tags.Env == "staging"`:                              true,
+		`name =~ '^team-a-'`:                                 true,
+		`name !~ '^team-b-'`:                                 true,
+		`id =~ '^i-\d+$'`:                                    true,
+		`cpu_count >= 4 && cpu_count < 8`:                    true,
+		`cpu_count > 4`:                                      false,
+		`launch_time > date('2021-01-01')`:                   true,
+		`launch_time < date("2021-03-04T09:00:00Z")`:         false,
+		`launch_time > ago('87600h')`:                        true,
+		`tags.Env == 'prod' || name =~ 'web$'`:               true,
+		`!(tags.Env == 'staging')`:                           false,
+		`tags.Env in ['dev', 'staging']`:                     true,
+		`security_groups == 'sg-222222'`:                     true,
+		`security_groups != 'sg-222222'`:                     false,
+		`has(tags.Env) && !has(tags.Owner)`:                  true,
+		`tags.Owner != 'a'`:                                  true,
+		`tags.Owner == 'a' || (cpu_count == 4 && !has(foo))`: true,
+	} {
+		parsed, err := ParseFilterExpression(expression)
+		if err != nil {
+			t.Errorf("%s: %s", expression, err)
+			continue
+		}
+		if parsed.Match(filterResource()) != expected {
+			t.Errorf("%s expected to be %t", expression, expected)
+		}
+	}
+}
+
+func TestFilterExpressionErrors(t *testing.T) {
+	for _, expression := range []string{
+		`tags.Env`,
+		`tags.Env = 'prod'`,
+		`tags.Env == 'prod`,
+		`(tags.Env == 'prod'`,
+		`name =~ '('`,
+		`launch_time > date('yesterday')`,
+		`tags.Env == 'prod' &&`,
+		`tags.Env in 'prod'`,
+		`'prod' == tags.Env`,
+	} {
+		if _, err := ParseFilterExpression(expression); err == nil {
+			t.Errorf("%s expected to fail", expression)
+		}
+	}
+}
+
+func TestExpressionFiltersParsing(t *testing.T) {
+	service := Service{}
+	service.ParseFilters([]string{"Expr=has(tags.Env)", "Type=instance;Expr=name =~ 'a;b'", "Expr=tags.Env =="})
+
+	if len(service.Filter) != 2 {
+		t.Fatalf("failed to parse, got %v", service.Filter)
+	}
+	if service.Filter[0].ServiceName != "" || service.Filter[0].Expression.Raw != "has(tags.Env)" || service.Filter[0].isInitial() {
+		t.Errorf("failed to parse, got %v", service.Filter[0])
+	}
+	if service.Filter[1].ServiceName != "instance" || service.Filter[1].Expression.Raw != "name =~ 'a;b'" {
+		t.Errorf("failed to parse, got %v", service.Filter[1])
+	}
+}
+
+func TestServiceExpressionCleanupWithFilter(t *testing.T) {
+	other := filterResource()
+	other.InstanceInfo = &terraform.InstanceInfo{Type: "aws_instance", Id: "aws_instance.other"}
+	other.InstanceState = &terraform.InstanceState{ID: "i-654321", Attributes: map[string]string{"tags.Env": "prod"}}
+	vpc := prepareNoAttrs("vpc-123456", "aws_vpc")
+	service := Service{Resources: []Resource{filterResource(), other, vpc}}
+	for i := range service.Resources {
+		service.Resources[i].Provider = "aws"
+	}
+	service.ParseFilters([]string{"Type=instance;Expr=tags.Env != 'prod'"})
+	service.PostRefreshCleanup()
+
+	if len(service.Resources) != 2 || service.Resources[0].InstanceState.ID != "i-123456" || service.Resources[1].InstanceState.ID != "vpc-123456" {
+		t.Errorf("failed to cleanup, got %v", service.Resources)
+	}
+}
+
+func TestReadFilterFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "filters.txt")
+	content := "# instances of team a\nType=instance;Expr=tags.Team == 'a' && tags.Env in ['dev', 'staging']\n\nvpc=vpc-123456\n"
+	if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	filters, err := ReadFilterFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(filters, []string{"Type=instance;Expr=tags.Team == 'a' && tags.Env in ['dev', 'staging']", "vpc=vpc-123456"}) {
+		t.Errorf("unexpected filters %v", filters)
+	}
+	if err := ValidateFilters(filters); err != nil {
+		t.Error(err)
+	}
+	if err := ValidateFilters([]string{"Expr=tags.Env"}); err == nil {
+		t.Errorf("invalid expression expected to fail")
+	}
+}
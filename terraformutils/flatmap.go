@@ -213,6 +213,7 @@ func (p *FlatmapParser) fromFlatmapMap(prefix string, ty cty.Type) (map[string]i
 	}
 
 	values := make(map[string]interface{})
+	seen := map[string]bool{}
 	for fullKey := range p.attributes {
 		if !strings.HasPrefix(fullKey, prefix) {
 			continue
@@ -231,6 +232,18 @@ func (p *FlatmapParser) fromFlatmapMap(prefix string, ty cty.Type) (map[string]i
 		if p.isAttributeIgnored(fullKey) {
 			continue
 		}
+		if !ty.IsPrimitiveType() {
+			// maps of objects come from nested attributes of protocol 6 schemas,
+			// their keys can't hold dots, what follows the first one is inside the element
+			if dot := strings.IndexByte(key, '.'); dot != -1 {
+				key = key[:dot]
+			}
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			fullKey = prefix + key
+		}
 		value, err := p.fromFlatmapValue(fullKey, ty)
 		if err != nil {
 			return nil, err
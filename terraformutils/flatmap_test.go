@@ -1,6 +1,7 @@
 package terraformutils
 
 import (
+	"reflect"
 	"regexp"
 	"testing"
 
@@ -34,3 +35,37 @@ func TestNestedAttributeFiltering(t *testing.T) {
 		t.Errorf("failed to resolve %v", result)
 	}
 }
+
+func TestMapOfObjectsParsing(t *testing.T) {
+	attributes := map[string]string{
+		"rules.%":             "2",
+		"rules.allow.port":    "443",
+		"rules.allow.created": "2021-01-01",
+		"rules.deny.port":     "22",
+	}
+	ignoreKeys := []*regexp.Regexp{
+		regexp.MustCompile(`^rules.[^.]+.created($|\.(.*))`),
+	}
+	parser := NewFlatmapParser(attributes, ignoreKeys, []*regexp.Regexp{})
+
+	attributesType := cty.Object(map[string]cty.Type{
+		"rules": cty.Map(cty.Object(map[string]cty.Type{
+			"port":    cty.String,
+			"created": cty.String,
+		})),
+	})
+
+	result, err := parser.Parse(attributesType)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]interface{}{
+		"rules": map[string]interface{}{
+			"allow": map[string]interface{}{"port": "443"},
+			"deny":  map[string]interface{}{"port": "22"},
+		},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("expected %v, got %v", expected, result)
+	}
+}
@@ -0,0 +1,561 @@
+// Copyright 2018 The Terraformer Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package terraformutils
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	GraphFormatDOT     = "dot"
+	GraphFormatJSON    = "json"
+	GraphFormatMermaid = "mermaid"
+
+	GraphLevelResource = "resource"
+	GraphLevelService  = "service"
+)
+
+// Graph is the dependency graph of the resources imported during a run, a resource depends on another
+// when one of its attributes holds an identifier of the other
+type Graph struct {
+	mutex        sync.Mutex
+	Services     []GraphService `json:"services"`
+	Resources    []GraphNode    `json:"resources"`
+	Edges        []GraphEdge    `json:"edges"`
+	ServiceEdges []ServiceEdge  `json:"service_edges"`
+	Cycles       [][]string     `json:"cycles"`
+}
+
+type GraphService struct {
+	ID        string `json:"id"`
+	Provider  string `json:"provider"`
+	Region    string `json:"region,omitempty"`
+	Service   string `json:"service"`
+	Resources int    `json:"resources"`
+}
+
+// GraphNode is an imported resource. Orphan resources are referenced by nothing although resources of the
+// same type are, e.g. a security group no instance uses.
+type GraphNode struct {
+	ID       string `json:"id"`
+	Service  string `json:"service"`
+	Address  string `json:"address"`
+	Type     string `json:"type"`
+	ImportID string `json:"import_id"`
+	Orphan   bool   `json:"orphan,omitempty"`
+}
+
+// GraphEdge is the Attribute of From holding the Key attribute of To. Source is connection for links
+// declared by the provider and inferred for those found by InferReferences.
+type GraphEdge struct {
+	From      string `json:"from"`
+	To        string `json:"to"`
+	Attribute string `json:"attribute"`
+	Key       string `json:"key"`
+	Source    string `json:"source"`
+}
+
+// ServiceEdge counts the resource edges between two services
+type ServiceEdge struct {
+	From  string `json:"from"`
+	To    string `json:"to"`
+	Edges int    `json:"edges"`
+}
+
+const (
+	EdgeSourceConnection = "connection"
+	EdgeSourceInferred   = "inferred"
+)
+
+func NewGraph() *Graph {
+	return &Graph{
+		Services:     []GraphService{},
+		Resources:    []GraphNode{},
+		Edges:        []GraphEdge{},
+		ServiceEdges: []ServiceEdge{},
+		Cycles:       [][]string{},
+	}
+}
+
+// ConnectionReferences returns the references the connections of a provider make between imported
+// resources, importResources must not be connected yet as ConnectServices replaces the matched values
+func ConnectionReferences(importResources map[string][]Resource, connections map[string]map[string][]string) []Reference {
+	references := []Reference{}
+	services := []string{}
+	for from := range connections {
+		services = append(services, from)
+	}
+	sort.Strings(services)
+	for _, from := range services {
+		if _, exist := importResources[from]; !exist {
+			continue
+		}
+		targetServices := []string{}
+		for to := range connections[from] {
+			targetServices = append(targetServices, to)
+		}
+		sort.Strings(targetServices)
+		for _, to := range targetServices {
+			pairs := connections[from][to]
+			if len(pairs)%2 == 1 {
+				continue
+			}
+			for i := 0; i < len(pairs); i += 2 {
+				targets := map[string][]Resource{}
+				keys := map[string]string{}
+				for _, target := range importResources[to] {
+					if target.InstanceState == nil {
+						continue
+					}
+					key := pairs[i+1]
+					if key == "self_link" || key == "id" {
+						key = target.GetIDKey()
+					}
+					values := WalkAndGet(key, target.InstanceState.Attributes)
+					if len(values) != 1 {
+						continue
+					}
+					value := values[0].(string)
+					targets[value] = append(targets[value], target)
+					keys[target.Address()] = key
+				}
+				for _, r := range importResources[from] {
+					for _, value := range WalkAndGet(pairs[i], r.Item) {
+						s, ok := value.(string)
+						if !ok {
+							continue
+						}
+						for _, target := range targets[s] {
+							if target.Address() == r.Address() {
+								continue
+							}
+							references = append(references, Reference{
+								FromService: from,
+								From:        r.Address(),
+								Attribute:   pairs[i],
+								ToService:   to,
+								To:          target.Address(),
+								Key:         keys[target.Address()],
+							})
+						}
+					}
+				}
+			}
+		}
+	}
+	return references
+}
+
+// Add adds the resources imported by a provider in a region and the references between them, nil safe
+func (g *Graph) Add(provider, region string, importResources map[string][]Resource, connected, inferred []Reference) {
+	if g == nil {
+		return
+	}
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	services := make([]string, 0, len(importResources))
+	for service := range importResources {
+		services = append(services, service)
+	}
+	sort.Strings(services)
+	nodes := map[string]string{}
+	for _, service := range services {
+		serviceID := graphID(provider, region, service)
+		g.Services = append(g.Services, GraphService{
+			ID:        serviceID,
+			Provider:  provider,
+			Region:    region,
+			Service:   service,
+			Resources: len(importResources[service]),
+		})
+		for _, r := range importResources[service] {
+			id := graphID(provider, region, r.Address())
+			nodes[r.Address()] = id
+			node := GraphNode{
+				ID:      id,
+				Service: serviceID,
+				Address: r.Address(),
+				Type:    r.InstanceInfo.Type,
+			}
+			if r.InstanceState != nil {
+				node.ImportID = r.InstanceState.ID
+			}
+			g.Resources = append(g.Resources, node)
+		}
+	}
+	seen := map[GraphEdge]bool{}
+	for _, edge := range g.Edges {
+		seen[edge] = true
+	}
+	for _, references := range []struct {
+		source     string
+		references []Reference
+	}{{EdgeSourceConnection, connected}, {EdgeSourceInferred, inferred}} {
+		for _, reference := range references.references {
+			from, fromExist := nodes[reference.From]
+			to, toExist := nodes[reference.To]
+			if !fromExist || !toExist {
+				continue
+			}
+			edge := GraphEdge{
+				From:      from,
+				To:        to,
+				Attribute: reference.Attribute,
+				Key:       reference.Key,
+				Source:    references.source,
+			}
+			if !seen[edge] {
+				seen[edge] = true
+				g.Edges = append(g.Edges, edge)
+			}
+		}
+	}
+	g.analyze()
+}
+
+// analyze flags orphans and computes the service edges and the cycles between services
+func (g *Graph) analyze() {
+	services := map[string]string{}
+	types := map[string]string{}
+	for _, node := range g.Resources {
+		services[node.ID] = node.Service
+		types[node.ID] = node.Type
+	}
+	referenced := map[string]bool{}
+	referencedTypes := map[string]bool{}
+	counts := map[[2]string]int{}
+	for _, edge := range g.Edges {
+		referenced[edge.To] = true
+		referencedTypes[types[edge.To]] = true
+		from, to := services[edge.From], services[edge.To]
+		if from != to {
+			counts[[2]string{from, to}]++
+		}
+	}
+	for i := range g.Resources {
+		node := &g.Resources[i]
+		node.Orphan = referencedTypes[node.Type] && !referenced[node.ID]
+	}
+
+	g.ServiceEdges = []ServiceEdge{}
+	for pair, count := range counts {
+		g.ServiceEdges = append(g.ServiceEdges, ServiceEdge{From: pair[0], To: pair[1], Edges: count})
+	}
+	sort.Slice(g.ServiceEdges, func(i, j int) bool {
+		a, b := g.ServiceEdges[i], g.ServiceEdges[j]
+		if a.From != b.From {
+			return a.From < b.From
+		}
+		return a.To < b.To
+	})
+	g.Cycles = serviceCycles(g.Services, g.ServiceEdges)
+}
+
+// Orphans returns the addresses of the orphan resources
+func (g *Graph) Orphans() []string {
+	if g == nil {
+		return nil
+	}
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	orphans := []string{}
+	for _, node := range g.Resources {
+		if node.Orphan {
+			orphans = append(orphans, node.ID)
+		}
+	}
+	return orphans
+}
+
+// serviceCycles returns the strongly connected components of more than one service, the services of each
+// depend on each other through references and can't be applied one after another
+func serviceCycles(services []GraphService, edges []ServiceEdge) [][]string {
+	next := map[string][]string{}
+	for _, edge := range edges {
+		next[edge.From] = append(next[edge.From], edge.To)
+	}
+	index := map[string]int{}
+	lowLink := map[string]int{}
+	onStack := map[string]bool{}
+	stack := []string{}
+	cycles := [][]string{}
+	var connect func(service string)
+	connect = func(service string) {
+		index[service] = len(index)
+		lowLink[service] = index[service]
+		stack = append(stack, service)
+		onStack[service] = true
+		for _, to := range next[service] {
+			if _, visited := index[to]; !visited {
+				connect(to)
+				if lowLink[to] < lowLink[service] {
+					lowLink[service] = lowLink[to]
+				}
+			} else if onStack[to] && index[to] < lowLink[service] {
+				lowLink[service] = index[to]
+			}
+		}
+		if lowLink[service] != index[service] {
+			return
+		}
+		component := []string{}
+		for {
+			last := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[last] = false
+			component = append(component, last)
+			if last == service {
+				break
+			}
+		}
+		if len(component) > 1 {
+			sort.Strings(component)
+			cycles = append(cycles, component)
+		}
+	}
+	for _, service := range services {
+		if _, visited := index[service.ID]; !visited {
+			connect(service.ID)
+		}
+	}
+	sort.Slice(cycles, func(i, j int) bool {
+		return cycles[i][0] < cycles[j][0]
+	})
+	return cycles
+}
+
+// GraphFormatOf returns the format matching the extension of path, DOT when unknown
+func GraphFormatOf(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return GraphFormatJSON
+	case ".mmd", ".mermaid":
+		return GraphFormatMermaid
+	default:
+		return GraphFormatDOT
+	}
+}
+
+// Write saves the graph to path, in the format matching its extension when format is empty
+func (g *Graph) Write(path, format, level string) error {
+	if g == nil || path == "" {
+		return nil
+	}
+	if format == "" {
+		format = GraphFormatOf(path)
+	}
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+			return err
+		}
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := g.Render(f, format, level); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Render writes the graph in format, DOT and Mermaid draw either resources grouped by service or only
+// services, JSON always holds both
+func (g *Graph) Render(w io.Writer, format, level string) error {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	if level == "" {
+		level = GraphLevelResource
+	}
+	if level != GraphLevelResource && level != GraphLevelService {
+		return fmt.Errorf("unsupported graph level: %s", level)
+	}
+	switch format {
+	case GraphFormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(g)
+	case GraphFormatDOT, "":
+		return g.renderDOT(w, level)
+	case GraphFormatMermaid:
+		return g.renderMermaid(w, level)
+	default:
+		return fmt.Errorf("unsupported graph format: %s", format)
+	}
+}
+
+func (g *Graph) renderDOT(w io.Writer, level string) error {
+	b := &strings.Builder{}
+	b.WriteString("digraph terraformer {\n  rankdir=LR;\n  node [shape=box];\n")
+	inCycle := g.cycleServices()
+	labels := g.serviceLabels()
+	if level == GraphLevelService {
+		for _, service := range g.Services {
+			attributes := "label=" + strconv.Quote(labels[service.ID])
+			if inCycle[service.ID] {
+				attributes += ", color=red"
+			}
+			fmt.Fprintf(b, "  %s [%s];\n", strconv.Quote(service.ID), attributes)
+		}
+		for _, edge := range g.ServiceEdges {
+			attributes := "label=\"" + strconv.Itoa(edge.Edges) + "\""
+			if inCycle[edge.From] && inCycle[edge.To] {
+				attributes += ", color=red"
+			}
+			fmt.Fprintf(b, "  %s -> %s [%s];\n", strconv.Quote(edge.From), strconv.Quote(edge.To), attributes)
+		}
+	} else {
+		for i, service := range g.Services {
+			fmt.Fprintf(b, "  subgraph cluster_%d {\n    label=%s;\n", i, strconv.Quote(labels[service.ID]))
+			if inCycle[service.ID] {
+				b.WriteString("    color=red;\n")
+			}
+			for _, node := range g.Resources {
+				if node.Service != service.ID {
+					continue
+				}
+				attributes := "label=" + strconv.Quote(node.Address)
+				if node.Orphan {
+					attributes += ", style=dashed, color=orange"
+				}
+				fmt.Fprintf(b, "    %s [%s];\n", strconv.Quote(node.ID), attributes)
+			}
+			b.WriteString("  }\n")
+		}
+		for _, edge := range g.Edges {
+			attributes := "label=" + strconv.Quote(edge.Attribute)
+			if edge.Source == EdgeSourceInferred {
+				attributes += ", style=dashed"
+			}
+			fmt.Fprintf(b, "  %s -> %s [%s];\n", strconv.Quote(edge.From), strconv.Quote(edge.To), attributes)
+		}
+	}
+	for _, cycle := range g.Cycles {
+		fmt.Fprintf(b, "  // cycle: %s\n", strings.Join(cycle, ", "))
+	}
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func (g *Graph) renderMermaid(w io.Writer, level string) error {
+	b := &strings.Builder{}
+	b.WriteString("flowchart LR\n")
+	inCycle := g.cycleServices()
+	labels := g.serviceLabels()
+	// mermaid ids can't hold the dots and slashes of addresses
+	ids := map[string]string{}
+	for i, service := range g.Services {
+		ids[service.ID] = "s" + strconv.Itoa(i)
+	}
+	for i, node := range g.Resources {
+		ids[node.ID] = "r" + strconv.Itoa(i)
+	}
+	if level == GraphLevelService {
+		for _, service := range g.Services {
+			fmt.Fprintf(b, "  %s[%s]\n", ids[service.ID], mermaidLabel(labels[service.ID]))
+		}
+		for _, edge := range g.ServiceEdges {
+			fmt.Fprintf(b, "  %s -->|%d| %s\n", ids[edge.From], edge.Edges, ids[edge.To])
+		}
+		for _, service := range g.Services {
+			if inCycle[service.ID] {
+				fmt.Fprintf(b, "  class %s cycle\n", ids[service.ID])
+			}
+		}
+	} else {
+		for _, service := range g.Services {
+			fmt.Fprintf(b, "  subgraph %s[%s]\n", ids[service.ID], mermaidLabel(labels[service.ID]))
+			for _, node := range g.Resources {
+				if node.Service == service.ID {
+					fmt.Fprintf(b, "    %s[%s]\n", ids[node.ID], mermaidLabel(node.Address))
+				}
+			}
+			b.WriteString("  end\n")
+		}
+		for _, edge := range g.Edges {
+			arrow := "-->"
+			if edge.Source == EdgeSourceInferred {
+				arrow = "-.->"
+			}
+			fmt.Fprintf(b, "  %s %s|%s| %s\n", ids[edge.From], arrow, mermaidLabel(edge.Attribute), ids[edge.To])
+		}
+		for _, node := range g.Resources {
+			if node.Orphan {
+				fmt.Fprintf(b, "  class %s orphan\n", ids[node.ID])
+			}
+		}
+		for _, service := range g.Services {
+			if inCycle[service.ID] {
+				fmt.Fprintf(b, "  class %s cycle\n", ids[service.ID])
+			}
+		}
+	}
+	b.WriteString("  classDef orphan stroke:#f90,stroke-dasharray:4\n")
+	b.WriteString("  classDef cycle stroke:#f00\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// serviceLabels names services by service, prefixed with the provider and region only when the graph holds several
+func (g *Graph) serviceLabels() map[string]string {
+	prefixes := map[string]bool{}
+	for _, service := range g.Services {
+		prefixes[graphID(service.Provider, service.Region, "")] = true
+	}
+	labels := map[string]string{}
+	for _, service := range g.Services {
+		labels[service.ID] = service.Service
+		if len(prefixes) > 1 {
+			labels[service.ID] = service.ID
+		}
+	}
+	return labels
+}
+
+func (g *Graph) cycleServices() map[string]bool {
+	inCycle := map[string]bool{}
+	for _, cycle := range g.Cycles {
+		for _, service := range cycle {
+			inCycle[service] = true
+		}
+	}
+	return inCycle
+}
+
+func mermaidLabel(label string) string {
+	return "\"" + strings.ReplaceAll(label, "\"", "#quot;") + "\""
+}
+
+// graphID joins the non empty parts of an identifier, provider and region keep the resources of
+// several Import calls apart
+func graphID(parts ...string) string {
+	nonEmpty := []string{}
+	for _, part := range parts {
+		if part != "" {
+			nonEmpty = append(nonEmpty, part)
+		}
+	}
+	return strings.Join(nonEmpty, "/")
+}
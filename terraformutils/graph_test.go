@@ -0,0 +1,170 @@
+// Copyright 2018 The Terraformer Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package terraformutils
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func prepareNamed(id, name, resourceType string, attributesParsed map[string]interface{}) Resource {
+	r := prepare(id, resourceType, map[string]string{}, attributesParsed)
+	r.ResourceName = name
+	return r
+}
+
+func testGraph() *Graph {
+	importResources := map[string][]Resource{
+		"vpc": {prepareNamed("vpc-123456", "main", "aws_vpc", map[string]interface{}{})},
+		"sg": {
+			prepareNamed("sg-111111", "used", "aws_security_group", map[string]interface{}{"vpc_id": "vpc-123456"}),
+			prepareNamed("sg-222222", "unused", "aws_security_group", map[string]interface{}{"vpc_id": "vpc-123456"}),
+		},
+		"ec2": {prepareNamed("i-123456", "web", "aws_instance", map[string]interface{}{
+			"vpc_security_group_ids": []interface{}{"sg-111111"},
+		})},
+	}
+	connections := map[string]map[string][]string{
+		"ec2": {"sg": {"vpc_security_group_ids", "id"}},
+		"sg":  {"vpc": {"vpc_id", "id"}},
+	}
+	connected := ConnectionReferences(importResources, connections)
+	inferred := []Reference{{FromService: "vpc", From: "aws_vpc.main", Attribute: "tags.Instance", ToService: "ec2", To: "aws_instance.web", Key: "id"}}
+	graph := NewGraph()
+	graph.Add("aws", "eu-west-1", importResources, connected, inferred)
+	return graph
+}
+
+func TestConnectionReferences(t *testing.T) {
+	importResources := map[string][]Resource{
+		"sg":  {prepareNamed("sg-111111", "used", "aws_security_group", map[string]interface{}{"vpc_id": "vpc-123456"})},
+		"vpc": {prepareNamed("vpc-123456", "main", "aws_vpc", map[string]interface{}{})},
+	}
+	references := ConnectionReferences(importResources, map[string]map[string][]string{"sg": {"vpc": {"vpc_id", "id"}}})
+
+	expected := []Reference{{FromService: "sg", From: "aws_security_group.used", Attribute: "vpc_id", ToService: "vpc", To: "aws_vpc.main", Key: "id"}}
+	if !reflect.DeepEqual(references, expected) {
+		t.Errorf("unexpected references %v", references)
+	}
+	if importResources["sg"][0].Item["vpc_id"] != "vpc-123456" {
+		t.Errorf("resources expected to be left unchanged, got %v", importResources["sg"][0].Item)
+	}
+}
+
+func TestGraphOrphansAndCycles(t *testing.T) {
+	graph := testGraph()
+
+	if len(graph.Edges) != 4 {
+		t.Errorf("unexpected edges %v", graph.Edges)
+	}
+	if orphans := graph.Orphans(); !reflect.DeepEqual(orphans, []string{"aws/eu-west-1/aws_security_group.unused"}) {
+		t.Errorf("unexpected orphans %v", orphans)
+	}
+	expected := [][]string{{"aws/eu-west-1/ec2", "aws/eu-west-1/sg", "aws/eu-west-1/vpc"}}
+	if !reflect.DeepEqual(graph.Cycles, expected) {
+		t.Errorf("unexpected cycles %v", graph.Cycles)
+	}
+	if !reflect.DeepEqual(graph.ServiceEdges[1], ServiceEdge{From: "aws/eu-west-1/sg", To: "aws/eu-west-1/vpc", Edges: 2}) {
+		t.Errorf("unexpected service edges %v", graph.ServiceEdges)
+	}
+}
+
+func TestGraphWithoutCycles(t *testing.T) {
+	importResources := map[string][]Resource{
+		"sg":  {prepareNamed("sg-111111", "used", "aws_security_group", map[string]interface{}{"vpc_id": "vpc-123456"})},
+		"vpc": {prepareNamed("vpc-123456", "main", "aws_vpc", map[string]interface{}{})},
+	}
+	graph := NewGraph()
+	graph.Add("aws", "", importResources, ConnectionReferences(importResources, map[string]map[string][]string{"sg": {"vpc": {"vpc_id", "id"}}}), nil)
+
+	if len(graph.Cycles) != 0 || len(graph.Orphans()) != 0 {
+		t.Errorf("unexpected cycles %v or orphans %v", graph.Cycles, graph.Orphans())
+	}
+}
+
+func TestGraphRender(t *testing.T) {
+	graph := testGraph()
+	for _, test := range []struct {
+		format   string
+		level    string
+		expected []string
+	}{
+		{GraphFormatDOT, GraphLevelResource, []string{
+			"subgraph cluster_0 {\n    label=\"ec2\";\n    color=red;",
+			`"aws/eu-west-1/aws_security_group.unused" [label="aws_security_group.unused", style=dashed, color=orange];`,
+			`"aws/eu-west-1/aws_instance.web" -> "aws/eu-west-1/aws_security_group.used" [label="vpc_security_group_ids"];`,
+			`"aws/eu-west-1/aws_vpc.main" -> "aws/eu-west-1/aws_instance.web" [label="tags.Instance", style=dashed];`,
+		}},
+		{GraphFormatDOT, GraphLevelService, []string{
+			`"aws/eu-west-1/sg" -> "aws/eu-west-1/vpc" [label="2", color=red];`,
+			"// cycle: aws/eu-west-1/ec2, aws/eu-west-1/sg, aws/eu-west-1/vpc",
+		}},
+		{GraphFormatMermaid, GraphLevelResource, []string{
+			"flowchart LR\n",
+			"  subgraph s1[\"sg\"]\n    r1[\"aws_security_group.used\"]\n    r2[\"aws_security_group.unused\"]\n",
+			"  r0 -->|\"vpc_security_group_ids\"| r1\n",
+			"  r3 -.->|\"tags.Instance\"| r0\n",
+			"  class r2 orphan\n",
+		}},
+		{GraphFormatMermaid, GraphLevelService, []string{
+			"  s1 -->|2| s2\n",
+			"  class s0 cycle\n",
+		}},
+	} {
+		b := &bytes.Buffer{}
+		if err := graph.Render(b, test.format, test.level); err != nil {
+			t.Fatal(err)
+		}
+		for _, expected := range test.expected {
+			if !strings.Contains(b.String(), expected) {
+				t.Errorf("%s %s graph expected to contain %q, got\n%s", test.format, test.level, expected, b.String())
+			}
+		}
+	}
+}
+
+func TestGraphRenderJSON(t *testing.T) {
+	b := &bytes.Buffer{}
+	if err := testGraph().Render(b, GraphFormatJSON, ""); err != nil {
+		t.Fatal(err)
+	}
+	decoded := Graph{}
+	if err := json.Unmarshal(b.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded.Resources) != 4 || len(decoded.Edges) != 4 || len(decoded.ServiceEdges) != 3 || len(decoded.Cycles) != 1 {
+		t.Errorf("unexpected graph %s", b.String())
+	}
+	if err := testGraph().Render(b, "svg", ""); err == nil {
+		t.Errorf("unsupported format expected to fail")
+	}
+}
+
+func TestGraphFormatOf(t *testing.T) {
+	for path, expected := range map[string]string{
+		"graph.json":    GraphFormatJSON,
+		"graph.mmd":     GraphFormatMermaid,
+		"graph.mermaid": GraphFormatMermaid,
+		"graph.dot":     GraphFormatDOT,
+		"graph":         GraphFormatDOT,
+	} {
+		if format := GraphFormatOf(path); format != expected {
+			t.Errorf("%s: expected %s, got %s", path, expected, format)
+		}
+	}
+}
@@ -21,205 +21,411 @@ import (
 	"fmt"
 	"log"
 	"regexp"
+	"sort"
 	"strings"
 
-	"github.com/hashicorp/hcl/hcl/ast"
-	hclPrinter "github.com/hashicorp/hcl/hcl/printer"
-	hclParser "github.com/hashicorp/hcl/json/parser"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/hashicorp/terraform/configs/configschema"
+	"github.com/hashicorp/terraform/providers"
+	"github.com/zclconf/go-cty/cty"
 )
 
-// Copy code from https://github.com/kubernetes/kops project with few changes for support many provider and heredoc
+var unsafeChars = regexp.MustCompile(`[^0-9A-Za-z_]`)
 
-const safeChars = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_"
+// a string holding only a reference, e.g. "${aws_vpc.tfer--main.id}", is written as a bare expression
+var referenceExpression = regexp.MustCompile(`^\$\{([A-Za-z_][\w-]*(?:\.[\w-]+|\[[0-9]+\])+)\}$`)
 
-var unsafeChars = regexp.MustCompile(`[^0-9A-Za-z_]`)
+// heredocs are built by providers as "<<POLICY\n...\nPOLICY"
+var heredocExpression = regexp.MustCompile(`(?s)^<<(-?)([A-Za-z_][\w-]*)\n(.*)\n([A-Za-z_][\w-]*)$`)
+
+var numberLiteral = regexp.MustCompile(`^-?[0-9]+(\.[0-9]+)?([eE][+-]?[0-9]+)?$`)
+
+var providerReference = regexp.MustCompile(`^[A-Za-z_][\w-]*(\.[A-Za-z_][\w-]*)?$`)
+
+// blockLabels is the number of labels of the terraform block types found in generated files
+var blockLabels = map[string]int{
+	"resource":          2,
+	"data":              2,
+	"provider":          1,
+	"variable":          1,
+	"output":            1,
+	"module":            1,
+	"terraform.backend": 1,
+}
+
+// Print renders data laid out as terraform JSON configuration, in HCL or JSON
+func Print(data interface{}, format string) ([]byte, error) {
+	return PrintWithSchema(data, nil, format)
+}
 
-// sanitizer fixes up an invalid HCL AST, as produced by the HCL parser for JSON
-type astSanitizer struct{}
-
-// output prints creates b printable HCL output and returns it.
-func (v *astSanitizer) visit(n interface{}) {
-	switch t := n.(type) {
-	case *ast.File:
-		v.visit(t.Node)
-	case *ast.ObjectList:
-		var index int
-		for {
-			if index == len(t.Items) {
-				break
+// PrintWithSchema renders data like Print, resource, data source and provider blocks
+// are written following the provider schema when one is given
+func PrintWithSchema(data interface{}, schema *providers.GetSchemaResponse, format string) ([]byte, error) {
+	switch format {
+	case "hcl":
+		return hclPrint(data, schema, nil)
+	case "json":
+		return jsonPrint(data)
+	}
+	return []byte{}, errors.New("error: unknown output format")
+}
+
+func hclPrint(data interface{}, schema *providers.GetSchemaResponse, mapsObjects map[string]struct{}) ([]byte, error) {
+	// round trip through json, so the writer only handles json types
+	dataJSON, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(dataJSON))
+	dec.UseNumber()
+	root := map[string]interface{}{}
+	if err := dec.Decode(&root); err != nil {
+		return nil, fmt.Errorf("error: HCL data must be an object: %v", err)
+	}
+	w := &hclWriter{schema: schema, mapsObjects: mapsObjects}
+	f := hclwrite.NewEmptyFile()
+	w.writeBody(f.Body(), "", root, nil, true)
+	if w.err != nil {
+		return nil, w.err
+	}
+	return hclwrite.Format(f.Bytes()), nil
+}
+
+type hclWriter struct {
+	schema *providers.GetSchemaResponse
+	// mapsObjects are the attribute paths of resources holding maps, without a schema
+	// other objects of resources are written as blocks
+	mapsObjects map[string]struct{}
+	// err is the first attribute that can't be written
+	err error
+}
+
+// writeBody writes attributes before blocks, both in key order. Keys missing from the schema
+// are blocks when they hold a list of objects, as in terraform JSON configuration.
+func (w *hclWriter) writeBody(body *hclwrite.Body, path string, values map[string]interface{}, schema *configschema.Block, topLevel bool) {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	blocks := []string{}
+	for _, k := range keys {
+		value := values[k]
+		if value == nil {
+			continue
+		}
+		if w.isBlock(path, k, value, schema) {
+			blocks = append(blocks, k)
+			continue
+		}
+		if !hclsyntax.ValidIdentifier(k) {
+			if w.err == nil {
+				w.err = fmt.Errorf("error: attribute %q of %s is not a valid identifier", k, path)
+			}
+			continue
+		}
+		ty := cty.DynamicPseudoType
+		if schema != nil {
+			if attribute, exist := schema.Attributes[k]; exist {
+				ty = attribute.Type
+			}
+		}
+		if k == "provider" && path == "resource" {
+			// meta-argument referencing a provider configuration
+			if name, ok := value.(string); ok && providerReference.MatchString(name) {
+				body.SetAttributeRaw(k, hclwrite.Tokens{{Type: hclsyntax.TokenIdent, Bytes: []byte(name)}})
+				continue
 			}
-			v.visit(t.Items[index])
-			index++
 		}
-	case *ast.ObjectKey:
-	case *ast.ObjectItem:
-		v.visitObjectItem(t)
-	case *ast.LiteralType:
-	case *ast.ListType:
-	case *ast.ObjectType:
-		v.visit(t.List)
-	default:
-		fmt.Printf(" unknown type: %T\n", n)
+		body.SetAttributeRaw(k, tokensForValue(value, ty))
+	}
+	for _, k := range blocks {
+		blockPath := strings.TrimPrefix(path+"."+k, ".")
+		if labels, exist := blockLabels[blockPath]; exist {
+			w.writeLabeledBlocks(body, k, labels, values[k], []string{}, topLevel)
+			continue
+		}
+		var nested *configschema.NestedBlock
+		if schema != nil {
+			nested = schema.BlockTypes[k]
+		}
+		w.writeNestedBlocks(body, blockPath, k, values[k], nested, topLevel)
 	}
 }
 
-func (v *astSanitizer) visitObjectItem(o *ast.ObjectItem) {
-	for i, k := range o.Keys {
-		if i == 0 {
-			text := k.Token.Text
-			if text != "" && text[0] == '"' && text[len(text)-1] == '"' {
-				v := text[1 : len(text)-1]
-				safe := true
-				for _, c := range v {
-					if !strings.ContainsRune(safeChars, c) {
-						safe = false
-						break
-					}
-				}
-				if safe {
-					k.Token.Text = v
-				}
-			}
+// appendBlock separates top level blocks with an empty line
+func appendBlock(body *hclwrite.Body, blockType string, labels []string, topLevel bool) *hclwrite.Block {
+	if topLevel && (len(body.Blocks()) > 0 || len(body.Attributes()) > 0) {
+		body.AppendNewline()
+	}
+	return body.AppendNewBlock(blockType, labels)
+}
+
+func (w *hclWriter) isBlock(path, key string, value interface{}, schema *configschema.Block) bool {
+	if path == "" {
+		// every top level key of a terraform JSON configuration is a block type
+		return true
+	}
+	if _, exist := blockLabels[strings.TrimPrefix(path+"."+key, ".")]; exist {
+		return true
+	}
+	if schema != nil {
+		if _, exist := schema.Attributes[key]; exist {
+			return false
+		}
+		if _, exist := schema.BlockTypes[key]; exist {
+			return true
 		}
 	}
-	switch t := o.Val.(type) {
-	case *ast.LiteralType: // heredoc support
-		if strings.HasPrefix(t.Token.Text, `"<<`) {
-			t.Token.Text = t.Token.Text[1:]
-			t.Token.Text = t.Token.Text[:len(t.Token.Text)-1]
-			t.Token.Text = strings.ReplaceAll(t.Token.Text, `\n`, "\n")
-			t.Token.Text = strings.ReplaceAll(t.Token.Text, `\t`, "")
-			t.Token.Type = 10
-			// check if text json for Unquote and Indent
-			tmp := map[string]interface{}{}
-			jsonTest := t.Token.Text
-			lines := strings.Split(jsonTest, "\n")
-			jsonTest = strings.Join(lines[1:len(lines)-1], "\n")
-			jsonTest = strings.ReplaceAll(jsonTest, "\\\"", "\"")
-			// it's json we convert to heredoc back
-			err := json.Unmarshal([]byte(jsonTest), &tmp)
-			if err == nil {
-				dataJSONBytes, err := json.MarshalIndent(tmp, "", "  ")
-				if err == nil {
-					jsonData := strings.Split(string(dataJSONBytes), "\n")
-					// first line for heredoc
-					jsonData = append([]string{lines[0]}, jsonData...)
-					// last line for heredoc
-					jsonData = append(jsonData, lines[len(lines)-1])
-					hereDoc := strings.Join(jsonData, "\n")
-					t.Token.Text = hereDoc
-				}
-			}
+	if m, ok := value.(map[string]interface{}); ok && (path == "resource" || strings.HasPrefix(path, "resource.")) {
+		if !validIdentifiers(m) {
+			// only a map can hold such keys, e.g. tags named aws:cloudformation:stack-name
+			return false
+		}
+		attributePath := strings.TrimPrefix(strings.TrimPrefix(path, "resource"), ".")
+		if attributePath != "" {
+			attributePath += "."
+		}
+		_, isMap := w.mapsObjects[attributePath+key]
+		return !isMap
+	}
+	list, ok := value.([]interface{})
+	if !ok || len(list) == 0 {
+		return false
+	}
+	for _, element := range list {
+		if m, ok := element.(map[string]interface{}); !ok || !validIdentifiers(m) {
+			return false
 		}
-	default:
 	}
+	return true
+}
 
-	// A hack so that Assign.IsValid is true, so that the printer will output =
-	o.Assign.Line = 1
+func validIdentifiers(values map[string]interface{}) bool {
+	for k := range values {
+		if !hclsyntax.ValidIdentifier(k) {
+			return false
+		}
+	}
+	return true
+}
 
-	v.visit(o.Val)
+// writeLabeledBlocks walks label maps down to block bodies, e.g. resource -> type -> name -> body
+func (w *hclWriter) writeLabeledBlocks(body *hclwrite.Body, blockType string, labelCount int, value interface{}, labels []string, topLevel bool) {
+	if list, ok := value.([]interface{}); ok {
+		for _, element := range list {
+			w.writeLabeledBlocks(body, blockType, labelCount, element, labels, topLevel)
+		}
+		return
+	}
+	values, _ := value.(map[string]interface{})
+	if len(labels) < labelCount {
+		keys := make([]string, 0, len(values))
+		for k := range values {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			w.writeLabeledBlocks(body, blockType, labelCount, values[k], append(append([]string{}, labels...), k), topLevel)
+		}
+		return
+	}
+	block := appendBlock(body, blockType, labels, topLevel)
+	w.writeBody(block.Body(), blockType, values, w.blockSchema(blockType, labels), false)
 }
 
-func Print(data interface{}, mapsObjects map[string]struct{}, format string) ([]byte, error) {
-	switch format {
-	case "hcl":
-		return hclPrint(data, mapsObjects)
-	case "json":
-		return jsonPrint(data)
+func (w *hclWriter) blockSchema(blockType string, labels []string) *configschema.Block {
+	if w.schema == nil {
+		return nil
 	}
-	return []byte{}, errors.New("error: unknown output format")
+	switch blockType {
+	case "resource":
+		return w.schema.ResourceTypes[labels[0]].Block
+	case "data":
+		return w.schema.DataSources[labels[0]].Block
+	case "provider":
+		return w.schema.Provider.Block
+	}
+	return nil
 }
 
-func hclPrint(data interface{}, mapsObjects map[string]struct{}) ([]byte, error) {
-	dataBytesJSON, err := jsonPrint(data)
-	if err != nil {
-		return dataBytesJSON, err
+func (w *hclWriter) writeNestedBlocks(body *hclwrite.Body, path, blockType string, value interface{}, nested *configschema.NestedBlock, topLevel bool) {
+	var schema *configschema.Block
+	if nested != nil {
+		schema = &nested.Block
+		if nested.Nesting == configschema.NestingMap {
+			values, _ := value.(map[string]interface{})
+			keys := make([]string, 0, len(values))
+			for k := range values {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				for _, blockValues := range blockBodies(values[k]) {
+					w.writeBody(appendBlock(body, blockType, []string{k}, topLevel).Body(), path, blockValues, schema, false)
+				}
+			}
+			return
+		}
 	}
-	dataJSON := string(dataBytesJSON)
-	nodes, err := hclParser.Parse([]byte(dataJSON))
-	if err != nil {
-		log.Println(dataJSON)
-		return []byte{}, fmt.Errorf("error parsing terraform json: %v", err)
+	for _, blockValues := range blockBodies(value) {
+		w.writeBody(appendBlock(body, blockType, nil, topLevel).Body(), path, blockValues, schema, false)
 	}
-	var sanitizer astSanitizer
-	sanitizer.visit(nodes)
+}
 
-	var b bytes.Buffer
-	err = hclPrinter.Fprint(&b, nodes)
-	if err != nil {
-		return nil, fmt.Errorf("error writing HCL: %v", err)
+func blockBodies(value interface{}) []map[string]interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return []map[string]interface{}{v}
+	case []interface{}:
+		bodies := []map[string]interface{}{}
+		for _, element := range v {
+			if body, ok := element.(map[string]interface{}); ok {
+				bodies = append(bodies, body)
+			}
+		}
+		return bodies
 	}
-	s := b.String()
+	return nil
+}
 
-	// Remove extra whitespace...
-	s = strings.ReplaceAll(s, "\n\n", "\n")
+func tokensForValue(value interface{}, ty cty.Type) hclwrite.Tokens {
+	switch v := value.(type) {
+	case nil:
+		return hclwrite.Tokens{{Type: hclsyntax.TokenIdent, Bytes: []byte("null")}}
+	case bool:
+		return hclwrite.Tokens{{Type: hclsyntax.TokenIdent, Bytes: []byte(fmt.Sprint(v))}}
+	case json.Number:
+		return hclwrite.Tokens{{Type: hclsyntax.TokenNumberLit, Bytes: []byte(v.String())}}
+	case string:
+		return tokensForString(v, ty)
+	case []interface{}:
+		return tokensForList(v, ty)
+	case map[string]interface{}:
+		return tokensForObject(v, ty)
+	}
+	return tokensForString(fmt.Sprint(value), ty)
+}
 
-	// ...but leave whitespace between resources
-	s = strings.ReplaceAll(s, "}\nresource", "}\n\nresource")
+func tokensForString(s string, ty cty.Type) hclwrite.Tokens {
+	switch {
+	case ty == cty.Number && numberLiteral.MatchString(s):
+		return hclwrite.Tokens{{Type: hclsyntax.TokenNumberLit, Bytes: []byte(s)}}
+	case ty == cty.Bool && (s == "true" || s == "false"):
+		return hclwrite.Tokens{{Type: hclsyntax.TokenIdent, Bytes: []byte(s)}}
+	}
+	if m := referenceExpression.FindStringSubmatch(s); m != nil {
+		return hclwrite.Tokens{{Type: hclsyntax.TokenIdent, Bytes: []byte(m[1])}}
+	}
+	if m := heredocExpression.FindStringSubmatch(s); m != nil && m[2] == m[4] {
+		return hclwrite.Tokens{
+			{Type: hclsyntax.TokenOHeredoc, Bytes: []byte("<<" + m[1] + m[2] + "\n")},
+			{Type: hclsyntax.TokenStringLit, Bytes: []byte(indentJSON(m[3]) + "\n")},
+			{Type: hclsyntax.TokenCHeredoc, Bytes: []byte(m[4])},
+		}
+	}
+	return hclwrite.Tokens{
+		{Type: hclsyntax.TokenOQuote, Bytes: []byte(`"`)},
+		{Type: hclsyntax.TokenQuotedLit, Bytes: []byte(escapeQuotedString(s))},
+		{Type: hclsyntax.TokenCQuote, Bytes: []byte(`"`)},
+	}
+}
 
-	// Apply Terraform style (alignment etc.)
-	formatted, err := hclPrinter.Format([]byte(s))
-	if err != nil {
-		return nil, err
+// indentJSON pretty prints heredocs holding a JSON object, e.g. policies, and returns others unchanged
+func indentJSON(s string) string {
+	object := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(s), &object); err != nil {
+		return s
 	}
-	// hack for support terraform 0.12
-	formatted = terraform12Adjustments(formatted, mapsObjects)
-	// hack for support terraform 0.13
-	formatted = terraform13Adjustments(formatted)
+	indented, err := json.MarshalIndent(object, "", "  ")
 	if err != nil {
-		log.Println("Invalid HCL follows:")
-		for i, line := range strings.Split(s, "\n") {
-			fmt.Printf("%4d|\t%s\n", i+1, line)
-		}
-		return nil, fmt.Errorf("error formatting HCL: %v", err)
+		return s
 	}
+	return string(indented)
+}
 
-	return formatted, nil
+// escapeQuotedString keeps ${} interpolations, providers use them to reference other resources
+func escapeQuotedString(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		`"`, `\"`,
+		"\n", `\n`,
+		"\r", `\r`,
+		"\t", `\t`,
+		"%{", "%%{",
+	).Replace(s)
 }
 
-func terraform12Adjustments(formatted []byte, mapsObjects map[string]struct{}) []byte {
-	singletonListFix := regexp.MustCompile(`^\s*\w+ = {`)
-	singletonListFixEnd := regexp.MustCompile(`^\s*}`)
-
-	s := string(formatted)
-	old := " = {"
-	newEquals := " {"
-	lines := strings.Split(s, "\n")
-	prefix := make([]string, 0)
-	for i, line := range lines {
-		if singletonListFixEnd.MatchString(line) && len(prefix) > 0 {
-			prefix = prefix[:len(prefix)-1]
-			continue
+func tokensForList(values []interface{}, ty cty.Type) hclwrite.Tokens {
+	multiline := false
+	for _, v := range values {
+		if _, ok := v.(map[string]interface{}); ok {
+			multiline = true
 		}
-		if !singletonListFix.MatchString(line) {
-			continue
+	}
+	tokens := hclwrite.Tokens{{Type: hclsyntax.TokenOBrack, Bytes: []byte("[")}}
+	if multiline {
+		tokens = append(tokens, &hclwrite.Token{Type: hclsyntax.TokenNewline, Bytes: []byte("\n")})
+	}
+	for i, v := range values {
+		elementType := cty.DynamicPseudoType
+		switch {
+		case ty.IsListType() || ty.IsSetType():
+			elementType = ty.ElementType()
+		case ty.IsTupleType() && i < len(ty.TupleElementTypes()):
+			elementType = ty.TupleElementTypes()[i]
 		}
-		key := strings.Trim(strings.Split(line, old)[0], " ")
-		prefix = append(prefix, key)
-		if _, exist := mapsObjects[strings.Join(prefix, ".")]; exist {
-			continue
+		tokens = append(tokens, tokensForValue(v, elementType)...)
+		switch {
+		case multiline:
+			tokens = append(tokens,
+				&hclwrite.Token{Type: hclsyntax.TokenComma, Bytes: []byte(",")},
+				&hclwrite.Token{Type: hclsyntax.TokenNewline, Bytes: []byte("\n")})
+		case i < len(values)-1:
+			tokens = append(tokens, &hclwrite.Token{Type: hclsyntax.TokenComma, Bytes: []byte(",")})
 		}
-		lines[i] = strings.ReplaceAll(line, old, newEquals)
 	}
-	s = strings.Join(lines, "\n")
-	return []byte(s)
+	return append(tokens, &hclwrite.Token{Type: hclsyntax.TokenCBrack, Bytes: []byte("]")})
 }
 
-func terraform13Adjustments(formatted []byte) []byte {
-	s := string(formatted)
-	oldRequiredProviders := "\"required_providers\""
-	newRequiredProviders := "required_providers"
-	lines := strings.Split(s, "\n")
-	providerRequirementDefinition := false
-	for i, line := range lines {
-		if providerRequirementDefinition {
-			line = strings.ReplaceAll(line, " {", " = {")
+func tokensForObject(values map[string]interface{}, ty cty.Type) hclwrite.Tokens {
+	if len(values) == 0 {
+		return hclwrite.Tokens{
+			{Type: hclsyntax.TokenOBrace, Bytes: []byte("{")},
+			{Type: hclsyntax.TokenCBrace, Bytes: []byte("}")},
+		}
+	}
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	tokens := hclwrite.Tokens{
+		{Type: hclsyntax.TokenOBrace, Bytes: []byte("{")},
+		{Type: hclsyntax.TokenNewline, Bytes: []byte("\n")},
+	}
+	for _, k := range keys {
+		elementType := cty.DynamicPseudoType
+		switch {
+		case ty.IsMapType():
+			elementType = ty.ElementType()
+		case ty.IsObjectType() && ty.HasAttribute(k):
+			elementType = ty.AttributeType(k)
+		}
+		if hclsyntax.ValidIdentifier(k) {
+			tokens = append(tokens, &hclwrite.Token{Type: hclsyntax.TokenIdent, Bytes: []byte(k)})
+		} else {
+			tokens = append(tokens,
+				&hclwrite.Token{Type: hclsyntax.TokenOQuote, Bytes: []byte(`"`)},
+				&hclwrite.Token{Type: hclsyntax.TokenQuotedLit, Bytes: []byte(escapeQuotedString(k))},
+				&hclwrite.Token{Type: hclsyntax.TokenCQuote, Bytes: []byte(`"`)})
 		}
-		providerRequirementDefinition = strings.Contains(line, newRequiredProviders)
-		lines[i] = strings.Replace(line, oldRequiredProviders, newRequiredProviders, 1)
+		tokens = append(tokens, &hclwrite.Token{Type: hclsyntax.TokenEqual, Bytes: []byte("=")})
+		tokens = append(tokens, tokensForValue(values[k], elementType)...)
+		tokens = append(tokens, &hclwrite.Token{Type: hclsyntax.TokenNewline, Bytes: []byte("\n")})
 	}
-	s = strings.Join(lines, "\n")
-	return []byte(s)
+	return append(tokens, &hclwrite.Token{Type: hclsyntax.TokenCBrace, Bytes: []byte("}")})
 }
 
 func escapeRune(s string) string {
@@ -234,7 +440,7 @@ func TfSanitize(name string) string {
 }
 
 // Print hcl file from TerraformResource + provider
-func HclPrintResource(resources []Resource, providerData map[string]interface{}, output string) ([]byte, error) {
+func HclPrintResource(resources []Resource, providerData map[string]interface{}, output string, schema *providers.GetSchemaResponse) ([]byte, error) {
 	resourcesByType := map[string]map[string]interface{}{}
 	mapsObjects := map[string]struct{}{}
 	indexRe := regexp.MustCompile(`\.[0-9]+`)
@@ -246,7 +452,6 @@ func HclPrintResource(resources []Resource, providerData map[string]interface{},
 		}
 
 		if r[res.ResourceName] != nil {
-			log.Println(resources)
 			log.Printf("[ERR]: duplicate resource found: %s.%s", res.InstanceInfo.Type, res.ResourceName)
 			continue
 		}
@@ -268,11 +473,11 @@ func HclPrintResource(resources []Resource, providerData map[string]interface{},
 	if len(providerData) > 0 {
 		data["provider"] = providerData
 	}
-	var err error
-
-	hclBytes, err := Print(data, mapsObjects, output)
-	if err != nil {
-		return []byte{}, err
+	switch output {
+	case "hcl":
+		return hclPrint(data, schema, mapsObjects)
+	case "json":
+		return jsonPrint(data)
 	}
-	return hclBytes, nil
+	return []byte{}, errors.New("error: unknown output format")
 }
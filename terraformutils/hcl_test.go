@@ -17,6 +17,12 @@ package terraformutils
 import (
 	"strings"
 	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/terraform/configs/configschema"
+	"github.com/hashicorp/terraform/providers"
+	"github.com/zclconf/go-cty/cty"
 )
 
 func TestPrintResource(t *testing.T) {
@@ -43,7 +49,7 @@ func TestPrintResource(t *testing.T) {
 	resources = append(resources, importResource)
 	providerData := map[string]interface{}{}
 	output := "hcl"
-	data, _ := HclPrintResource(resources, providerData, output)
+	data, _ := HclPrintResource(resources, providerData, output, nil)
 
 	if strings.Count(string(data), "map1 = ") != 1 {
 		t.Errorf("failed to parse data %s", string(data))
@@ -52,3 +58,137 @@ func TestPrintResource(t *testing.T) {
 		t.Errorf("failed to parse data %s", string(data))
 	}
 }
+
+func TestPrintResourceWithSchema(t *testing.T) {
+	schema := &providers.GetSchemaResponse{
+		ResourceTypes: map[string]providers.Schema{
+			"type1": {
+				Block: &configschema.Block{
+					Attributes: map[string]*configschema.Attribute{
+						"count_of": {Type: cty.Number, Optional: true},
+						"enabled":  {Type: cty.Bool, Optional: true},
+						"name":     {Type: cty.String, Optional: true},
+						"policy":   {Type: cty.String, Optional: true},
+						"vpc_id":   {Type: cty.String, Optional: true},
+						"tags":     {Type: cty.Map(cty.String), Optional: true},
+						"ingress": {Type: cty.List(cty.Object(map[string]cty.Type{
+							"port": cty.Number,
+						})), Optional: true},
+						"settings": {Type: cty.Map(cty.Object(map[string]cty.Type{
+							"value": cty.String,
+						})), Optional: true},
+					},
+					BlockTypes: map[string]*configschema.NestedBlock{
+						"rule": {
+							Nesting: configschema.NestingList,
+							Block: configschema.Block{
+								Attributes: map[string]*configschema.Attribute{
+									"ports": {Type: cty.List(cty.Number), Optional: true},
+								},
+							},
+						},
+						"config": {Nesting: configschema.NestingSingle},
+					},
+				},
+			},
+		},
+	}
+	resource := prepare("ID1", "type1", map[string]string{}, map[string]interface{}{
+		"count_of": "3",
+		"enabled":  "true",
+		"name":     "say \"hi\" 100%{",
+		"policy":   "<<POLICY\n{\"Version\":\"2012-10-17\",\"Statement\":[{\"Effect\":\"Allow\"}]}\nPOLICY",
+		"script":   "<<EOT\necho {\nEOT",
+		"vpc_id":   "${aws_vpc.tfer--main.id}",
+		"tags":     map[string]interface{}{"Owner": "me", "kubernetes.io/role": "elb"},
+		"ingress":  []interface{}{map[string]interface{}{"port": "443"}},
+		"settings": map[string]interface{}{"a": map[string]interface{}{"value": "b"}},
+		"rule":     []interface{}{map[string]interface{}{"ports": []interface{}{"80", "443"}}},
+		"config":   map[string]interface{}{},
+	})
+	data, err := HclPrintResource([]Resource{resource}, map[string]interface{}{}, "hcl", schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, diags := hclsyntax.ParseConfig(data, "type1.tf", hcl.Pos{Line: 1, Column: 1}); diags.HasErrors() {
+		t.Fatalf("invalid HCL %s: %s", string(data), diags)
+	}
+	for _, expected := range []string{
+		`count_of = 3`,
+		`enabled  = true`,
+		`name   = "say \"hi\" 100%%{"`,
+		"policy = <<POLICY\n{\n  \"Statement\": [\n    {\n      \"Effect\": \"Allow\"\n    }\n  ],\n  \"Version\": \"2012-10-17\"\n}\nPOLICY\n",
+		"script = <<EOT\necho {\nEOT\n",
+		`vpc_id = aws_vpc.tfer--main.id`,
+		`"kubernetes.io/role" = "elb"`,
+		"ingress = [\n    {\n      port = 443\n    },\n  ]",
+		"settings = {\n    a = {\n      value = \"b\"\n    }\n  }",
+		"rule {\n    ports = [80, 443]\n  }",
+		"config {\n  }",
+	} {
+		if !strings.Contains(string(data), expected) {
+			t.Errorf("missing %q in %s", expected, string(data))
+		}
+	}
+}
+
+func TestPrintResourceInvalidKeys(t *testing.T) {
+	resource := prepare("ID1", "type1", map[string]string{}, map[string]interface{}{
+		"labels": map[string]interface{}{"aws:cloudformation:stack-name": "main", "Owner": "me"},
+	})
+	data, err := HclPrintResource([]Resource{resource}, map[string]interface{}{}, "hcl", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"aws:cloudformation:stack-name" = "main"`) {
+		t.Errorf("keys that are not identifiers expected to be written as map keys, got %s", string(data))
+	}
+
+	schema := &providers.GetSchemaResponse{
+		ResourceTypes: map[string]providers.Schema{
+			"type1": {Block: &configschema.Block{BlockTypes: map[string]*configschema.NestedBlock{
+				"config": {Nesting: configschema.NestingSingle},
+			}}},
+		},
+	}
+	resource = prepare("ID1", "type1", map[string]string{}, map[string]interface{}{
+		"config": map[string]interface{}{"not valid": "x"},
+	})
+	if _, err := HclPrintResource([]Resource{resource}, map[string]interface{}{}, "hcl", schema); err == nil {
+		t.Error("block attributes that are not identifiers expected to fail")
+	}
+}
+
+func TestPrintConfiguration(t *testing.T) {
+	data, err := Print(map[string]interface{}{
+		"output": map[string]interface{}{
+			"type1_tfer--name_id": map[string]interface{}{"value": "${type1.tfer--name.id}"},
+		},
+		"terraform": map[string]interface{}{
+			"backend":            []map[string]interface{}{{"s3": map[string]interface{}{"bucket": "state"}}},
+			"required_providers": []map[string]interface{}{{"aws": map[string]interface{}{"source": "hashicorp/aws", "version": "~> 3.0"}}},
+		},
+	}, "hcl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `output "type1_tfer--name_id" {
+  value = type1.tfer--name.id
+}
+
+terraform {
+  backend "s3" {
+    bucket = "state"
+  }
+  required_providers {
+    aws = {
+      source  = "hashicorp/aws"
+      version = "~> 3.0"
+    }
+  }
+}
+`
+	if string(data) != expected {
+		t.Errorf("unexpected configuration %s", string(data))
+	}
+}
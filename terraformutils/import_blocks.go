@@ -0,0 +1,100 @@
+// Copyright 2018 The Terraformer Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package terraformutils
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// PrintImportBlocks renders one Terraform 1.5+ `import` block per resource, so
+// terraform plan can adopt the resources without a generated tfstate
+func PrintImportBlocks(resources []Resource, format string) ([]byte, error) {
+	return PrintModulesImportBlocks(map[string][]Resource{"": resources}, format)
+}
+
+// PrintModulesImportBlocks renders import blocks of resources of child modules keyed by module
+// name, in the root module. Resources of the root module are keyed by an empty name.
+func PrintModulesImportBlocks(resourcesByModule map[string][]Resource, format string) ([]byte, error) {
+	imports := []importBlock{}
+	for module, resources := range resourcesByModule {
+		for _, r := range resources {
+			imports = append(imports, importBlock{module: module, resource: r})
+		}
+	}
+	sort.Slice(imports, func(i, j int) bool {
+		return imports[i].address() < imports[j].address()
+	})
+	switch format {
+	case "hcl":
+		return hclPrintImportBlocks(imports), nil
+	case "json":
+		return jsonPrintImportBlocks(imports)
+	}
+	return []byte{}, errors.New("error: unknown output format")
+}
+
+type importBlock struct {
+	module   string
+	resource Resource
+}
+
+func (b importBlock) traversal() hcl.Traversal {
+	names := []string{b.resource.InstanceInfo.Type, b.resource.ResourceName}
+	if b.module != "" {
+		names = append([]string{"module", b.module}, names...)
+	}
+	traversal := hcl.Traversal{hcl.TraverseRoot{Name: names[0]}}
+	for _, name := range names[1:] {
+		traversal = append(traversal, hcl.TraverseAttr{Name: name})
+	}
+	return traversal
+}
+
+func (b importBlock) address() string {
+	if b.module == "" {
+		return b.resource.Address()
+	}
+	return "module." + b.module + "." + b.resource.Address()
+}
+
+func hclPrintImportBlocks(imports []importBlock) []byte {
+	f := hclwrite.NewFile()
+	body := f.Body()
+	for i, b := range imports {
+		if i > 0 {
+			body.AppendNewline()
+		}
+		block := body.AppendNewBlock("import", nil).Body()
+		block.SetAttributeTraversal("to", b.traversal())
+		block.SetAttributeValue("id", cty.StringVal(b.resource.InstanceState.ID))
+	}
+	return f.Bytes()
+}
+
+func jsonPrintImportBlocks(imports []importBlock) ([]byte, error) {
+	blocks := []map[string]interface{}{}
+	for _, b := range imports {
+		blocks = append(blocks, map[string]interface{}{
+			"to": b.address(),
+			"id": b.resource.InstanceState.ID,
+		})
+	}
+	return jsonPrint(map[string]interface{}{"import": blocks})
+}
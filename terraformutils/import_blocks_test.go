@@ -0,0 +1,82 @@
+// Copyright 2018 The Terraformer Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package terraformutils
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestPrintImportBlocksHcl(t *testing.T) {
+	resources := []Resource{
+		prepareNoAttrs("ID2", "type2"),
+		prepareNoAttrs("ID1", "type1"),
+	}
+	data, err := PrintImportBlocks(resources, "hcl")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `import {
+  to = type1.tfer--name-002D-type1
+  id = "ID1"
+}
+
+import {
+  to = type2.tfer--name-002D-type2
+  id = "ID2"
+}
+`
+	if string(data) != expected {
+		t.Errorf("failed to print import blocks, got\n%s", string(data))
+	}
+}
+
+func TestPrintImportBlocksJSON(t *testing.T) {
+	resources := []Resource{prepareNoAttrs("ID1", "type1")}
+	data, err := PrintImportBlocks(resources, "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsed := map[string][]map[string]string{}
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(parsed, map[string][]map[string]string{
+		"import": {{"to": "type1.tfer--name-002D-type1", "id": "ID1"}},
+	}) {
+		t.Errorf("failed to print import blocks, got %v", parsed)
+	}
+}
+
+func TestPrintModulesImportBlocksHcl(t *testing.T) {
+	data, err := PrintModulesImportBlocks(map[string][]Resource{
+		"svc": {prepareNoAttrs("ID1", "type1")},
+	}, "hcl")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `import {
+  to = module.svc.type1.tfer--name-002D-type1
+  id = "ID1"
+}
+`
+	if string(data) != expected {
+		t.Errorf("failed to print import blocks, got\n%s", string(data))
+	}
+}
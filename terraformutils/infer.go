@@ -0,0 +1,216 @@
+// Copyright 2018 The Terraformer Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package terraformutils
+
+import (
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// minReferenceLength skips short identifiers, e.g. "1" or "main", they match unrelated literals
+const minReferenceLength = 6
+
+// Reference is a literal replaced by a reference to the attribute of another resource
+type Reference struct {
+	FromService string `json:"from_service"`
+	From        string `json:"from"`
+	Attribute   string `json:"attribute"`
+	ToService   string `json:"to_service"`
+	To          string `json:"to"`
+	Key         string `json:"key"`
+}
+
+// AmbiguousReference is a literal matching several resources, left unchanged
+type AmbiguousReference struct {
+	Address    string   `json:"address"`
+	Attribute  string   `json:"attribute"`
+	Value      string   `json:"value"`
+	Candidates []string `json:"candidates"`
+}
+
+// InferredReferences are the references found by InferReferences. Connections has the format of
+// ProviderGenerator.GetResourceConnections, with the links between services to expose as outputs.
+type InferredReferences struct {
+	References  []Reference
+	Ambiguous   []AmbiguousReference
+	Connections map[string]map[string][]string
+}
+
+type referenceTarget struct {
+	service  string
+	resource Resource
+	key      string
+}
+
+// InferReferences replaces literals equal to the ID, ARN or self_link of another imported resource with a
+// reference to it. The ID is only used for resources without self_link, whose outputs expose self_link.
+// References inside a service are direct, others are built by link. Attributes declared in overrides,
+// the connections of the provider, are left to ConnectServices.
+func InferReferences(importResources map[string][]Resource, overrides map[string]map[string][]string, link LinkFunc) InferredReferences {
+	inferred := InferredReferences{
+		References:  []Reference{},
+		Ambiguous:   []AmbiguousReference{},
+		Connections: map[string]map[string][]string{},
+	}
+	services := make([]string, 0, len(importResources))
+	for service := range importResources {
+		services = append(services, service)
+	}
+	sort.Strings(services)
+
+	index := map[string][]referenceTarget{}
+	for _, service := range services {
+		for _, r := range importResources[service] {
+			if r.InstanceState == nil {
+				continue
+			}
+			for _, key := range []string{r.GetIDKey(), "arn"} {
+				value := r.InstanceState.Attributes[key]
+				if isReferenceable(value) && !hasTarget(index[value], r) {
+					index[value] = append(index[value], referenceTarget{service: service, resource: r, key: key})
+				}
+			}
+		}
+	}
+
+	for _, from := range services {
+		declared := declaredAttributes(overrides[from])
+		for i := range importResources[from] {
+			r := &importResources[from][i]
+			walkStrings("", r.Item, func(key, value string) string {
+				attribute := attributePath(key)
+				if declared[attribute] {
+					return value
+				}
+				matches := []referenceTarget{}
+				for _, target := range index[value] {
+					if target.resource.Address() != r.Address() {
+						matches = append(matches, target)
+					}
+				}
+				if len(matches) == 0 {
+					return value
+				}
+				if len(matches) > 1 {
+					candidates := []string{}
+					for _, target := range matches {
+						candidates = append(candidates, target.resource.Address())
+					}
+					log.Printf("WARN: %s.%s matches %s, left as a literal", r.Address(), key, strings.Join(candidates, ", "))
+					inferred.Ambiguous = append(inferred.Ambiguous, AmbiguousReference{
+						Address:    r.Address(),
+						Attribute:  key,
+						Value:      value,
+						Candidates: candidates,
+					})
+					return value
+				}
+				target := matches[0]
+				inferred.References = append(inferred.References, Reference{
+					FromService: from,
+					From:        r.Address(),
+					Attribute:   key,
+					ToService:   target.service,
+					To:          target.resource.Address(),
+					Key:         target.key,
+				})
+				if target.service == from {
+					return "${" + target.resource.Address() + "." + target.key + "}"
+				}
+				if inferred.Connections[from] == nil {
+					inferred.Connections[from] = map[string][]string{}
+				}
+				pairs := inferred.Connections[from][target.service]
+				if !hasPair(pairs, attribute, target.key) {
+					inferred.Connections[from][target.service] = append(pairs, attribute, target.key)
+				}
+				return link(from, target.service, target.resource, target.key)
+			})
+		}
+	}
+	return inferred
+}
+
+// MergeConnections returns the connections of both tables, pairs of b missing from a added after those of a
+func MergeConnections(a, b map[string]map[string][]string) map[string]map[string][]string {
+	merged := map[string]map[string][]string{}
+	for _, connections := range []map[string]map[string][]string{a, b} {
+		for from, services := range connections {
+			if merged[from] == nil {
+				merged[from] = map[string][]string{}
+			}
+			for to, pairs := range services {
+				for i := 0; i+1 < len(pairs); i += 2 {
+					if !hasPair(merged[from][to], pairs[i], pairs[i+1]) {
+						merged[from][to] = append(merged[from][to], pairs[i], pairs[i+1])
+					}
+				}
+			}
+		}
+	}
+	return merged
+}
+
+func hasPair(pairs []string, attribute, key string) bool {
+	for i := 0; i+1 < len(pairs); i += 2 {
+		if pairs[i] == attribute && pairs[i+1] == key {
+			return true
+		}
+	}
+	return false
+}
+
+func hasTarget(targets []referenceTarget, r Resource) bool {
+	for _, target := range targets {
+		if target.resource.Address() == r.Address() {
+			return true
+		}
+	}
+	return false
+}
+
+// isReferenceable skips values too short or numeric to identify a resource
+func isReferenceable(value string) bool {
+	if len(value) < minReferenceLength {
+		return false
+	}
+	_, err := strconv.ParseFloat(value, 64)
+	return err != nil
+}
+
+// declaredAttributes returns the attribute paths of the connections of a service
+func declaredAttributes(connections map[string][]string) map[string]bool {
+	declared := map[string]bool{}
+	for _, pairs := range connections {
+		for i := 0; i+1 < len(pairs); i += 2 {
+			declared[pairs[i]] = true
+		}
+	}
+	return declared
+}
+
+// attributePath removes list indexes from the key of a value, e.g. ingress.0.security_groups.1
+// becomes ingress.security_groups as in connections of providers
+func attributePath(key string) string {
+	segments := []string{}
+	for _, segment := range strings.Split(key, ".") {
+		if _, err := strconv.Atoi(segment); err != nil {
+			segments = append(segments, segment)
+		}
+	}
+	return strings.Join(segments, ".")
+}
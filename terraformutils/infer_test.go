@@ -0,0 +1,92 @@
+// Copyright 2018 The Terraformer Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package terraformutils
+
+import (
+	"reflect"
+	"testing"
+)
+
+func prepareWithArn(id, arn, resourceType string) Resource {
+	r := prepareNoAttrs(id, resourceType)
+	r.InstanceState.Attributes["arn"] = arn
+	return r
+}
+
+func TestInferReferences(t *testing.T) {
+	importResources := map[string][]Resource{
+		"lambda": {prepare("my-function", "aws_lambda_function", map[string]string{}, map[string]interface{}{
+			"role":        "arn:aws:iam::123456789012:role/lambda",
+			"kms_key_arn": "arn:aws:kms:eu-west-1:123456789012:key/1234",
+			"layers":      []interface{}{"arn:aws:lambda:eu-west-1:123456789012:layer:shared:1"},
+			"timeout":     "123456",
+		}), prepareWithArn("shared", "arn:aws:lambda:eu-west-1:123456789012:layer:shared:1", "aws_lambda_layer_version")},
+		"iam": {prepareWithArn("lambda", "arn:aws:iam::123456789012:role/lambda", "aws_iam_role")},
+		"kms": {
+			prepareWithArn("1234", "arn:aws:kms:eu-west-1:123456789012:key/1234", "aws_kms_key"),
+			prepareWithArn("alias", "arn:aws:kms:eu-west-1:123456789012:key/1234", "aws_kms_alias"),
+		},
+		"misc": {prepareNoAttrs("123456", "aws_misc")},
+	}
+
+	inferred := InferReferences(importResources, map[string]map[string][]string{}, ServiceLinkFunc(true))
+
+	if !reflect.DeepEqual(importResources["lambda"][0].Item, map[string]interface{}{
+		"role":        "${data.terraform_remote_state.iam.outputs.aws_iam_role_tfer--name-002D-aws_iam_role_arn}",
+		"kms_key_arn": "arn:aws:kms:eu-west-1:123456789012:key/1234",
+		"layers":      []interface{}{"${aws_lambda_layer_version.tfer--name-002D-aws_lambda_layer_version.arn}"},
+		"timeout":     "123456",
+	}) {
+		t.Errorf("unexpected references %v", importResources["lambda"][0].Item)
+	}
+	if !reflect.DeepEqual(inferred.Connections, map[string]map[string][]string{"lambda": {"iam": {"role", "arn"}}}) {
+		t.Errorf("unexpected connections %v", inferred.Connections)
+	}
+	if len(inferred.References) != 2 {
+		t.Errorf("unexpected references %v", inferred.References)
+	}
+	if len(inferred.Ambiguous) != 1 || inferred.Ambiguous[0].Attribute != "kms_key_arn" || len(inferred.Ambiguous[0].Candidates) != 2 {
+		t.Errorf("unexpected ambiguous references %v", inferred.Ambiguous)
+	}
+}
+
+func TestInferReferencesOverrides(t *testing.T) {
+	importResources := map[string][]Resource{
+		"lambda": {prepare("my-function", "aws_lambda_function", map[string]string{}, map[string]interface{}{
+			"role": "arn:aws:iam::123456789012:role/lambda",
+		})},
+		"iam": {prepareWithArn("lambda", "arn:aws:iam::123456789012:role/lambda", "aws_iam_role")},
+	}
+	overrides := map[string]map[string][]string{"lambda": {"other": {"role", "arn"}}}
+
+	inferred := InferReferences(importResources, overrides, ServiceLinkFunc(true))
+
+	if importResources["lambda"][0].Item["role"] != "arn:aws:iam::123456789012:role/lambda" || len(inferred.References) != 0 {
+		t.Errorf("attribute of provider connections expected to be left unchanged, got %v", importResources["lambda"][0].Item)
+	}
+}
+
+func TestMergeConnections(t *testing.T) {
+	merged := MergeConnections(
+		map[string]map[string][]string{"lambda": {"iam": {"role", "arn"}}},
+		map[string]map[string][]string{"lambda": {"iam": {"role", "arn", "layers", "arn"}}, "ec2": {"vpc": {"vpc_id", "id"}}},
+	)
+	if !reflect.DeepEqual(merged, map[string]map[string][]string{
+		"lambda": {"iam": {"role", "arn", "layers", "arn"}},
+		"ec2":    {"vpc": {"vpc_id", "id"}},
+	}) {
+		t.Errorf("unexpected merged connections %v", merged)
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright 2018 The Terraformer Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package terraformutils
+
+import (
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"sync"
+)
+
+// ManagedResources holds the resources of existing terraform states, services skip them before refresh
+type ManagedResources struct {
+	resources map[string]bool
+	mutex     sync.Mutex
+	skipped   []Resource
+}
+
+// LoadManagedResources reads v3 and v4 state files
+func LoadManagedResources(paths []string) (*ManagedResources, error) {
+	m := &ManagedResources{resources: map[string]bool{}}
+	for _, path := range paths {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		resources, err := ReadTfStateResources(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read managed state %s: %s", path, err)
+		}
+		for _, r := range resources {
+			m.resources[resourceKey(r)] = true
+		}
+	}
+	return m, nil
+}
+
+// IsManaged reports whether a resource with the same type and ID exists in the managed states
+func (m *ManagedResources) IsManaged(r Resource) bool {
+	if m == nil {
+		return false
+	}
+	return m.resources[resourceKey(r)]
+}
+
+// Exclude drops managed resources and records them for the summary
+func (m *ManagedResources) Exclude(resources []Resource) []Resource {
+	if m == nil || len(m.resources) == 0 {
+		return resources
+	}
+	unmanaged := []Resource{}
+	for _, r := range resources {
+		if !m.IsManaged(r) {
+			unmanaged = append(unmanaged, r)
+			continue
+		}
+		m.mutex.Lock()
+		m.skipped = append(m.skipped, r)
+		m.mutex.Unlock()
+	}
+	return unmanaged
+}
+
+// Skipped returns the excluded resources ordered by address
+func (m *ManagedResources) Skipped() []Resource {
+	if m == nil {
+		return []Resource{}
+	}
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	skipped := append([]Resource{}, m.skipped...)
+	sort.Slice(skipped, func(i, j int) bool {
+		return skipped[i].Address() < skipped[j].Address()
+	})
+	return skipped
+}
+
+func resourceKey(r Resource) string {
+	return r.InstanceInfo.Type + "/" + r.InstanceState.ID
+}
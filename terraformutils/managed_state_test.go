@@ -0,0 +1,65 @@
+// Copyright 2018 The Terraformer Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package terraformutils
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func TestExcludeManagedResources(t *testing.T) {
+	dir, err := ioutil.TempDir("", "terraformer-managed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	v4, err := PrintTfState([]Resource{prepareNoAttrs("ID1", "type1")}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v3 := []byte(`{"version": 3, "modules": [{"resources": {
+		"type2.name": {"type": "type2", "primary": {"id": "ID2", "attributes": {"id": "ID2"}}}
+	}}]}`)
+	paths := []string{filepath.Join(dir, "v4.tfstate"), filepath.Join(dir, "v3.tfstate")}
+	for i, data := range [][]byte{v4, v3} {
+		if err := ioutil.WriteFile(paths[i], data, 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	managed, err := LoadManagedResources(paths)
+	if err != nil {
+		t.Fatal(err)
+	}
+	service := &Service{}
+	service.SetManagedResources(managed)
+	service.SetResources([]Resource{
+		prepareNoAttrs("ID1", "type1"),
+		prepareNoAttrs("ID2", "type2"),
+		prepareNoAttrs("ID2", "type1"),
+	})
+	service.InitialCleanup()
+
+	if len(service.Resources) != 1 || service.Resources[0].InstanceState.ID != "ID2" || service.Resources[0].InstanceInfo.Type != "type1" {
+		t.Errorf("unexpected resources %v", service.Resources)
+	}
+	if len(managed.Skipped()) != 2 {
+		t.Errorf("unexpected skipped resources %v", managed.Skipped())
+	}
+
+	if _, err := LoadManagedResources([]string{filepath.Join(dir, "missing.tfstate")}); err == nil {
+		t.Error("expected missing state error")
+	}
+}
@@ -0,0 +1,191 @@
+// Copyright 2018 The Terraformer Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package terraformutils
+
+import (
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	namingPlaceholder   = regexp.MustCompile(`\{([^{}]*)\}`)
+	escapedRune         = regexp.MustCompile(`-([0-9A-F]+)-`)
+	camelCaseBoundary   = regexp.MustCompile(`([a-z0-9])([A-Z])`)
+	nonSnakeChars       = regexp.MustCompile(`[^a-z0-9_]+`)
+	referenceCandidate  = regexp.MustCompile(`[A-Za-z_][\w-]*\.[\w-]+`)
+	duplicateUnderscore = regexp.MustCompile(`_+`)
+)
+
+// NamingTemplate builds resource names from placeholders evaluated against refreshed resources:
+// {id}, {name} (the name given by the provider), {type} (the resource type without provider prefix),
+// {tag:Key} (tags.Key or labels.Key) and any other attribute key, e.g. {bucket}.
+// Alternatives are separated by |, the first non empty one is used: {tag:Name|name}.
+type NamingTemplate struct {
+	template string
+}
+
+func NewNamingTemplate(template string) (NamingTemplate, error) {
+	if strings.Count(template, "{") != strings.Count(template, "}") {
+		return NamingTemplate{}, fmt.Errorf("unbalanced braces in naming template %q", template)
+	}
+	for _, match := range namingPlaceholder.FindAllStringSubmatch(template, -1) {
+		if strings.TrimSpace(match[1]) == "" {
+			return NamingTemplate{}, fmt.Errorf("empty placeholder in naming template %q", template)
+		}
+	}
+	return NamingTemplate{template: template}, nil
+}
+
+// Name evaluates the template for a resource and returns a snake_case name,
+// falling back to the provider name and then the ID when the result is empty
+func (t NamingTemplate) Name(r Resource) string {
+	name := namingPlaceholder.ReplaceAllStringFunc(t.template, func(placeholder string) string {
+		for _, alternative := range strings.Split(placeholder[1:len(placeholder)-1], "|") {
+			if value := namingValue(r, strings.TrimSpace(alternative)); value != "" {
+				return value
+			}
+		}
+		return ""
+	})
+	for _, candidate := range []string{name, OriginalName(r), r.InstanceState.ID} {
+		if name = SnakeCaseName(candidate); name != "" {
+			return name
+		}
+	}
+	return r.ResourceName
+}
+
+func namingValue(r Resource, placeholder string) string {
+	switch {
+	case placeholder == "id":
+		return r.InstanceState.ID
+	case placeholder == "name":
+		return OriginalName(r)
+	case placeholder == "type":
+		return r.ServiceName()
+	case strings.HasPrefix(placeholder, "tag:"):
+		key := strings.TrimPrefix(placeholder, "tag:")
+		if value := r.InstanceState.Attributes["tags."+key]; value != "" {
+			return value
+		}
+		return r.InstanceState.Attributes["labels."+key]
+	}
+	return r.InstanceState.Attributes[placeholder]
+}
+
+// OriginalName reverts TfSanitize on the resource name
+func OriginalName(r Resource) string {
+	name := strings.TrimPrefix(r.ResourceName, "tfer--")
+	return escapedRune.ReplaceAllStringFunc(name, func(escaped string) string {
+		decoded, err := hex.DecodeString(escaped[1 : len(escaped)-1])
+		if err != nil {
+			return escaped
+		}
+		return strings.TrimLeft(string(decoded), "\x00")
+	})
+}
+
+// SnakeCaseName turns any string into a readable identifier, e.g. "My Web-Server" into my_web_server
+func SnakeCaseName(name string) string {
+	name = camelCaseBoundary.ReplaceAllString(name, "${1}_${2}")
+	name = nonSnakeChars.ReplaceAllString(strings.ToLower(name), "_")
+	name = strings.Trim(duplicateUnderscore.ReplaceAllString(name, "_"), "_")
+	if name != "" && name[0] >= '0' && name[0] <= '9' {
+		name = "_" + name
+	}
+	return name
+}
+
+// RenameResources names resources with the template. Resources of the same type sharing a name
+// get _2, _3... suffixes in the order of their IDs. References to old addresses kept in
+// AdditionalFields and Item, e.g. depends_on, are updated to the new names.
+func RenameResources(resources []*Resource, template NamingTemplate) {
+	names := make(map[*Resource]string, len(resources))
+	for _, r := range resources {
+		names[r] = template.Name(*r)
+	}
+	sorted := make([]*Resource, len(resources))
+	copy(sorted, resources)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].InstanceInfo.Type != sorted[j].InstanceInfo.Type {
+			return sorted[i].InstanceInfo.Type < sorted[j].InstanceInfo.Type
+		}
+		if names[sorted[i]] != names[sorted[j]] {
+			return names[sorted[i]] < names[sorted[j]]
+		}
+		return sorted[i].InstanceState.ID < sorted[j].InstanceState.ID
+	})
+
+	used := map[string]bool{}
+	for _, r := range sorted {
+		used[r.InstanceInfo.Type+"."+names[r]] = true
+	}
+	assigned := map[string]bool{}
+	renames := map[string]string{}
+	for _, r := range sorted {
+		name := names[r]
+		if assigned[r.InstanceInfo.Type+"."+name] {
+			for i := 2; ; i++ {
+				candidate := name + "_" + strconv.Itoa(i)
+				if !used[r.InstanceInfo.Type+"."+candidate] && !assigned[r.InstanceInfo.Type+"."+candidate] {
+					name = candidate
+					break
+				}
+			}
+		}
+		assigned[r.InstanceInfo.Type+"."+name] = true
+		renames[r.Address()] = r.InstanceInfo.Type + "." + name
+		r.ResourceName = name
+		r.InstanceInfo.Id = r.Address()
+	}
+
+	for _, r := range resources {
+		for k, v := range r.AdditionalFields {
+			r.AdditionalFields[k] = renameReferences(v, renames)
+		}
+		for k, v := range r.Item {
+			r.Item[k] = renameReferences(v, renames)
+		}
+	}
+}
+
+func renameReferences(value interface{}, renames map[string]string) interface{} {
+	switch v := value.(type) {
+	case string:
+		return referenceCandidate.ReplaceAllStringFunc(v, func(address string) string {
+			if renamed, exist := renames[address]; exist {
+				return renamed
+			}
+			return address
+		})
+	case []string:
+		for i, e := range v {
+			v[i] = renameReferences(e, renames).(string)
+		}
+	case []interface{}:
+		for i, e := range v {
+			v[i] = renameReferences(e, renames)
+		}
+	case map[string]interface{}:
+		for k, e := range v {
+			v[k] = renameReferences(e, renames)
+		}
+	}
+	return value
+}
@@ -0,0 +1,78 @@
+// Copyright 2018 The Terraformer Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package terraformutils
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNamingTemplate(t *testing.T) {
+	r := NewResource("i-0abc", "i-0abc", "aws_instance", "aws", map[string]string{
+		"tags.Name":     "Web Server",
+		"instance_type": "t3.micro",
+	}, []string{}, map[string]interface{}{})
+
+	for template, expected := range map[string]string{
+		"{tag:Name}":                "web_server",
+		"{tag:Owner|name}":          "i_0abc",
+		"{type}_{instance_type}":    "instance_t3_micro",
+		"{tag:Owner}":               "i_0abc",
+		"MyPrefix-{id}":             "my_prefix_i_0abc",
+		"{tag:Owner|tag:Name} {id}": "web_server_i_0abc",
+	} {
+		naming, err := NewNamingTemplate(template)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if name := naming.Name(r); name != expected {
+			t.Errorf("template %s: expected %s, got %s", template, expected, name)
+		}
+	}
+
+	if _, err := NewNamingTemplate("{tag:Name"); err == nil {
+		t.Error("expected unbalanced braces error")
+	}
+}
+
+func TestOriginalName(t *testing.T) {
+	r := NewSimpleResource("id", "my-bucket.é", "aws_s3_bucket", "aws", []string{})
+	if name := OriginalName(r); name != "my-bucket.é" {
+		t.Errorf("failed to revert sanitized name, got %s", name)
+	}
+	if name := SnakeCaseName("0-Web"); name != "_0_web" {
+		t.Errorf("unexpected snake case name %s", name)
+	}
+}
+
+func TestRenameResources(t *testing.T) {
+	first := NewResource("ID2", "a", "type1", "provider", map[string]string{"tags.Name": "web"}, []string{}, map[string]interface{}{})
+	second := NewResource("ID1", "b", "type1", "provider", map[string]string{"tags.Name": "web"}, []string{}, map[string]interface{}{})
+	other := NewResource("ID3", "c", "type2", "provider", map[string]string{"tags.Name": "web"}, []string{}, map[string]interface{}{
+		"depends_on": []string{"type1.tfer--a"},
+	})
+	naming, _ := NewNamingTemplate("{tag:Name}")
+	RenameResources([]*Resource{&first, &second, &other}, naming)
+
+	if second.ResourceName != "web" || first.ResourceName != "web_2" || other.ResourceName != "web" {
+		t.Errorf("unexpected names %s %s %s", first.ResourceName, second.ResourceName, other.ResourceName)
+	}
+	if first.InstanceInfo.Id != "type1.web_2" {
+		t.Errorf("unexpected instance info id %s", first.InstanceInfo.Id)
+	}
+	if !reflect.DeepEqual(other.AdditionalFields["depends_on"], []string{"type1.web_2"}) {
+		t.Errorf("failed to rename references %v", other.AdditionalFields["depends_on"])
+	}
+}
@@ -17,6 +17,8 @@ type ProvidersMapping struct {
 	providerToService  map[ProviderGenerator]string
 	serviceToProvider  map[string]ProviderGenerator
 	resourceToProvider map[*Resource]ProviderGenerator
+	refreshedServices  map[string]bool
+	serviceReports     map[string]*ServiceReport
 }
 
 func NewProvidersMapping(baseProvider ProviderGenerator) *ProvidersMapping {
@@ -28,6 +30,8 @@ func NewProvidersMapping(baseProvider ProviderGenerator) *ProvidersMapping {
 		providerToService:  map[ProviderGenerator]string{},
 		serviceToProvider:  map[string]ProviderGenerator{},
 		resourceToProvider: map[*Resource]ProviderGenerator{},
+		refreshedServices:  map[string]bool{},
+		serviceReports:     map[string]*ServiceReport{},
 	}
 
 	return providersMapping
@@ -68,9 +72,33 @@ func (p *ProvidersMapping) RemoveServices(services []string) {
 		delete(p.Providers, matchingProvider)
 		delete(p.providerToService, matchingProvider)
 		delete(p.serviceToProvider, service)
+		delete(p.refreshedServices, service)
 	}
 }
 
+// MarkServiceRefreshed excludes the resources of a service restored from a checkpoint from refreshing
+func (p *ProvidersMapping) MarkServiceRefreshed(service string) {
+	p.refreshedServices[service] = true
+}
+
+func (p *ProvidersMapping) IsServiceRefreshed(service string) bool {
+	return p.refreshedServices[service]
+}
+
+// SetServiceReport sets the report recording the outcome of a service
+func (p *ProvidersMapping) SetServiceReport(service string, report *ServiceReport) {
+	p.serviceReports[service] = report
+}
+
+// GetServiceReport returns the report of a service, nil when the run isn't reported
+func (p *ProvidersMapping) GetServiceReport(service string) *ServiceReport {
+	return p.serviceReports[service]
+}
+
+func (p *ProvidersMapping) MatchService(resource *Resource) string {
+	return p.providerToService[p.resourceToProvider[resource]]
+}
+
 func (p *ProvidersMapping) ShuffleResources() []*Resource {
 	resources := []*Resource{}
 	for resource := range p.Resources {
@@ -131,11 +159,21 @@ func (p *ProvidersMapping) GetResourcesByService() map[string][]Resource {
 	return mapping
 }
 
+// RenameResources applies a naming template to the refreshed resources of all services
+func (p *ProvidersMapping) RenameResources(template NamingTemplate) {
+	resources := []*Resource{}
+	for resource := range p.Resources {
+		resources = append(resources, resource)
+	}
+	RenameResources(resources, template)
+}
+
 func (p *ProvidersMapping) ConvertTFStates(providerWrapper *providerwrapper.ProviderWrapper) {
 	for resource := range p.Resources {
 		err := resource.ConvertTFstate(providerWrapper)
 		if err != nil {
 			log.Printf("failed to convert resources %s because of error %s", resource.InstanceInfo.Id, err)
+			p.GetServiceReport(p.MatchService(resource)).AddFailure(resource.Address(), resource.InstanceState.ID, err)
 		}
 	}
 
@@ -0,0 +1,375 @@
+// Copyright 2018 The Terraformer Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package providerwrapper //nolint
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// LockFileName is the dependency lock file terraform init reads provider versions and hashes from
+const LockFileName = ".terraform.lock.hcl"
+
+// ProviderBinary is an installed provider plugin
+type ProviderBinary struct {
+	Path string
+	// Source is the address of the provider, e.g. registry.terraform.io/hashicorp/aws, empty outside of registry layouts
+	Source string
+	// Version is nil when it can't be read from the path
+	Version *version.Version
+}
+
+type providerSelection struct {
+	path        string
+	constraint  string
+	constraints version.Constraints
+}
+
+var (
+	selectionsMutex sync.Mutex
+	selections      = map[string]providerSelection{}
+	hashesMutex     sync.Mutex
+	hashes          = map[string]string{}
+)
+
+// SelectProvider sets the binary or the version constraint, e.g. "~> 3.0", used for a provider
+// instead of the last installed one. Empty values keep the default.
+func SelectProvider(providerName, path, versionConstraint string) error {
+	selection := providerSelection{path: path, constraint: versionConstraint}
+	if path != "" {
+		if _, err := os.Stat(path); err != nil {
+			return fmt.Errorf("provider %s: %w", providerName, err)
+		}
+	}
+	if versionConstraint != "" {
+		constraints, err := version.NewConstraint(versionConstraint)
+		if err != nil {
+			return fmt.Errorf("provider %s version: %w", providerName, err)
+		}
+		selection.constraints = constraints
+	}
+	selectionsMutex.Lock()
+	defer selectionsMutex.Unlock()
+	selections[providerName] = selection
+	return nil
+}
+
+func getSelection(providerName string) providerSelection {
+	selectionsMutex.Lock()
+	defer selectionsMutex.Unlock()
+	return selections[providerName]
+}
+
+// FindProvider returns the binary of a provider: the selected one, else the highest installed version
+// matching the selected constraint, else the highest version of the first plugin directory holding one
+func FindProvider(providerName string) (*ProviderBinary, error) {
+	selection := getSelection(providerName)
+	if selection.path != "" {
+		return providerBinaryFromPath(selection.path, providerName), nil
+	}
+	installed := installedProviders(providerName)
+	if selection.constraints != nil {
+		var matching []*ProviderBinary
+		var versions []string
+		for _, binaries := range installed {
+			for _, binary := range binaries {
+				if binary.Version == nil {
+					continue
+				}
+				versions = append(versions, binary.Version.String())
+				if selection.constraints.Check(binary.Version) {
+					matching = append(matching, binary)
+				}
+			}
+		}
+		if len(matching) == 0 {
+			return nil, fmt.Errorf("no installed %s provider matches version %s, found %s", providerName, selection.constraint, strings.Join(versions, ", "))
+		}
+		return highestVersion(matching), nil
+	}
+	for _, binaries := range installed {
+		if len(binaries) > 0 {
+			return highestVersion(binaries), nil
+		}
+	}
+	return nil, fmt.Errorf("provider %s not found, ensure that you are following https://www.terraform.io/docs/configuration/providers.html#third-party-plugins", providerName)
+}
+
+// highestVersion returns the binary with the highest version, the last one for equal versions
+func highestVersion(binaries []*ProviderBinary) *ProviderBinary {
+	highest := binaries[0]
+	for _, binary := range binaries[1:] {
+		if highest.Version == nil || (binary.Version != nil && !binary.Version.LessThan(highest.Version)) {
+			highest = binary
+		}
+	}
+	return highest
+}
+
+// installedProviders lists the binaries of a provider by plugin directory, in the order directories are searched:
+// the data dir, TF_PLUGIN_CACHE_DIR, ~/.terraform.d, then the terraform 0.12 plugin directories
+func installedProviders(providerName string) [][]*ProviderBinary {
+	dataDir := os.Getenv("TF_DATA_DIR")
+	if dataDir == "" {
+		dataDir = DefaultDataDir
+	}
+	homeDir := filepath.Join(os.Getenv("HOME"), ".terraform.d")
+	installed := [][]*ProviderBinary{
+		append(registryProviders(filepath.Join(dataDir, "providers"), providerName),
+			registryProviders(filepath.Join(dataDir, "plugins"), providerName)...),
+	}
+	if cacheDir := os.Getenv("TF_PLUGIN_CACHE_DIR"); cacheDir != "" {
+		installed = append(installed, registryProviders(cacheDir, providerName))
+	}
+	installed = append(installed,
+		append(registryProviders(filepath.Join(homeDir, "providers"), providerName),
+			registryProviders(filepath.Join(homeDir, "plugins"), providerName)...),
+		legacyProviders(filepath.Join(dataDir, "plugins", pluginMachineName), providerName),
+		legacyProviders(filepath.Join(os.Getenv("HOME"), "."+DefaultPluginVendorDirV12), providerName),
+	)
+	return installed
+}
+
+// registryProviders lists binaries installed by terraform 0.13 and later, for every registry host and namespace:
+// <host>/<namespace>/<name>/<version>/<os_arch>/terraform-provider-<name>
+func registryProviders(registryDir, providerName string) []*ProviderBinary {
+	binaries := []*ProviderBinary{}
+	hostDirs, err := ioutil.ReadDir(registryDir)
+	if err != nil {
+		return binaries
+	}
+	// providers of the public registry first, then those of other hosts, e.g. private registries
+	sort.SliceStable(hostDirs, func(i, j int) bool {
+		return hostDirs[i].Name() == DefaultProviderRegistryHost && hostDirs[j].Name() != DefaultProviderRegistryHost
+	})
+	for _, hostDir := range hostDirs {
+		namespaceDirs, err := ioutil.ReadDir(filepath.Join(registryDir, hostDir.Name()))
+		if err != nil {
+			continue
+		}
+		for _, namespaceDir := range namespaceDirs {
+			pluginPath := filepath.Join(registryDir, hostDir.Name(), namespaceDir.Name(), providerName)
+			versionDirs, err := ioutil.ReadDir(pluginPath)
+			if err != nil {
+				continue
+			}
+			for _, versionDir := range versionDirs {
+				platformPath := filepath.Join(pluginPath, versionDir.Name(), pluginMachineName)
+				for _, binary := range legacyProviders(platformPath, providerName) {
+					binary.Source = strings.Join([]string{hostDir.Name(), namespaceDir.Name(), providerName}, "/")
+					if v, err := version.NewVersion(versionDir.Name()); err == nil {
+						binary.Version = v
+					}
+					binaries = append(binaries, binary)
+				}
+			}
+		}
+	}
+	return binaries
+}
+
+// legacyProviders lists the binaries of a directory, named terraform-provider-<name>_v<version>
+func legacyProviders(pluginDir, providerName string) []*ProviderBinary {
+	binaries := []*ProviderBinary{}
+	files, err := ioutil.ReadDir(pluginDir)
+	if err != nil {
+		return binaries
+	}
+	for _, file := range files {
+		if file.IsDir() || !isProviderFileName(file.Name(), providerName) {
+			continue
+		}
+		binaries = append(binaries, &ProviderBinary{
+			Path:    filepath.Join(pluginDir, file.Name()),
+			Version: fileNameVersion(file.Name()),
+		})
+	}
+	return binaries
+}
+
+// isProviderFileName tells whether the file is a binary of the provider, terraform-provider-aws is not one of aws
+func isProviderFileName(fileName, providerName string) bool {
+	name := strings.TrimSuffix(fileName, ".exe")
+	prefix := "terraform-provider-" + providerName
+	return name == prefix || strings.HasPrefix(name, prefix+"_")
+}
+
+func fileNameVersion(fileName string) *version.Version {
+	parts := strings.Split(strings.TrimSuffix(fileName, ".exe"), "_")
+	if len(parts) < 2 {
+		return nil
+	}
+	v, err := version.NewVersion(parts[1])
+	if err != nil {
+		return nil
+	}
+	return v
+}
+
+// providerBinaryFromPath reads the source and version of a binary given by path, from a registry layout or its name
+func providerBinaryFromPath(path, providerName string) *ProviderBinary {
+	binary := &ProviderBinary{Path: path, Version: fileNameVersion(filepath.Base(path))}
+	// .../<host>/<namespace>/<name>/<version>/<os_arch>/terraform-provider-<name>
+	parts := strings.Split(filepath.ToSlash(filepath.Clean(path)), "/")
+	if len(parts) >= 6 && parts[len(parts)-4] == providerName {
+		if v, err := version.NewVersion(parts[len(parts)-3]); err == nil {
+			binary.Source = strings.Join(parts[len(parts)-6:len(parts)-3], "/")
+			binary.Version = v
+		}
+	}
+	return binary
+}
+
+// versionConstraint returns the selected constraint, else one allowing patch releases of the binary version
+func versionConstraint(providerName string, binary *ProviderBinary) string {
+	if constraint := getSelection(providerName).constraint; constraint != "" {
+		return constraint
+	}
+	if binary.Version == nil {
+		return ""
+	}
+	return "~> " + binary.Version.String()
+}
+
+func GetProviderVersion(providerName string) string {
+	binary, err := FindProvider(providerName)
+	if err != nil {
+		log.Println("Can't find provider file path. Ensure that you are following https://www.terraform.io/docs/configuration/providers.html#third-party-plugins.")
+		return ""
+	}
+	constraint := versionConstraint(providerName, binary)
+	if constraint == "" {
+		log.Println("Can't find provider version. Ensure that you are following https://www.terraform.io/docs/configuration/providers.html#plugin-names-and-versions.")
+	}
+	return constraint
+}
+
+// GetProviderSource returns the fully qualified source address of the provider, e.g. registry.terraform.io/hashicorp/aws
+func GetProviderSource(providerName string) string {
+	binary, err := FindProvider(providerName)
+	if err == nil && binary.Source != "" {
+		return binary.Source
+	}
+	return DefaultProviderRegistryHost + "/hashicorp/" + providerName
+}
+
+// GetProviderSourceAddress returns the source address of the provider as written in required_providers,
+// without the host of the public registry, e.g. hashicorp/aws
+func GetProviderSourceAddress(providerName string) string {
+	return strings.TrimPrefix(GetProviderSource(providerName), DefaultProviderRegistryHost+"/")
+}
+
+// LockFile returns the dependency lock file pinning the version and hash of the binary used for the provider,
+// nil when the binary or its version is unknown
+func LockFile(providerName string) ([]byte, error) {
+	binary, err := FindProvider(providerName)
+	if err != nil || binary.Version == nil {
+		return nil, nil
+	}
+	hash, err := packageHash(binary)
+	if err != nil {
+		return nil, err
+	}
+	file := hclwrite.NewEmptyFile()
+	body := file.Body()
+	body.AppendUnstructuredTokens(hclwrite.Tokens{
+		{Bytes: []byte("# This file is maintained automatically by \"terraform init\".\n")},
+		{Bytes: []byte("# Manual edits may be lost in future updates.\n")},
+		{Bytes: []byte("\n")},
+	})
+	block := body.AppendNewBlock("provider", []string{GetProviderSource(providerName)})
+	block.Body().SetAttributeRaw("version", hclwrite.TokensForValue(cty.StringVal(binary.Version.String())))
+	if constraint := versionConstraint(providerName, binary); constraint != "" {
+		block.Body().SetAttributeRaw("constraints", hclwrite.TokensForValue(cty.StringVal(constraint)))
+	}
+	block.Body().SetAttributeRaw("hashes", hclwrite.Tokens{
+		{Bytes: []byte("[\n")},
+		{Bytes: []byte(fmt.Sprintf("%q,\n", hash))},
+		{Bytes: []byte("]")},
+	})
+	return hclwrite.Format(file.Bytes()), nil
+}
+
+// packageHash returns the h1 hash terraform init records for a provider package: the hash of the directory
+// installed from a registry, of the binary alone otherwise
+func packageHash(binary *ProviderBinary) (string, error) {
+	hashesMutex.Lock()
+	defer hashesMutex.Unlock()
+	if hash, exist := hashes[binary.Path]; exist {
+		return hash, nil
+	}
+	files := map[string]string{filepath.Base(binary.Path): binary.Path}
+	if binary.Source != "" {
+		packageDir := filepath.Dir(binary.Path)
+		files = map[string]string{}
+		err := filepath.Walk(packageDir, func(path string, info os.FileInfo, err error) error {
+			if err != nil || !info.Mode().IsRegular() {
+				return err
+			}
+			name, err := filepath.Rel(packageDir, path)
+			if err != nil {
+				return err
+			}
+			files[filepath.ToSlash(name)] = path
+			return nil
+		})
+		if err != nil {
+			return "", err
+		}
+	}
+	hash, err := hashFiles(files)
+	if err != nil {
+		return "", err
+	}
+	hashes[binary.Path] = hash
+	return hash, nil
+}
+
+// hashFiles computes the h1 hash of files by name, the dirhash Hash1 of Go modules terraform uses for packages
+func hashFiles(files map[string]string) (string, error) {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	summary := sha256.New()
+	for _, name := range names {
+		f, err := os.Open(files[name])
+		if err != nil {
+			return "", err
+		}
+		fileHash := sha256.New()
+		_, err = io.Copy(fileHash, f)
+		f.Close()
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(summary, "%x  %s\n", fileHash.Sum(nil), name)
+	}
+	return "h1:" + base64.StdEncoding.EncodeToString(summary.Sum(nil)), nil
+}
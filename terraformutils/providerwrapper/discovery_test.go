@@ -0,0 +1,124 @@
+// Copyright 2018 The Terraformer Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package providerwrapper //nolint
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func installProvider(t *testing.T, dir, source, version string) string {
+	parts := strings.Split(source, "/")
+	pluginDir := filepath.Join(dir, parts[0], parts[1], parts[2], version, pluginMachineName)
+	if err := os.MkdirAll(pluginDir, os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(pluginDir, "terraform-provider-"+parts[2]+"_v"+version)
+	if err := ioutil.WriteFile(path, []byte(version), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestFindProvider(t *testing.T) {
+	dataDir, err := ioutil.TempDir("", "terraformer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dataDir)
+	cacheDir := filepath.Join(dataDir, "cache")
+	installProvider(t, filepath.Join(dataDir, "providers"), "registry.terraform.io/hashicorp/aws", "3.10.0")
+	installProvider(t, filepath.Join(dataDir, "providers"), "registry.terraform.io/hashicorp/aws", "3.9.0")
+	installProvider(t, cacheDir, "registry.terraform.io/hashicorp/aws", "2.70.0")
+	installProvider(t, filepath.Join(dataDir, "providers"), "registry.terraform.io/hashicorp/awscc", "0.1.0")
+	os.Setenv("TF_DATA_DIR", dataDir)
+	defer os.Unsetenv("TF_DATA_DIR")
+	os.Setenv("TF_PLUGIN_CACHE_DIR", cacheDir)
+	defer os.Unsetenv("TF_PLUGIN_CACHE_DIR")
+	defer SelectProvider("aws", "", "") //nolint
+
+	testCases := []struct {
+		constraint string
+		version    string
+	}{
+		{"", "3.10.0"},
+		{"~> 3.9.0", "3.9.0"},
+		{"< 3.0", "2.70.0"},
+	}
+	for _, tc := range testCases {
+		if err := SelectProvider("aws", "", tc.constraint); err != nil {
+			t.Fatal(err)
+		}
+		binary, err := FindProvider("aws")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if binary.Version.String() != tc.version || binary.Source != "registry.terraform.io/hashicorp/aws" {
+			t.Errorf("constraint %q: unexpected binary %s", tc.constraint, binary.Path)
+		}
+	}
+
+	if err := SelectProvider("aws", "", "> 4.0"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := FindProvider("aws"); err == nil {
+		t.Error("expected no provider matching > 4.0")
+	}
+	if err := SelectProvider("aws", "", "not a version"); err == nil {
+		t.Error("expected an invalid constraint error")
+	}
+}
+
+func TestLockFile(t *testing.T) {
+	dataDir, err := ioutil.TempDir("", "terraformer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dataDir)
+	path := installProvider(t, filepath.Join(dataDir, "providers"), "registry.example.com/acme/widget", "1.2.3")
+	if err := SelectProvider("widget", path, ""); err != nil {
+		t.Fatal(err)
+	}
+	defer SelectProvider("widget", "", "") //nolint
+
+	lockFile, err := LockFile("widget")
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash, err := hashFiles(map[string]string{filepath.Base(path): path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `# This file is maintained automatically by "terraform init".
+# Manual edits may be lost in future updates.
+
+provider "registry.example.com/acme/widget" {
+  version     = "1.2.3"
+  constraints = "~> 1.2.3"
+  hashes = [
+    "` + hash + `",
+  ]
+}
+`
+	if string(lockFile) != expected {
+		t.Errorf("unexpected lock file %s", string(lockFile))
+	}
+	if source := GetProviderSourceAddress("widget"); source != "registry.example.com/acme/widget" {
+		t.Errorf("unexpected source %s", source)
+	}
+}
@@ -0,0 +1,501 @@
+// Copyright 2018 The Terraformer Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package providerwrapper //nolint
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"sync/atomic"
+
+	"github.com/GoogleCloudPlatform/terraformer/terraformutils/providerwrapper/tfplugin6"
+
+	"github.com/hashicorp/go-plugin"
+	"github.com/hashicorp/terraform/configs/configschema"
+	"github.com/hashicorp/terraform/providers"
+	"github.com/hashicorp/terraform/tfdiags"
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+	"github.com/zclconf/go-cty/cty/msgpack"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errNotSupported is returned by the provider calls terraformer never makes
+var errNotSupported = errors.New("not supported by terraformer")
+
+// versionedPlugins are the plugin protocol versions negotiated with providers, 5 and 6
+var versionedPlugins = map[int]plugin.PluginSet{
+	5: {"provider": &GRPCProviderPlugin{protocol: 5}},
+	6: {"provider": &GRPCProviderPlugin{protocol: 6}},
+}
+
+// NestedType is an attribute of a protocol 6 schema made of nested attributes. The terraform 0.12 schema
+// only keeps the type it implies, nested types keep which attributes inside are required or computed.
+type NestedType struct {
+	Nesting     configschema.NestingMode
+	Attributes  map[string]*configschema.Attribute
+	NestedTypes map[string]*NestedType
+}
+
+// providerClient is the part of the provider service terraformer calls
+type providerClient interface {
+	GetProviderSchema(ctx context.Context, in *tfplugin6.GetProviderSchema_Request, opts ...grpc.CallOption) (*tfplugin6.GetProviderSchema_Response, error)
+	ConfigureProvider(ctx context.Context, in *tfplugin6.ConfigureProvider_Request, opts ...grpc.CallOption) (*tfplugin6.ConfigureProvider_Response, error)
+	ReadResource(ctx context.Context, in *tfplugin6.ReadResource_Request, opts ...grpc.CallOption) (*tfplugin6.ReadResource_Response, error)
+	ImportResourceState(ctx context.Context, in *tfplugin6.ImportResourceState_Request, opts ...grpc.CallOption) (*tfplugin6.ImportResourceState_Response, error)
+	StopProvider(ctx context.Context, in *tfplugin6.StopProvider_Request, opts ...grpc.CallOption) (*tfplugin6.StopProvider_Response, error)
+}
+
+// GRPCProviderPlugin implements plugin.GRPCPlugin for providers served over protocol 5 or 6
+type GRPCProviderPlugin struct {
+	plugin.Plugin
+	protocol int
+}
+
+func (p *GRPCProviderPlugin) GRPCClient(ctx context.Context, broker *plugin.GRPCBroker, c *grpc.ClientConn) (interface{}, error) {
+	var client providerClient = tfplugin6.NewProviderClient(c)
+	if p.protocol == 5 {
+		client = &protocol5Client{cc: c}
+	}
+	return &GRPCProvider{
+		client: client,
+		ctx:    ctx,
+	}, nil
+}
+
+func (p *GRPCProviderPlugin) GRPCServer(broker *plugin.GRPCBroker, s *grpc.Server) error {
+	return errors.New("terraformer only runs provider clients")
+}
+
+// GRPCProvider is the providers.Interface of the provider plugins. Unlike the terraform 0.12 client,
+// it speaks protocol 6 and can be given a cached schema instead of fetching it from the plugin.
+type GRPCProvider struct {
+	client providerClient
+	ctx    context.Context
+
+	mutex       sync.Mutex
+	schemas     providers.GetSchemaResponse
+	nestedTypes map[string]map[string]*NestedType
+	// unavailable is set once a call fails because the connection to the plugin is gone
+	unavailable int32
+}
+
+// checkTransport records calls that failed on the connection itself, not in the provider
+func (p *GRPCProvider) checkTransport(err error) {
+	if status.Code(err) == codes.Unavailable {
+		atomic.StoreInt32(&p.unavailable, 1)
+	}
+}
+
+// ConnectionLost tells whether a call failed because the plugin could not be reached
+func (p *GRPCProvider) ConnectionLost() bool {
+	return atomic.LoadInt32(&p.unavailable) == 1
+}
+
+// SetSchema gives the provider a schema cached from an earlier run, the plugin is not asked for it again
+func (p *GRPCProvider) SetSchema(schema providers.GetSchemaResponse, nestedTypes map[string]map[string]*NestedType) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.schemas = schema
+	p.nestedTypes = nestedTypes
+}
+
+// NestedTypes returns the attributes with nested attributes of each resource type
+func (p *GRPCProvider) NestedTypes() map[string]map[string]*NestedType {
+	p.GetSchema()
+	return p.nestedTypes
+}
+
+func (p *GRPCProvider) GetSchema() (resp providers.GetSchemaResponse) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if p.schemas.Provider.Block != nil {
+		return p.schemas
+	}
+
+	resp.ResourceTypes = map[string]providers.Schema{}
+	resp.DataSources = map[string]providers.Schema{}
+	// schemas of big providers are over the default grpc limit of 4MB
+	const maxRecvSize = 64 << 20
+	protoResp, err := p.client.GetProviderSchema(p.ctx, new(tfplugin6.GetProviderSchema_Request), grpc.MaxRecvMsgSizeCallOption{MaxRecvMsgSize: maxRecvSize})
+	if err != nil {
+		p.checkTransport(err)
+		resp.Diagnostics = resp.Diagnostics.Append(err)
+		return resp
+	}
+	resp.Diagnostics = resp.Diagnostics.Append(protoToDiagnostics(protoResp.Diagnostics))
+	if protoResp.Provider == nil {
+		resp.Diagnostics = resp.Diagnostics.Append(errors.New("missing provider schema"))
+		return resp
+	}
+
+	nestedTypes := map[string]map[string]*NestedType{}
+	resp.Provider, _, err = protoToProviderSchema(protoResp.Provider)
+	if err != nil {
+		resp.Diagnostics = resp.Diagnostics.Append(err)
+		return resp
+	}
+	for name, res := range protoResp.ResourceSchemas {
+		schema, resourceNestedTypes, err := protoToProviderSchema(res)
+		if err != nil {
+			resp.Diagnostics = resp.Diagnostics.Append(fmt.Errorf("schema of %s: %w", name, err))
+			return resp
+		}
+		resp.ResourceTypes[name] = schema
+		if len(resourceNestedTypes) > 0 {
+			nestedTypes[name] = resourceNestedTypes
+		}
+	}
+	for name, data := range protoResp.DataSourceSchemas {
+		schema, _, err := protoToProviderSchema(data)
+		if err != nil {
+			resp.Diagnostics = resp.Diagnostics.Append(fmt.Errorf("schema of %s: %w", name, err))
+			return resp
+		}
+		resp.DataSources[name] = schema
+	}
+
+	p.schemas = resp
+	p.nestedTypes = nestedTypes
+	return resp
+}
+
+func (p *GRPCProvider) getResourceSchema(name string) (providers.Schema, error) {
+	schema := p.GetSchema()
+	if schema.Diagnostics.HasErrors() {
+		return providers.Schema{}, schema.Diagnostics.Err()
+	}
+	resourceSchema, exist := schema.ResourceTypes[name]
+	if !exist {
+		return providers.Schema{}, fmt.Errorf("unknown resource type %s", name)
+	}
+	return resourceSchema, nil
+}
+
+func (p *GRPCProvider) PrepareProviderConfig(r providers.PrepareProviderConfigRequest) (resp providers.PrepareProviderConfigResponse) {
+	resp.PreparedConfig = r.Config
+	return resp
+}
+
+func (p *GRPCProvider) ValidateResourceTypeConfig(r providers.ValidateResourceTypeConfigRequest) (resp providers.ValidateResourceTypeConfigResponse) {
+	resp.Diagnostics = resp.Diagnostics.Append(errNotSupported)
+	return resp
+}
+
+func (p *GRPCProvider) ValidateDataSourceConfig(r providers.ValidateDataSourceConfigRequest) (resp providers.ValidateDataSourceConfigResponse) {
+	resp.Diagnostics = resp.Diagnostics.Append(errNotSupported)
+	return resp
+}
+
+func (p *GRPCProvider) UpgradeResourceState(r providers.UpgradeResourceStateRequest) (resp providers.UpgradeResourceStateResponse) {
+	resp.Diagnostics = resp.Diagnostics.Append(errNotSupported)
+	return resp
+}
+
+func (p *GRPCProvider) Configure(r providers.ConfigureRequest) (resp providers.ConfigureResponse) {
+	schema := p.GetSchema()
+	if schema.Diagnostics.HasErrors() {
+		resp.Diagnostics = resp.Diagnostics.Append(schema.Diagnostics)
+		return resp
+	}
+	mp, err := msgpack.Marshal(r.Config, schema.Provider.Block.ImpliedType())
+	if err != nil {
+		resp.Diagnostics = resp.Diagnostics.Append(err)
+		return resp
+	}
+	protoResp, err := p.client.ConfigureProvider(p.ctx, &tfplugin6.ConfigureProvider_Request{
+		TerraformVersion: r.TerraformVersion,
+		Config:           &tfplugin6.DynamicValue{Msgpack: mp},
+	})
+	if err != nil {
+		p.checkTransport(err)
+		resp.Diagnostics = resp.Diagnostics.Append(err)
+		return resp
+	}
+	resp.Diagnostics = resp.Diagnostics.Append(protoToDiagnostics(protoResp.Diagnostics))
+	return resp
+}
+
+func (p *GRPCProvider) Stop() error {
+	resp, err := p.client.StopProvider(p.ctx, new(tfplugin6.StopProvider_Request))
+	if err != nil {
+		p.checkTransport(err)
+		return err
+	}
+	if resp.Error != "" {
+		return errors.New(resp.Error)
+	}
+	return nil
+}
+
+func (p *GRPCProvider) ReadResource(r providers.ReadResourceRequest) (resp providers.ReadResourceResponse) {
+	resourceSchema, err := p.getResourceSchema(r.TypeName)
+	if err != nil {
+		resp.Diagnostics = resp.Diagnostics.Append(err)
+		return resp
+	}
+	ty := resourceSchema.Block.ImpliedType()
+	mp, err := msgpack.Marshal(r.PriorState, ty)
+	if err != nil {
+		resp.Diagnostics = resp.Diagnostics.Append(err)
+		return resp
+	}
+	protoResp, err := p.client.ReadResource(p.ctx, &tfplugin6.ReadResource_Request{
+		TypeName:     r.TypeName,
+		CurrentState: &tfplugin6.DynamicValue{Msgpack: mp},
+		Private:      r.Private,
+	})
+	if err != nil {
+		p.checkTransport(err)
+		resp.Diagnostics = resp.Diagnostics.Append(err)
+		return resp
+	}
+	resp.Diagnostics = resp.Diagnostics.Append(protoToDiagnostics(protoResp.Diagnostics))
+
+	state := cty.NullVal(ty)
+	if protoResp.NewState != nil {
+		state, err = decodeDynamicValue(protoResp.NewState, ty)
+		if err != nil {
+			resp.Diagnostics = resp.Diagnostics.Append(err)
+			return resp
+		}
+	}
+	resp.NewState = state
+	resp.Private = protoResp.Private
+	return resp
+}
+
+func (p *GRPCProvider) PlanResourceChange(r providers.PlanResourceChangeRequest) (resp providers.PlanResourceChangeResponse) {
+	resp.Diagnostics = resp.Diagnostics.Append(errNotSupported)
+	return resp
+}
+
+func (p *GRPCProvider) ApplyResourceChange(r providers.ApplyResourceChangeRequest) (resp providers.ApplyResourceChangeResponse) {
+	resp.Diagnostics = resp.Diagnostics.Append(errNotSupported)
+	return resp
+}
+
+func (p *GRPCProvider) ImportResourceState(r providers.ImportResourceStateRequest) (resp providers.ImportResourceStateResponse) {
+	protoResp, err := p.client.ImportResourceState(p.ctx, &tfplugin6.ImportResourceState_Request{
+		TypeName: r.TypeName,
+		Id:       r.ID,
+	})
+	if err != nil {
+		p.checkTransport(err)
+		resp.Diagnostics = resp.Diagnostics.Append(err)
+		return resp
+	}
+	resp.Diagnostics = resp.Diagnostics.Append(protoToDiagnostics(protoResp.Diagnostics))
+
+	for _, imported := range protoResp.ImportedResources {
+		resourceSchema, err := p.getResourceSchema(imported.TypeName)
+		if err != nil {
+			resp.Diagnostics = resp.Diagnostics.Append(err)
+			return resp
+		}
+		ty := resourceSchema.Block.ImpliedType()
+		state := cty.NullVal(ty)
+		if imported.State != nil {
+			state, err = decodeDynamicValue(imported.State, ty)
+			if err != nil {
+				resp.Diagnostics = resp.Diagnostics.Append(err)
+				return resp
+			}
+		}
+		resp.ImportedResources = append(resp.ImportedResources, providers.ImportedResource{
+			TypeName: imported.TypeName,
+			State:    state,
+			Private:  imported.Private,
+		})
+	}
+	return resp
+}
+
+func (p *GRPCProvider) ReadDataSource(r providers.ReadDataSourceRequest) (resp providers.ReadDataSourceResponse) {
+	resp.Diagnostics = resp.Diagnostics.Append(errNotSupported)
+	return resp
+}
+
+// Close does nothing, the plugin process is killed by its client
+func (p *GRPCProvider) Close() error {
+	log.Println("[TRACE] GRPCProvider: Close")
+	return nil
+}
+
+// decodeDynamicValue decodes msgpack values, or JSON ones sent by some providers
+func decodeDynamicValue(v *tfplugin6.DynamicValue, ty cty.Type) (cty.Value, error) {
+	if len(v.Msgpack) == 0 && len(v.Json) > 0 {
+		return ctyjson.Unmarshal(v.Json, ty)
+	}
+	return msgpack.Unmarshal(v.Msgpack, ty)
+}
+
+func protoToProviderSchema(s *tfplugin6.Schema) (providers.Schema, map[string]*NestedType, error) {
+	block, nestedTypes, err := protoToConfigSchema(s.Block)
+	if err != nil {
+		return providers.Schema{}, nil, err
+	}
+	return providers.Schema{Version: s.Version, Block: block}, nestedTypes, nil
+}
+
+func protoToConfigSchema(b *tfplugin6.Schema_Block) (*configschema.Block, map[string]*NestedType, error) {
+	block := &configschema.Block{
+		Attributes: map[string]*configschema.Attribute{},
+		BlockTypes: map[string]*configschema.NestedBlock{},
+	}
+	if b == nil {
+		return block, nil, nil
+	}
+	attributes, nestedTypes, err := protoToAttributes(b.Attributes)
+	if err != nil {
+		return nil, nil, err
+	}
+	block.Attributes = attributes
+	for _, nestedBlock := range b.BlockTypes {
+		nested, err := protoToNestedBlock(nestedBlock)
+		if err != nil {
+			return nil, nil, err
+		}
+		block.BlockTypes[nestedBlock.TypeName] = nested
+	}
+	return block, nestedTypes, nil
+}
+
+// protoToAttributes converts attributes, those with nested attributes get the type they imply
+func protoToAttributes(protoAttributes []*tfplugin6.Schema_Attribute) (map[string]*configschema.Attribute, map[string]*NestedType, error) {
+	attributes := map[string]*configschema.Attribute{}
+	nestedTypes := map[string]*NestedType{}
+	for _, a := range protoAttributes {
+		attribute := &configschema.Attribute{
+			Description: a.Description,
+			Required:    a.Required,
+			Optional:    a.Optional,
+			Computed:    a.Computed,
+			Sensitive:   a.Sensitive,
+		}
+		if a.NestedType != nil {
+			nestedType, err := protoToNestedType(a.NestedType)
+			if err != nil {
+				return nil, nil, err
+			}
+			attribute.Type = nestedType.impliedType()
+			nestedTypes[a.Name] = nestedType
+		} else if err := json.Unmarshal(a.Type, &attribute.Type); err != nil {
+			return nil, nil, fmt.Errorf("type of attribute %s: %w", a.Name, err)
+		}
+		attributes[a.Name] = attribute
+	}
+	return attributes, nestedTypes, nil
+}
+
+func protoToNestedType(o *tfplugin6.Schema_Object) (*NestedType, error) {
+	attributes, nestedTypes, err := protoToAttributes(o.Attributes)
+	if err != nil {
+		return nil, err
+	}
+	nestedType := &NestedType{Attributes: attributes, NestedTypes: nestedTypes}
+	switch o.Nesting {
+	case tfplugin6.Schema_Object_SINGLE:
+		nestedType.Nesting = configschema.NestingSingle
+	case tfplugin6.Schema_Object_LIST:
+		nestedType.Nesting = configschema.NestingList
+	case tfplugin6.Schema_Object_SET:
+		nestedType.Nesting = configschema.NestingSet
+	case tfplugin6.Schema_Object_MAP:
+		nestedType.Nesting = configschema.NestingMap
+	default:
+		return nil, fmt.Errorf("invalid nesting mode %s", o.Nesting)
+	}
+	return nestedType, nil
+}
+
+func (t *NestedType) impliedType() cty.Type {
+	attributeTypes := map[string]cty.Type{}
+	for name, attribute := range t.Attributes {
+		attributeTypes[name] = attribute.Type
+	}
+	ty := cty.Object(attributeTypes)
+	switch t.Nesting {
+	case configschema.NestingList:
+		return cty.List(ty)
+	case configschema.NestingSet:
+		return cty.Set(ty)
+	case configschema.NestingMap:
+		return cty.Map(ty)
+	default:
+		return ty
+	}
+}
+
+func protoToNestedBlock(b *tfplugin6.Schema_NestedBlock) (*configschema.NestedBlock, error) {
+	nestedBlock := &configschema.NestedBlock{
+		MinItems: int(b.MinItems),
+		MaxItems: int(b.MaxItems),
+	}
+	switch b.Nesting {
+	case tfplugin6.Schema_NestedBlock_SINGLE:
+		nestedBlock.Nesting = configschema.NestingSingle
+	case tfplugin6.Schema_NestedBlock_GROUP:
+		nestedBlock.Nesting = configschema.NestingGroup
+	case tfplugin6.Schema_NestedBlock_LIST:
+		nestedBlock.Nesting = configschema.NestingList
+	case tfplugin6.Schema_NestedBlock_MAP:
+		nestedBlock.Nesting = configschema.NestingMap
+	case tfplugin6.Schema_NestedBlock_SET:
+		nestedBlock.Nesting = configschema.NestingSet
+	}
+	block, _, err := protoToConfigSchema(b.Block)
+	if err != nil {
+		return nil, err
+	}
+	nestedBlock.Block = *block
+	return nestedBlock, nil
+}
+
+func protoToDiagnostics(protoDiags []*tfplugin6.Diagnostic) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+	for _, d := range protoDiags {
+		severity := tfdiags.Error
+		if d.Severity == tfplugin6.Diagnostic_WARNING {
+			severity = tfdiags.Warning
+		}
+		if d.Attribute != nil {
+			diags = diags.Append(tfdiags.AttributeValue(severity, d.Summary, d.Detail, protoToPath(d.Attribute)))
+		} else {
+			diags = diags.Append(tfdiags.WholeContainingBody(severity, d.Summary, d.Detail))
+		}
+	}
+	return diags
+}
+
+func protoToPath(attributePath *tfplugin6.AttributePath) cty.Path {
+	var path cty.Path
+	for _, step := range attributePath.Steps {
+		switch selector := step.Selector.(type) {
+		case *tfplugin6.AttributePath_Step_AttributeName:
+			path = path.GetAttr(selector.AttributeName)
+		case *tfplugin6.AttributePath_Step_ElementKeyString:
+			path = path.Index(cty.StringVal(selector.ElementKeyString))
+		case *tfplugin6.AttributePath_Step_ElementKeyInt:
+			path = path.Index(cty.NumberIntVal(selector.ElementKeyInt))
+		}
+	}
+	return path
+}
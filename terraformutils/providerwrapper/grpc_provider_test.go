@@ -0,0 +1,164 @@
+// Copyright 2018 The Terraformer Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package providerwrapper //nolint
+
+import (
+	"context"
+	"net"
+	"regexp"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/terraformer/terraformutils/providerwrapper/tfplugin6"
+
+	"github.com/hashicorp/terraform/providers"
+	"github.com/zclconf/go-cty/cty"
+	"google.golang.org/grpc"
+)
+
+func TestNestedTypeSchema(t *testing.T) {
+	schema := &tfplugin6.Schema{Block: &tfplugin6.Schema_Block{
+		Attributes: []*tfplugin6.Schema_Attribute{
+			{Name: "id", Type: []byte(`"string"`), Computed: true},
+			{Name: "rules", Optional: true, NestedType: &tfplugin6.Schema_Object{
+				Nesting: tfplugin6.Schema_Object_LIST,
+				Attributes: []*tfplugin6.Schema_Attribute{
+					{Name: "port", Type: []byte(`"number"`), Required: true},
+					{Name: "created", Type: []byte(`"string"`), Computed: true},
+					{Name: "targets", Optional: true, NestedType: &tfplugin6.Schema_Object{
+						Nesting: tfplugin6.Schema_Object_MAP,
+						Attributes: []*tfplugin6.Schema_Attribute{
+							{Name: "address", Type: []byte(`"string"`), Required: true},
+							{Name: "arn", Type: []byte(`"string"`), Computed: true},
+						},
+					}},
+				},
+			}},
+		},
+	}}
+
+	resourceSchema, nestedTypes, err := protoToProviderSchema(schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedType := cty.List(cty.Object(map[string]cty.Type{
+		"port":    cty.Number,
+		"created": cty.String,
+		"targets": cty.Map(cty.Object(map[string]cty.Type{
+			"address": cty.String,
+			"arn":     cty.String,
+		})),
+	}))
+	if !resourceSchema.Block.Attributes["rules"].Type.Equals(expectedType) {
+		t.Fatalf("unexpected type %s", resourceSchema.Block.Attributes["rules"].Type.GoString())
+	}
+
+	p := &ProviderWrapper{
+		schema: &providers.GetSchemaResponse{
+			ResourceTypes: map[string]providers.Schema{"test_resource": resourceSchema},
+		},
+		nestedTypes: map[string]map[string]*NestedType{"test_resource": nestedTypes},
+	}
+	readOnlyAttributes, err := p.GetReadOnlyAttributes([]string{"test_resource"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ignored := []string{"id", "rules.0.created", "rules.1.targets.web.arn"}
+	kept := []string{"rules.0.port", "rules.1.targets.web.address"}
+	matches := func(key string) bool {
+		for _, pattern := range readOnlyAttributes["test_resource"] {
+			if regexp.MustCompile(pattern).MatchString(key) {
+				return true
+			}
+		}
+		return false
+	}
+	for _, key := range ignored {
+		if !matches(key) {
+			t.Errorf("%s expected to be read only in %v", key, readOnlyAttributes)
+		}
+	}
+	for _, key := range kept {
+		if matches(key) {
+			t.Errorf("%s not expected to be read only in %v", key, readOnlyAttributes)
+		}
+	}
+}
+
+// protocol5Server serves the tfplugin5 calls terraformer makes, counting them by method
+type protocol5Server struct {
+	calls map[string]int
+}
+
+func (s *protocol5Server) serviceDesc() *grpc.ServiceDesc {
+	handler := func(method string, in, out interface{}) grpc.MethodDesc {
+		return grpc.MethodDesc{
+			MethodName: method,
+			Handler: func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+				s.calls[method]++
+				return out, dec(in)
+			},
+		}
+	}
+	schema := &tfplugin6.Schema{Block: &tfplugin6.Schema_Block{
+		Attributes: []*tfplugin6.Schema_Attribute{{Name: "region", Type: []byte(`"string"`), Optional: true}},
+	}}
+	return &grpc.ServiceDesc{
+		ServiceName: "tfplugin5.Provider",
+		HandlerType: (*interface{})(nil),
+		Methods: []grpc.MethodDesc{
+			handler("GetSchema", new(tfplugin6.GetProviderSchema_Request), &tfplugin6.GetProviderSchema_Response{
+				Provider:        schema,
+				ResourceSchemas: map[string]*tfplugin6.Schema{"test_resource": schema},
+			}),
+			handler("Configure", new(tfplugin6.ConfigureProvider_Request), new(tfplugin6.ConfigureProvider_Response)),
+		},
+	}
+}
+
+func TestProtocol5Client(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := &protocol5Server{calls: map[string]int{}}
+	s := grpc.NewServer()
+	s.RegisterService(server.serviceDesc(), server)
+	go s.Serve(listener) //nolint
+	defer s.Stop()
+	conn, err := grpc.Dial(listener.Addr().String(), grpc.WithInsecure())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	provider := &GRPCProvider{client: &protocol5Client{cc: conn}, ctx: context.Background()}
+	schema := provider.GetSchema()
+	if schema.Diagnostics.HasErrors() {
+		t.Fatal(schema.Diagnostics.Err())
+	}
+	if schema.ResourceTypes["test_resource"].Block.Attributes["region"] == nil {
+		t.Fatalf("unexpected schema %v", schema.ResourceTypes)
+	}
+
+	cached := &GRPCProvider{client: &protocol5Client{cc: conn}, ctx: context.Background()}
+	cached.SetSchema(schema, nil)
+	config := cty.ObjectVal(map[string]cty.Value{"region": cty.StringVal("us-east-1")})
+	if resp := cached.Configure(providers.ConfigureRequest{Config: config}); resp.Diagnostics.HasErrors() {
+		t.Fatal(resp.Diagnostics.Err())
+	}
+	if server.calls["GetSchema"] != 1 || server.calls["Configure"] != 1 {
+		t.Errorf("unexpected calls %v", server.calls)
+	}
+}
@@ -0,0 +1,136 @@
+// Copyright 2018 The Terraformer Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package providerwrapper //nolint
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"sync/atomic"
+
+	"github.com/hashicorp/go-plugin"
+	"github.com/hashicorp/terraform/providers"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// maxPluginRestarts is the number of times the refresh of a resource is retried after its plugin died
+const maxPluginRestarts = 1
+
+// RestartHook receives each restart of a dead plugin process, with the resource refreshed when it died
+type RestartHook func(plugin int, resourceType, id string, err error)
+
+// pluginPool spreads requests over several plugin processes of the same provider
+type pluginPool struct {
+	slots     []*pluginSlot
+	counter   uint32
+	killed    int32
+	onRestart RestartHook
+}
+
+// pluginSlot holds the process currently serving one position of the pool, replaced on restart
+type pluginSlot struct {
+	sync.Mutex
+	index   int
+	process *pluginProcess
+}
+
+type pluginProcess struct {
+	client   *plugin.Client
+	provider providers.Interface
+}
+
+func newPluginPool(size int) *pluginPool {
+	pool := &pluginPool{}
+	for i := 0; i < size; i++ {
+		pool.slots = append(pool.slots, &pluginSlot{index: i})
+	}
+	return pool
+}
+
+// next returns the slots round-robin
+func (pool *pluginPool) next() *pluginSlot {
+	i := atomic.AddUint32(&pool.counter, 1) - 1
+	return pool.slots[int(i%uint32(len(pool.slots)))]
+}
+
+func (pool *pluginPool) kill() {
+	atomic.StoreInt32(&pool.killed, 1)
+	for _, slot := range pool.slots {
+		slot.Lock()
+		if slot.process != nil {
+			slot.process.client.Kill()
+		}
+		slot.Unlock()
+	}
+}
+
+func (pool *pluginPool) isKilled() bool {
+	return atomic.LoadInt32(&pool.killed) == 1
+}
+
+func (slot *pluginSlot) get() *pluginProcess {
+	slot.Lock()
+	defer slot.Unlock()
+	return slot.process
+}
+
+// isDead tells whether a request failed because the plugin process exited or its connection was lost.
+// Diagnostics are never looked at, the provider reports its own network errors with the same words.
+func (process *pluginProcess) isDead(err error) bool {
+	if err == nil {
+		return false
+	}
+	if process.client.Exited() {
+		return true
+	}
+	if transport, ok := process.provider.(interface{ ConnectionLost() bool }); ok {
+		return transport.ConnectionLost()
+	}
+	return false
+}
+
+// SetRestartHook sets the hook called each time a dead plugin process is restarted
+func (p *ProviderWrapper) SetRestartHook(hook RestartHook) {
+	p.plugins.onRestart = hook
+}
+
+// restart replaces the dead process of the slot with a new configured one,
+// unless another refresh already replaced it
+func (p *ProviderWrapper) restart(slot *pluginSlot, dead *pluginProcess, info *terraform.InstanceInfo, id string, cause error) error {
+	if p.plugins.isKilled() {
+		return fmt.Errorf("provider plugin stopped: %w", cause)
+	}
+	slot.Lock()
+	defer slot.Unlock()
+	if slot.process != dead {
+		return nil
+	}
+	log.Printf("WARN: %s plugin %d died refreshing %s %s, restarting it: %s", p.providerName, slot.index, info.Type, id, cause)
+	dead.client.Kill()
+	process, err := p.startPlugin(true)
+	if err != nil {
+		return fmt.Errorf("failed to restart %s plugin %d: %w", p.providerName, slot.index, err)
+	}
+	if p.plugins.isKilled() {
+		process.client.Kill()
+		return errors.New("provider plugin stopped")
+	}
+	slot.process = process
+	if p.plugins.onRestart != nil {
+		p.plugins.onRestart(slot.index, info.Type, id, cause)
+	}
+	return nil
+}
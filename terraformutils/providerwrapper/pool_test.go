@@ -0,0 +1,120 @@
+// Copyright 2018 The Terraformer Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package providerwrapper //nolint
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/terraformer/terraformutils/providerwrapper/tfplugin6"
+	"github.com/hashicorp/go-plugin"
+	"github.com/hashicorp/terraform/configs/configschema"
+	"github.com/hashicorp/terraform/providers"
+	"github.com/zclconf/go-cty/cty"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// configuringProvider answers Configure with a fixed error
+type configuringProvider struct {
+	providers.Interface
+	configureError string
+}
+
+func (p *configuringProvider) Configure(providers.ConfigureRequest) (resp providers.ConfigureResponse) {
+	if p.configureError != "" {
+		resp.Diagnostics = resp.Diagnostics.Append(errors.New(p.configureError))
+	}
+	return resp
+}
+
+// readClient answers ReadResource with a transport error or with diagnostics of the provider
+type readClient struct {
+	providerClient
+	err     error
+	summary string
+}
+
+func (c *readClient) ReadResource(ctx context.Context, in *tfplugin6.ReadResource_Request, opts ...grpc.CallOption) (*tfplugin6.ReadResource_Response, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	return &tfplugin6.ReadResource_Response{Diagnostics: []*tfplugin6.Diagnostic{
+		{Severity: tfplugin6.Diagnostic_ERROR, Summary: c.summary},
+	}}, nil
+}
+
+func TestPluginIsDead(t *testing.T) {
+	testCases := []struct {
+		client *readClient
+		dead   bool
+	}{
+		{&readClient{err: status.Error(codes.Unavailable, "transport is closing")}, true},
+		{&readClient{summary: "RequestError: dial tcp 10.0.0.1:443: connect: connection refused"}, false},
+		{&readClient{summary: "read: connection reset by peer"}, false},
+	}
+	schema := providers.GetSchemaResponse{Provider: providers.Schema{Block: &configschema.Block{}}, ResourceTypes: map[string]providers.Schema{
+		"test_resource": {Block: &configschema.Block{Attributes: map[string]*configschema.Attribute{
+			"id": {Type: cty.String, Computed: true},
+		}}},
+	}}
+	for _, testCase := range testCases {
+		provider := &GRPCProvider{client: testCase.client, ctx: context.Background()}
+		provider.SetSchema(schema, nil)
+		resp := provider.ReadResource(providers.ReadResourceRequest{
+			TypeName:   "test_resource",
+			PriorState: cty.ObjectVal(map[string]cty.Value{"id": cty.StringVal("test")}),
+		})
+		process := &pluginProcess{client: &plugin.Client{}, provider: provider}
+		if dead := process.isDead(resp.Diagnostics.Err()); dead != testCase.dead {
+			t.Errorf("%v: dead %t, expected %t", resp.Diagnostics.Err(), dead, testCase.dead)
+		}
+	}
+}
+
+func TestPluginPoolNext(t *testing.T) {
+	pool := newPluginPool(3)
+	indexes := []int{}
+	for i := 0; i < 6; i++ {
+		indexes = append(indexes, pool.next().index)
+	}
+	expected := []int{0, 1, 2, 0, 1, 2}
+	for i := range expected {
+		if indexes[i] != expected[i] {
+			t.Fatalf("expected slots %v, got %v", expected, indexes)
+		}
+	}
+}
+
+func TestConfigurePlugin(t *testing.T) {
+	p := &ProviderWrapper{
+		providerName: "test",
+		config:       cty.EmptyObjectVal,
+		schema:       &providers.GetSchemaResponse{Provider: providers.Schema{Block: &configschema.Block{}}},
+	}
+	if err := p.configurePlugin(&pluginProcess{client: &plugin.Client{}, provider: &configuringProvider{}}, true); err != nil {
+		t.Errorf("unexpected error %s", err)
+	}
+	if err := p.configurePlugin(&pluginProcess{client: &plugin.Client{}, provider: &configuringProvider{configureError: "deprecated argument"}}, false); err != nil {
+		t.Errorf("diagnostics of the first start expected to be ignored, got %s", err)
+	}
+	err := p.configurePlugin(&pluginProcess{client: &plugin.Client{}, provider: &configuringProvider{configureError: "invalid credentials"}}, true)
+	if err == nil || !strings.Contains(err.Error(), "invalid credentials") {
+		t.Errorf("a restarted plugin failing to configure expected to fail, got %v", err)
+	}
+}
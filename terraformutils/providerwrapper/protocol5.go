@@ -0,0 +1,69 @@
+// Copyright 2018 The Terraformer Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package providerwrapper //nolint
+
+import (
+	"context"
+
+	"github.com/GoogleCloudPlatform/terraformer/terraformutils/providerwrapper/tfplugin6"
+
+	"google.golang.org/grpc"
+)
+
+// protocol5Client calls the tfplugin5 service with tfplugin6 messages. The messages of the calls
+// terraformer makes have the same fields and numbers in both protocols, so they are the same on the wire.
+type protocol5Client struct {
+	cc grpc.ClientConnInterface
+}
+
+func (c *protocol5Client) GetProviderSchema(ctx context.Context, in *tfplugin6.GetProviderSchema_Request, opts ...grpc.CallOption) (*tfplugin6.GetProviderSchema_Response, error) {
+	out := new(tfplugin6.GetProviderSchema_Response)
+	if err := c.cc.Invoke(ctx, "/tfplugin5.Provider/GetSchema", in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *protocol5Client) ConfigureProvider(ctx context.Context, in *tfplugin6.ConfigureProvider_Request, opts ...grpc.CallOption) (*tfplugin6.ConfigureProvider_Response, error) {
+	out := new(tfplugin6.ConfigureProvider_Response)
+	if err := c.cc.Invoke(ctx, "/tfplugin5.Provider/Configure", in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *protocol5Client) ReadResource(ctx context.Context, in *tfplugin6.ReadResource_Request, opts ...grpc.CallOption) (*tfplugin6.ReadResource_Response, error) {
+	out := new(tfplugin6.ReadResource_Response)
+	if err := c.cc.Invoke(ctx, "/tfplugin5.Provider/ReadResource", in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *protocol5Client) ImportResourceState(ctx context.Context, in *tfplugin6.ImportResourceState_Request, opts ...grpc.CallOption) (*tfplugin6.ImportResourceState_Response, error) {
+	out := new(tfplugin6.ImportResourceState_Response)
+	if err := c.cc.Invoke(ctx, "/tfplugin5.Provider/ImportResourceState", in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *protocol5Client) StopProvider(ctx context.Context, in *tfplugin6.StopProvider_Request, opts ...grpc.CallOption) (*tfplugin6.StopProvider_Response, error) {
+	out := new(tfplugin6.StopProvider_Response)
+	if err := c.cc.Invoke(ctx, "/tfplugin5.Provider/Stop", in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
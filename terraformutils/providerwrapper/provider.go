@@ -15,15 +15,14 @@
 package providerwrapper //nolint
 
 import (
+	"context"
 	"errors"
 	"fmt"
-	"io/ioutil"
 	"log"
 	"os"
 	"os/exec"
 	"runtime"
-	"strings"
-	"time"
+	"sync"
 
 	"github.com/GoogleCloudPlatform/terraformer/terraformutils/terraformerstring"
 
@@ -49,21 +48,28 @@ const DefaultPluginVendorDirV12 = "terraform.d/plugins/" + pluginMachineName
 // pluginMachineName is the directory name used in new plugin paths.
 const pluginMachineName = runtime.GOOS + "_" + runtime.GOARCH
 
+// DefaultProviderRegistryHost is the registry terraform resolves provider source addresses from.
+const DefaultProviderRegistryHost = "registry.terraform.io"
+
 type ProviderWrapper struct {
-	Provider     *tfplugin.GRPCProvider
-	client       *plugin.Client
-	rpcClient    plugin.ClientProtocol
+	plugins      *pluginPool
 	providerName string
 	config       cty.Value
+	verbose      bool
 	schema       *providers.GetSchemaResponse
+	nestedTypes  map[string]map[string]*NestedType
 	retryCount   int
 	retrySleepMs int
+	rateLimiter  *RateLimiter
+	deniedTypes  sync.Map
 }
 
 func NewProviderWrapper(providerName string, providerConfig cty.Value, verbose bool, options ...map[string]int) (*ProviderWrapper, error) {
 	p := &ProviderWrapper{retryCount: 5, retrySleepMs: 300}
 	p.providerName = providerName
 	p.config = providerConfig
+	p.verbose = verbose
+	pluginProcesses := 1
 
 	if len(options) > 0 {
 		retryCount, hasOption := options[0]["retryCount"]
@@ -74,20 +80,43 @@ func NewProviderWrapper(providerName string, providerConfig cty.Value, verbose b
 		if hasOption {
 			p.retrySleepMs = retrySleepMs
 		}
+		processes, hasOption := options[0]["pluginProcesses"]
+		if hasOption && processes > 0 {
+			pluginProcesses = processes
+		}
 	}
 
-	err := p.initProvider(verbose)
+	p.plugins = newPluginPool(pluginProcesses)
+	err := p.initProvider()
 
 	return p, err
 }
 
+// NewSchemaProviderWrapper returns a wrapper answering schema queries only, for runs that read no resources.
+// The schema comes from the cache or from a plugin process stopped right after, never configured.
+func NewSchemaProviderWrapper(providerName string, verbose bool) (*ProviderWrapper, error) {
+	schema, err := GetProviderSchema(providerName, verbose)
+	if err != nil {
+		return nil, err
+	}
+	r := schema.response()
+	return &ProviderWrapper{
+		plugins:      newPluginPool(0),
+		providerName: providerName,
+		verbose:      verbose,
+		schema:       &r,
+		nestedTypes:  schema.NestedTypes,
+	}, nil
+}
+
+// Kill stops all plugin processes, crashed plugins are not restarted after
 func (p *ProviderWrapper) Kill() {
-	p.client.Kill()
+	p.plugins.kill()
 }
 
 func (p *ProviderWrapper) GetSchema() *providers.GetSchemaResponse {
 	if p.schema == nil {
-		r := p.Provider.GetSchema()
+		r := p.plugins.slots[0].get().provider.GetSchema()
 		p.schema = &r
 	}
 	return p.schema
@@ -113,6 +142,11 @@ func (p *ProviderWrapper) GetReadOnlyAttributes(resourceTypes []string) (map[str
 				}
 			}
 			readOnlyAttributes[resourceName] = p.readObjBlocks(obj.Block.BlockTypes, readOnlyAttributes[resourceName], "-1")
+			for k, nestedType := range p.nestedTypes[resourceName] {
+				if attribute, exist := obj.Block.Attributes[k]; exist && (attribute.Optional || attribute.Required) {
+					readOnlyAttributes[resourceName] = p.readNestedType(nestedType, readOnlyAttributes[resourceName], "^"+k)
+				}
+			}
 		}
 	}
 	return readOnlyAttributes, nil
@@ -158,200 +192,232 @@ func (p *ProviderWrapper) readObjBlocks(block map[string]*configschema.NestedBlo
 	return readOnlyAttributes
 }
 
-func (p *ProviderWrapper) Refresh(info *terraform.InstanceInfo, state *terraform.InstanceState) (*terraform.InstanceState, error) {
+// readNestedType lists the computed attributes nested in an attribute of a protocol 6 schema
+func (p *ProviderWrapper) readNestedType(nestedType *NestedType, readOnlyAttributes []string, prefix string) []string {
+	switch nestedType.Nesting {
+	case configschema.NestingList, configschema.NestingSet:
+		prefix += ".[0-9]+."
+	case configschema.NestingMap:
+		prefix += ".[^.]+."
+	default:
+		prefix += "."
+	}
+	for key, attribute := range nestedType.Attributes {
+		if !attribute.Optional && !attribute.Required {
+			readOnlyAttributes = append(readOnlyAttributes, prefix+key+"($|\\.(.*))")
+		} else if nested, exist := nestedType.NestedTypes[key]; exist {
+			readOnlyAttributes = p.readNestedType(nested, readOnlyAttributes, prefix+key)
+		}
+	}
+	return readOnlyAttributes
+}
+
+// SetRateLimiter paces the requests sent to the provider, nil disables rate limiting
+func (p *ProviderWrapper) SetRateLimiter(rateLimiter *RateLimiter) {
+	p.rateLimiter = rateLimiter
+}
+
+// Refresh reads the current state of a resource, failed reads are retried depending on their ErrorClass.
+// When the plugin process dies during the refresh, it is restarted and the refresh retried.
+func (p *ProviderWrapper) Refresh(ctx context.Context, info *terraform.InstanceInfo, state *terraform.InstanceState) (*terraform.InstanceState, error) {
+	slot := p.plugins.next()
+	for restarts := 0; ; restarts++ {
+		process := slot.get()
+		newState, err := p.refresh(ctx, process, info, state)
+		if err == nil || !process.isDead(err) || ctx.Err() != nil {
+			return newState, err
+		}
+		if restarts >= maxPluginRestarts {
+			return nil, err
+		}
+		if restartErr := p.restart(slot, process, info, state.ID, err); restartErr != nil {
+			return nil, restartErr
+		}
+	}
+}
+
+func (p *ProviderWrapper) refresh(ctx context.Context, process *pluginProcess, info *terraform.InstanceInfo, state *terraform.InstanceState) (*terraform.InstanceState, error) {
 	schema := p.GetSchema()
 	impliedType := schema.ResourceTypes[info.Type].Block.ImpliedType()
 	priorState, err := state.AttrsAsObjectValue(impliedType)
 	if err != nil {
 		return nil, err
 	}
-	successReadResource := false
 	resp := providers.ReadResourceResponse{}
+	class := ErrorTransient
 	for i := 0; i < p.retryCount; i++ {
-		resp = p.Provider.ReadResource(providers.ReadResourceRequest{
+		if err := p.rateLimiter.Wait(ctx, info.Type); err != nil {
+			return nil, err
+		}
+		resp = process.provider.ReadResource(providers.ReadResourceRequest{
 			TypeName:   info.Type,
 			PriorState: priorState,
 			Private:    []byte{},
 		})
-		if resp.Diagnostics.HasErrors() {
-			log.Printf("WARN: Fail read resource from provider, wait %dms before retry\n", p.retrySleepMs)
-			time.Sleep(time.Duration(p.retrySleepMs) * time.Millisecond)
-			continue
-		} else {
-			successReadResource = true
+		p.rateLimiter.Done(info.Type, resp.Diagnostics)
+		if !resp.Diagnostics.HasErrors() {
 			break
 		}
+		if process.isDead(resp.Diagnostics.Err()) {
+			return nil, resp.Diagnostics.Err()
+		}
+		class = ClassifyDiagnostics(resp.Diagnostics)
+		if (class != ErrorTransient && class != ErrorThrottled) || i == p.retryCount-1 {
+			break
+		}
+		delay := p.retryDelay(class, i)
+		log.Printf("WARN: Fail read resource from provider (%s), wait %s before retry\n", class, delay)
+		if err := sleep(ctx, delay); err != nil {
+			return nil, err
+		}
 	}
 
-	if !successReadResource {
-		log.Println("Fail read resource from provider, trying import command")
+	if resp.Diagnostics.HasErrors() {
+		switch class {
+		case ErrorNotFound, ErrorThrottled, ErrorPermissionDenied:
+			return nil, p.refreshError(info.Type, class, resp.Diagnostics.Err())
+		}
+		log.Printf("Fail read resource from provider (%s), trying import command", class)
 		// retry with regular import command - without resource attributes
-		importResponse := p.Provider.ImportResourceState(providers.ImportResourceStateRequest{
+		if err := p.rateLimiter.Wait(ctx, info.Type); err != nil {
+			return nil, err
+		}
+		importResponse := process.provider.ImportResourceState(providers.ImportResourceStateRequest{
 			TypeName: info.Type,
 			ID:       state.ID,
 		})
+		p.rateLimiter.Done(info.Type, importResponse.Diagnostics)
 		if importResponse.Diagnostics.HasErrors() {
-			return nil, resp.Diagnostics.Err()
+			if process.isDead(importResponse.Diagnostics.Err()) {
+				return nil, importResponse.Diagnostics.Err()
+			}
+			return nil, p.refreshError(info.Type, ClassifyDiagnostics(importResponse.Diagnostics), importResponse.Diagnostics.Err())
 		}
 		if len(importResponse.ImportedResources) == 0 {
-			return nil, errors.New("not able to import resource for a given ID")
+			return nil, p.refreshError(info.Type, ErrorNotFound, errors.New("not able to import resource for a given ID"))
 		}
 		return terraform.NewInstanceStateShimmedFromValue(importResponse.ImportedResources[0].State, int(schema.ResourceTypes[info.Type].Version)), nil
 	}
 
 	if resp.NewState.IsNull() {
-		msg := fmt.Sprintf("ERROR: Read resource response is null for resource %s", info.Id)
-		return nil, errors.New(msg)
+		// providers return a null state for resources deleted since they were listed
+		return nil, p.refreshError(info.Type, ErrorNotFound, fmt.Errorf("read resource response is null for resource %s", info.Id))
 	}
 
 	return terraform.NewInstanceStateShimmedFromValue(resp.NewState, int(schema.ResourceTypes[info.Type].Version)), nil
 }
 
-func (p *ProviderWrapper) initProvider(verbose bool) error {
-	providerFilePath, err := getProviderFileName(p.providerName)
-	if err != nil {
-		return err
-	}
-	options := hclog.LoggerOptions{
-		Name:   "plugin",
-		Level:  hclog.Error,
-		Output: os.Stdout,
-	}
-	if verbose {
-		options.Level = hclog.Trace
+func (p *ProviderWrapper) initProvider() error {
+	for i := range p.plugins.slots {
+		process, err := p.startPlugin(false)
+		if err != nil {
+			p.plugins.kill()
+			return err
+		}
+		p.plugins.slots[i].process = process
 	}
-	logger := hclog.New(&options)
-	p.client = plugin.NewClient(
-		&plugin.ClientConfig{
-			Cmd:              exec.Command(providerFilePath),
-			HandshakeConfig:  tfplugin.Handshake,
-			VersionedPlugins: tfplugin.VersionedPlugins,
-			Managed:          true,
-			Logger:           logger,
-			AllowedProtocols: []plugin.Protocol{plugin.ProtocolGRPC},
-			AutoMTLS:         true,
-		})
-	p.rpcClient, err = p.client.Client()
+	return nil
+}
+
+// startPlugin launches a plugin process and configures it, see configurePlugin for restarted ones
+func (p *ProviderWrapper) startPlugin(restarted bool) (*pluginProcess, error) {
+	binary, err := FindProvider(p.providerName)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	raw, err := p.rpcClient.Dispense(tfplugin.ProviderPluginName)
+	process, err := launchPlugin(binary, p.verbose)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	if p.schema == nil {
+		if schema := loadSchema(p.providerName, binary); schema != nil {
+			r := schema.response()
+			p.schema = &r
+			p.nestedTypes = schema.NestedTypes
+		}
+	}
+	grpcProvider, isGRPCProvider := process.provider.(*GRPCProvider)
+	if p.schema == nil {
+		r := process.provider.GetSchema()
+		p.schema = &r
+		if isGRPCProvider {
+			p.nestedTypes = grpcProvider.NestedTypes()
+		}
+		if !r.Diagnostics.HasErrors() {
+			saveSchema(p.providerName, binary, newProviderSchema(r, p.nestedTypes))
+		}
+	} else if isGRPCProvider && !p.schema.Diagnostics.HasErrors() {
+		grpcProvider.SetSchema(*p.schema, p.nestedTypes)
 	}
 
-	p.Provider = raw.(*tfplugin.GRPCProvider)
+	if err := p.configurePlugin(process, restarted); err != nil {
+		return nil, err
+	}
+	return process, nil
+}
 
+// configurePlugin configures a started plugin process. Diagnostics of the first start are only logged, some
+// providers reporting warnings as errors. A restarted plugin rejecting the configuration that worked before
+// cannot serve the retried refresh, it is killed and an error returned.
+func (p *ProviderWrapper) configurePlugin(process *pluginProcess, restarted bool) error {
 	config, err := p.GetSchema().Provider.Block.CoerceValue(p.config)
 	if err != nil {
+		process.client.Kill()
 		return err
 	}
-	p.Provider.Configure(providers.ConfigureRequest{
+	resp := process.provider.Configure(providers.ConfigureRequest{
 		TerraformVersion: version.Version,
 		Config:           config,
 	})
-
-	return nil
-}
-
-func getProviderFileName(providerName string) (string, error) {
-	defaultDataDir := os.Getenv("TF_DATA_DIR")
-	if defaultDataDir == "" {
-		defaultDataDir = DefaultDataDir
+	if !resp.Diagnostics.HasErrors() {
+		return nil
 	}
-	providerFilePath, err := getProviderFileNameV13andV14(defaultDataDir, providerName)
-	if err != nil || providerFilePath == "" {
-		providerFilePath, err = getProviderFileNameV13andV14(os.Getenv("HOME")+string(os.PathSeparator)+
-			".terraform.d", providerName)
+	if !restarted {
+		log.Printf("WARN: configuring %s provider: %s", p.providerName, resp.Diagnostics.Err())
+		return nil
 	}
-	if err != nil || providerFilePath == "" {
-		return getProviderFileNameV12(providerName)
-	}
-	return providerFilePath, nil
+	process.client.Kill()
+	return fmt.Errorf("failed to configure %s provider: %w", p.providerName, resp.Diagnostics.Err())
 }
 
-func getProviderFileNameV13andV14(prefix, providerName string) (string, error) {
-	// Read terraform v14 file path
-	registryDir := prefix + string(os.PathSeparator) + "providers" + string(os.PathSeparator) +
-		"registry.terraform.io"
-	providerDirs, err := ioutil.ReadDir(registryDir)
-	if err != nil {
-		// Read terraform v13 file path
-		registryDir = prefix + string(os.PathSeparator) + "plugins" + string(os.PathSeparator) +
-			"registry.terraform.io"
-		providerDirs, err = ioutil.ReadDir(registryDir)
-		if err != nil {
-			return "", err
-		}
-	}
-	providerFilePath := ""
-	for _, providerDir := range providerDirs {
-		pluginPath := registryDir + string(os.PathSeparator) + providerDir.Name() +
-			string(os.PathSeparator) + providerName
-		dirs, err := ioutil.ReadDir(pluginPath)
-		if err != nil {
-			continue
-		}
-		for _, dir := range dirs {
-			if !dir.IsDir() {
-				continue
-			}
-			for _, dir := range dirs {
-				fullPluginPath := pluginPath + string(os.PathSeparator) + dir.Name() +
-					string(os.PathSeparator) + runtime.GOOS + "_" + runtime.GOARCH
-				files, err := ioutil.ReadDir(fullPluginPath)
-				if err == nil {
-					for _, file := range files {
-						if strings.HasPrefix(file.Name(), "terraform-provider-"+providerName) {
-							providerFilePath = fullPluginPath + string(os.PathSeparator) + file.Name()
-						}
-					}
-				}
-			}
-		}
+// launchPlugin starts a process of the provider binary, not configured yet
+func launchPlugin(binary *ProviderBinary, verbose bool) (*pluginProcess, error) {
+	options := hclog.LoggerOptions{
+		Name:   "plugin",
+		Level:  hclog.Error,
+		Output: os.Stdout,
 	}
-	return providerFilePath, nil
-}
-
-func getProviderFileNameV12(providerName string) (string, error) {
-	defaultDataDir := os.Getenv("TF_DATA_DIR")
-	if defaultDataDir == "" {
-		defaultDataDir = DefaultDataDir
+	if verbose {
+		options.Level = hclog.Trace
 	}
-	pluginPath := defaultDataDir + string(os.PathSeparator) + "plugins" + string(os.PathSeparator) + runtime.GOOS + "_" + runtime.GOARCH
-	files, err := ioutil.ReadDir(pluginPath)
+	logger := hclog.New(&options)
+	process := &pluginProcess{}
+	process.client = plugin.NewClient(
+		&plugin.ClientConfig{
+			Cmd:              exec.Command(binary.Path),
+			HandshakeConfig:  tfplugin.Handshake,
+			VersionedPlugins: versionedPlugins,
+			Managed:          true,
+			Logger:           logger,
+			AllowedProtocols: []plugin.Protocol{plugin.ProtocolGRPC},
+			AutoMTLS:         true,
+		})
+	rpcClient, err := process.client.Client()
 	if err != nil {
-		pluginPath = os.Getenv("HOME") + string(os.PathSeparator) + "." + DefaultPluginVendorDirV12
-		files, err = ioutil.ReadDir(pluginPath)
-		if err != nil {
-			return "", err
-		}
-	}
-	providerFilePath := ""
-	for _, file := range files {
-		if file.IsDir() {
-			continue
-		}
-		if strings.HasPrefix(file.Name(), "terraform-provider-"+providerName) {
-			providerFilePath = pluginPath + string(os.PathSeparator) + file.Name()
-		}
+		process.client.Kill()
+		return nil, err
 	}
-	return providerFilePath, nil
-}
-
-func GetProviderVersion(providerName string) string {
-	providerFilePath, err := getProviderFileName(providerName)
+	raw, err := rpcClient.Dispense(tfplugin.ProviderPluginName)
 	if err != nil {
-		log.Println("Can't find provider file path. Ensure that you are following https://www.terraform.io/docs/configuration/providers.html#third-party-plugins.")
-		return ""
+		process.client.Kill()
+		return nil, err
 	}
-	t := strings.Split(providerFilePath, string(os.PathSeparator))
-	providerFileName := t[len(t)-1]
-	providerFileNameParts := strings.Split(providerFileName, "_")
-	if len(providerFileNameParts) < 2 {
-		log.Println("Can't find provider version. Ensure that you are following https://www.terraform.io/docs/configuration/providers.html#plugin-names-and-versions.")
-		return ""
+
+	provider, ok := raw.(providers.Interface)
+	if !ok {
+		process.client.Kill()
+		return nil, fmt.Errorf("unexpected %s plugin client %T", binary.Path, raw)
 	}
-	providerVersion := providerFileNameParts[1]
-	return "~> " + strings.TrimPrefix(providerVersion, "v")
+	process.provider = provider
+	return process, nil
 }
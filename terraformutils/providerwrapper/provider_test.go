@@ -1,7 +1,11 @@
 package providerwrapper //nolint
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"regexp"
+	"runtime"
 	"testing"
 
 	"github.com/hashicorp/terraform/configs/configschema"
@@ -82,3 +86,41 @@ func isAttributeIgnored(name string, patterns []string) bool {
 	}
 	return ignored
 }
+
+func TestProviderSource(t *testing.T) {
+	dataDir, err := ioutil.TempDir("", "terraformer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dataDir)
+	pluginDir := filepath.Join(dataDir, "providers", DefaultProviderRegistryHost, "mrparkers", "keycloak", "2.0.0", runtime.GOOS+"_"+runtime.GOARCH)
+	if err := os.MkdirAll(pluginDir, os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(pluginDir, "terraform-provider-keycloak_v2.0.0"), []byte{}, os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	privateDir := filepath.Join(dataDir, "providers", "registry.example.com", "acme", "widget", "0.1.0", runtime.GOOS+"_"+runtime.GOARCH)
+	if err := os.MkdirAll(privateDir, os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(privateDir, "terraform-provider-widget_v0.1.0"), []byte{}, os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	if previous, ok := os.LookupEnv("TF_DATA_DIR"); ok {
+		defer os.Setenv("TF_DATA_DIR", previous)
+	} else {
+		defer os.Unsetenv("TF_DATA_DIR")
+	}
+	os.Setenv("TF_DATA_DIR", dataDir)
+
+	if source := GetProviderSource("widget"); source != "registry.example.com/acme/widget" {
+		t.Errorf("unexpected source %s", source)
+	}
+	if source := GetProviderSource("keycloak"); source != "registry.terraform.io/mrparkers/keycloak" {
+		t.Errorf("unexpected source %s", source)
+	}
+	if source := GetProviderSource("nonexistent"); source != "registry.terraform.io/hashicorp/nonexistent" {
+		t.Errorf("unexpected source %s", source)
+	}
+}
@@ -0,0 +1,186 @@
+// Copyright 2018 The Terraformer Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package providerwrapper //nolint
+
+import (
+	"context"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform/tfdiags"
+)
+
+const (
+	// throttledRate is the rate in requests per second an unlimited pacer falls back to when throttled
+	throttledRate = 10.0
+	// minRate is the lowest rate multiplicative decrease goes down to
+	minRate = 0.2
+	// rateIncrease is added to the rate after each successful request
+	rateIncrease = 0.1
+)
+
+var throttlingMessages = []string{
+	"throttl",
+	"rate exceeded",
+	"rate limit",
+	"too many requests",
+	"requestlimitexceeded",
+	"slowdown",
+	"status code: 429",
+	"error 429",
+}
+
+// RateLimiter paces provider requests, for all of them and per resource type.
+// Rates adapt with AIMD: halved when the provider reports throttling, increased slowly after successes,
+// never above the configured rate. A rate of 0 means unlimited until the first throttling.
+type RateLimiter struct {
+	global *pacer
+	types  map[string]*pacer
+}
+
+func NewRateLimiter(rate float64, resourceTypeRates map[string]float64) *RateLimiter {
+	l := &RateLimiter{global: newPacer(rate), types: map[string]*pacer{}}
+	for resourceType, typeRate := range resourceTypeRates {
+		l.types[resourceType] = newPacer(typeRate)
+	}
+	return l
+}
+
+// Wait blocks until a request for the resource type is allowed or the context is done
+func (l *RateLimiter) Wait(ctx context.Context, resourceType string) error {
+	if l == nil {
+		return ctx.Err()
+	}
+	delay := l.global.reserve()
+	if typePacer, exist := l.types[resourceType]; exist {
+		if typeDelay := typePacer.reserve(); typeDelay > delay {
+			delay = typeDelay
+		}
+	}
+	return sleep(ctx, delay)
+}
+
+// Done adapts rates to the result of a request
+func (l *RateLimiter) Done(resourceType string, diags tfdiags.Diagnostics) {
+	if l == nil {
+		return
+	}
+	throttled := IsThrottling(diags)
+	if throttled {
+		log.Printf("WARN: %s requests throttled, slowing down", resourceType)
+	}
+	for _, p := range []*pacer{l.global, l.types[resourceType]} {
+		if p == nil {
+			continue
+		}
+		if throttled {
+			p.decrease()
+		} else if !diags.HasErrors() {
+			p.increase()
+		}
+	}
+}
+
+// Rate returns the current global rate in requests per second
+func (l *RateLimiter) Rate() float64 {
+	if l == nil {
+		return 0
+	}
+	l.global.Lock()
+	defer l.global.Unlock()
+	return l.global.rate
+}
+
+// IsThrottling reports whether diagnostics contain an API throttling error
+func IsThrottling(diags tfdiags.Diagnostics) bool {
+	for _, diag := range diags {
+		if diag.Severity() != tfdiags.Error {
+			continue
+		}
+		description := diag.Description()
+		message := strings.ToLower(description.Summary + " " + description.Detail)
+		for _, throttlingMessage := range throttlingMessages {
+			if strings.Contains(message, throttlingMessage) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// sleep waits for the duration unless the context is done first
+func sleep(ctx context.Context, duration time.Duration) error {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+type pacer struct {
+	sync.Mutex
+	rate    float64
+	maxRate float64
+	next    time.Time
+}
+
+func newPacer(rate float64) *pacer {
+	return &pacer{rate: rate, maxRate: rate}
+}
+
+func (p *pacer) reserve() time.Duration {
+	p.Lock()
+	defer p.Unlock()
+	if p.rate == 0 {
+		return 0
+	}
+	now := time.Now()
+	if p.next.Before(now) {
+		p.next = now
+	}
+	delay := p.next.Sub(now)
+	p.next = p.next.Add(time.Duration(float64(time.Second) / p.rate))
+	return delay
+}
+
+func (p *pacer) decrease() {
+	p.Lock()
+	defer p.Unlock()
+	if p.rate == 0 {
+		p.rate = throttledRate
+		return
+	}
+	p.rate /= 2
+	if p.rate < minRate {
+		p.rate = minRate
+	}
+}
+
+func (p *pacer) increase() {
+	p.Lock()
+	defer p.Unlock()
+	if p.rate == 0 {
+		return
+	}
+	p.rate += rateIncrease
+	if p.maxRate != 0 && p.rate > p.maxRate {
+		p.rate = p.maxRate
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright 2018 The Terraformer Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package providerwrapper //nolint
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform/tfdiags"
+)
+
+func TestRateLimiterAIMD(t *testing.T) {
+	var throttled, failed tfdiags.Diagnostics
+	throttled = throttled.Append(errors.New("ThrottlingException: Rate exceeded"))
+	failed = failed.Append(errors.New("resource not found"))
+
+	limiter := NewRateLimiter(4, nil)
+	limiter.Done("type1", throttled)
+	if limiter.Rate() != 2 {
+		t.Errorf("expected multiplicative decrease to 2, got %f", limiter.Rate())
+	}
+	limiter.Done("type1", failed)
+	if limiter.Rate() != 2 {
+		t.Errorf("expected other errors to keep the rate, got %f", limiter.Rate())
+	}
+	for i := 0; i < 100; i++ {
+		limiter.Done("type1", nil)
+	}
+	if limiter.Rate() != 4 {
+		t.Errorf("expected additive increase up to the configured rate, got %f", limiter.Rate())
+	}
+
+	unlimited := NewRateLimiter(0, nil)
+	unlimited.Done("type1", nil)
+	if unlimited.Rate() != 0 {
+		t.Errorf("expected unlimited rate, got %f", unlimited.Rate())
+	}
+	unlimited.Done("type1", throttled)
+	if unlimited.Rate() != throttledRate {
+		t.Errorf("expected throttled rate, got %f", unlimited.Rate())
+	}
+}
+
+func TestRateLimiterResourceTypes(t *testing.T) {
+	limiter := NewRateLimiter(0, map[string]float64{"slow": 20})
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		_ = limiter.Wait(context.Background(), "fast")
+	}
+	if time.Since(start) > 40*time.Millisecond {
+		t.Errorf("unlimited type was paced")
+	}
+	start = time.Now()
+	for i := 0; i < 3; i++ {
+		_ = limiter.Wait(context.Background(), "slow")
+	}
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("limited type was not paced, took %s", elapsed)
+	}
+}
+
+func TestRateLimiterCancel(t *testing.T) {
+	limiter := NewRateLimiter(0.5, nil)
+	_ = limiter.Wait(context.Background(), "type1")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := limiter.Wait(ctx, "type1"); err != context.Canceled {
+		t.Errorf("expected cancellation, got %v", err)
+	}
+}
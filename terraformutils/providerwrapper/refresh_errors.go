@@ -0,0 +1,179 @@
+// Copyright 2018 The Terraformer Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package providerwrapper //nolint
+
+import (
+	"errors"
+	"log"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform/tfdiags"
+)
+
+// ErrorClass tells how a failed refresh is handled
+type ErrorClass string
+
+const (
+	// ErrorNotFound is a resource deleted since it was listed, it is dropped without retry
+	ErrorNotFound ErrorClass = "not_found"
+	// ErrorThrottled is retried with exponential backoff
+	ErrorThrottled ErrorClass = "throttled"
+	// ErrorPermissionDenied is not retried and reported once per resource type
+	ErrorPermissionDenied ErrorClass = "permission_denied"
+	// ErrorTransient is retried, then the resource is imported by ID. Unclassified errors are transient.
+	ErrorTransient ErrorClass = "transient"
+	// ErrorProviderBug is not retried, the resource is imported by ID instead
+	ErrorProviderBug ErrorClass = "provider_bug"
+)
+
+// maxBackoff caps the delay between retries of throttled requests
+const maxBackoff = 30 * time.Second
+
+// networkMessages are checked first, a host or endpoint that can't be reached says nothing about the resource
+var networkMessages = []string{
+	"no such host",
+	"connection refused",
+	"connection reset",
+	"network is unreachable",
+	"i/o timeout",
+	"tls handshake timeout",
+}
+
+// notFoundMessages are error codes of deleted resources. Looser words such as "not found" also describe
+// missing credentials, profiles or regions, such resources would be dropped without being reported.
+// A null state read by the provider is not found too.
+var notFoundMessages = []string{
+	"nosuchentity",
+	"nosuchbucket",
+	"nosuchkey",
+	"resourcenotfoundexception",
+	"notfoundexception",
+	".notfound:",
+	"resourcenotfound",
+	"resourcegroupnotfound",
+	"status code: 404",
+	"statuscode: 404",
+	"error 404",
+	"404 not found",
+}
+
+var permissionDeniedMessages = []string{
+	"accessdenied",
+	"access denied",
+	"permission denied",
+	"permission_denied",
+	"unauthorized",
+	"not authorized",
+	"authorizationfailed",
+	"forbidden",
+	"status code: 403",
+	"error 403",
+}
+
+var providerBugMessages = []string{
+	"panic",
+	"provider produced",
+	"invalid memory address",
+	"nil pointer",
+	"msgpack",
+	"unsupported attribute",
+}
+
+// RefreshError is a failed refresh with the class of its error
+type RefreshError struct {
+	Class ErrorClass
+	Err   error
+}
+
+func (e *RefreshError) Error() string {
+	return string(e.Class) + ": " + e.Err.Error()
+}
+
+func (e *RefreshError) Unwrap() error {
+	return e.Err
+}
+
+// ErrorClassOf returns the class of a refresh error, empty for other errors
+func ErrorClassOf(err error) ErrorClass {
+	var refreshErr *RefreshError
+	if errors.As(err, &refreshErr) {
+		return refreshErr.Class
+	}
+	return ""
+}
+
+// ClassifyDiagnostics returns the class of the errors of a provider response
+func ClassifyDiagnostics(diags tfdiags.Diagnostics) ErrorClass {
+	if IsThrottling(diags) {
+		return ErrorThrottled
+	}
+	messages := []string{}
+	for _, diag := range diags {
+		if diag.Severity() != tfdiags.Error {
+			continue
+		}
+		description := diag.Description()
+		messages = append(messages, strings.ToLower(description.Summary+" "+description.Detail))
+	}
+	message := strings.Join(messages, "\n")
+	switch {
+	case containsAny(message, networkMessages):
+		return ErrorTransient
+	case containsAny(message, permissionDeniedMessages):
+		return ErrorPermissionDenied
+	case containsAny(message, notFoundMessages):
+		return ErrorNotFound
+	case containsAny(message, providerBugMessages):
+		return ErrorProviderBug
+	}
+	return ErrorTransient
+}
+
+func containsAny(message string, substrings []string) bool {
+	for _, substring := range substrings {
+		if strings.Contains(message, substring) {
+			return true
+		}
+	}
+	return false
+}
+
+// retryDelay is fixed for transient errors, exponential with jitter for throttling
+func (p *ProviderWrapper) retryDelay(class ErrorClass, attempt int) time.Duration {
+	delay := time.Duration(p.retrySleepMs) * time.Millisecond
+	if class != ErrorThrottled {
+		return delay
+	}
+	for i := 0; i < attempt && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > maxBackoff {
+		delay = maxBackoff
+	}
+	// half fixed, half random, so workers throttled together don't retry together
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
+
+// refreshError classifies a refresh failure, permission errors are logged once per resource type
+func (p *ProviderWrapper) refreshError(resourceType string, class ErrorClass, err error) error {
+	if class == ErrorPermissionDenied {
+		if _, reported := p.deniedTypes.LoadOrStore(resourceType, true); !reported {
+			log.Printf("WARN: permission denied refreshing %s resources, skipping them: %s", resourceType, err)
+		}
+	}
+	return &RefreshError{Class: class, Err: err}
+}
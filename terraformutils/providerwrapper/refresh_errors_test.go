@@ -0,0 +1,138 @@
+// Copyright 2018 The Terraformer Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package providerwrapper //nolint
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-plugin"
+	"github.com/hashicorp/terraform/configs/configschema"
+	"github.com/hashicorp/terraform/providers"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/hashicorp/terraform/tfdiags"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// failingProvider answers reads and imports with fixed errors, counting the calls
+type failingProvider struct {
+	providers.Interface
+	readError   string
+	importError string
+	reads       int
+	imports     int
+}
+
+func (p *failingProvider) ReadResource(r providers.ReadResourceRequest) (resp providers.ReadResourceResponse) {
+	p.reads++
+	if p.readError != "" {
+		resp.Diagnostics = resp.Diagnostics.Append(errors.New(p.readError))
+	}
+	resp.NewState = cty.NullVal(r.PriorState.Type())
+	return resp
+}
+
+func (p *failingProvider) ImportResourceState(r providers.ImportResourceStateRequest) (resp providers.ImportResourceStateResponse) {
+	p.imports++
+	resp.Diagnostics = resp.Diagnostics.Append(errors.New(p.importError))
+	return resp
+}
+
+func TestClassifyDiagnostics(t *testing.T) {
+	testCases := map[string]ErrorClass{
+		"NoSuchBucket: The specified bucket does not exist":                  ErrorNotFound,
+		"googleapi: Error 404: The resource was not found":                   ErrorNotFound,
+		"AccessDeniedException: User is not authorized to perform":           ErrorPermissionDenied,
+		"googleapi: Error 403: Required permission denied":                   ErrorPermissionDenied,
+		"ThrottlingException: Rate exceeded":                                 ErrorThrottled,
+		"Provider produced invalid object":                                   ErrorProviderBug,
+		"RequestError: send request failed caused by: dial tcp: i/o timeout": ErrorTransient,
+		"dial tcp: lookup ec2.eu-west-9.amazonaws.com: no such host":         ErrorTransient,
+		"dial tcp 10.0.0.1:443: connection refused, bucket not found":        ErrorTransient,
+		"NoSuchEntity: The role with name test cannot be found":              ErrorNotFound,
+		"NoSuchKey: The specified key does not exist":                        ErrorNotFound,
+		"InvalidVpcID.NotFound: The vpc ID 'vpc-123' does not exist":         ErrorNotFound,
+		"ResourceGroupNotFound: Resource group 'rg' could not be found":      ErrorNotFound,
+		"error configuring provider: shared credentials file not found":      ErrorTransient,
+		"failed to get shared config profile, prod: profile does not exist":  ErrorTransient,
+		"region eu-west-9 could not be found":                                ErrorTransient,
+	}
+	for message, expected := range testCases {
+		var diags tfdiags.Diagnostics
+		diags = diags.Append(errors.New(message))
+		if class := ClassifyDiagnostics(diags); class != expected {
+			t.Errorf("%q classified %s, expected %s", message, class, expected)
+		}
+	}
+}
+
+func TestRefreshErrorClasses(t *testing.T) {
+	testCases := []struct {
+		readError   string
+		importError string
+		class       ErrorClass
+		message     string
+		reads       int
+		imports     int
+	}{
+		{"", "", ErrorNotFound, "null", 1, 0},
+		{"ResourceNotFoundException: instance not found", "", ErrorNotFound, "instance not found", 1, 0},
+		{"AccessDenied: access denied", "", ErrorPermissionDenied, "access denied", 1, 0},
+		{"ThrottlingException: Rate exceeded", "", ErrorThrottled, "Rate exceeded", 3, 0},
+		{"connection timed out", "import failed", ErrorTransient, "import failed", 3, 1},
+		{"Provider produced invalid object", "import failed", ErrorTransient, "import failed", 1, 1},
+	}
+	resourceSchema := providers.Schema{Block: &configschema.Block{
+		Attributes: map[string]*configschema.Attribute{"id": {Type: cty.String, Computed: true}},
+	}}
+	for _, testCase := range testCases {
+		provider := &failingProvider{readError: testCase.readError, importError: testCase.importError}
+		p := &ProviderWrapper{
+			retryCount:   3,
+			retrySleepMs: 1,
+			schema: &providers.GetSchemaResponse{
+				ResourceTypes: map[string]providers.Schema{"test_resource": resourceSchema},
+			},
+		}
+		process := &pluginProcess{client: &plugin.Client{}, provider: provider}
+		state := &terraform.InstanceState{ID: "test", Attributes: map[string]string{"id": "test"}}
+		_, err := p.refresh(context.Background(), process, &terraform.InstanceInfo{Id: "test", Type: "test_resource"}, state)
+		if class := ErrorClassOf(err); class != testCase.class || !strings.Contains(err.Error(), testCase.message) {
+			t.Errorf("%q: unexpected error %v, expected class %s", testCase.readError, err, testCase.class)
+		}
+		if provider.reads != testCase.reads || provider.imports != testCase.imports {
+			t.Errorf("%q: %d reads and %d imports, expected %d and %d", testCase.readError, provider.reads, provider.imports, testCase.reads, testCase.imports)
+		}
+	}
+}
+
+func TestThrottledRetryDelay(t *testing.T) {
+	p := &ProviderWrapper{retrySleepMs: 100}
+	if delay := p.retryDelay(ErrorTransient, 3); delay != 100*time.Millisecond {
+		t.Errorf("unexpected transient delay %s", delay)
+	}
+	for attempt, maxDelay := range []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond} {
+		delay := p.retryDelay(ErrorThrottled, attempt)
+		if delay < maxDelay/2 || delay > maxDelay {
+			t.Errorf("attempt %d: delay %s out of [%s, %s]", attempt, delay, maxDelay/2, maxDelay)
+		}
+	}
+	if delay := p.retryDelay(ErrorThrottled, 20); delay > maxBackoff {
+		t.Errorf("delay %s over %s", delay, maxBackoff)
+	}
+}
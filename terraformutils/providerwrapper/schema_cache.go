@@ -0,0 +1,194 @@
+// Copyright 2018 The Terraformer Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package providerwrapper //nolint
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/hashicorp/terraform/providers"
+)
+
+// schemaCacheDir holds one cached schema per provider binary checksum
+var schemaCacheDir = defaultSchemaCacheDir()
+
+// schemaCache keeps the schemas read during the run, each Import call starts a new wrapper
+var schemaCache = struct {
+	sync.Mutex
+	checksums map[string]string
+	schemas   map[string]*ProviderSchema
+}{
+	checksums: map[string]string{},
+	schemas:   map[string]*ProviderSchema{},
+}
+
+// ProviderSchema is the schema of a provider binary as it is cached on disk
+type ProviderSchema struct {
+	Provider      providers.Schema                  `json:"provider"`
+	ResourceTypes map[string]providers.Schema       `json:"resource_types"`
+	DataSources   map[string]providers.Schema       `json:"data_sources"`
+	NestedTypes   map[string]map[string]*NestedType `json:"nested_types,omitempty"`
+}
+
+func defaultSchemaCacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, "terraformer", "schemas")
+}
+
+func newProviderSchema(schema providers.GetSchemaResponse, nestedTypes map[string]map[string]*NestedType) *ProviderSchema {
+	return &ProviderSchema{
+		Provider:      schema.Provider,
+		ResourceTypes: schema.ResourceTypes,
+		DataSources:   schema.DataSources,
+		NestedTypes:   nestedTypes,
+	}
+}
+
+func (s *ProviderSchema) response() providers.GetSchemaResponse {
+	return providers.GetSchemaResponse{
+		Provider:      s.Provider,
+		ResourceTypes: s.ResourceTypes,
+		DataSources:   s.DataSources,
+	}
+}
+
+// binaryChecksum returns the sha256 of the provider binary, computed once per run
+func binaryChecksum(path string) (string, error) {
+	schemaCache.Lock()
+	defer schemaCache.Unlock()
+	if checksum, exist := schemaCache.checksums[path]; exist {
+		return checksum, nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	hash := sha256.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return "", err
+	}
+	checksum := hex.EncodeToString(hash.Sum(nil))
+	schemaCache.checksums[path] = checksum
+	return checksum, nil
+}
+
+func schemaCacheFile(providerName, checksum string) string {
+	return filepath.Join(schemaCacheDir, providerName+"-"+checksum+".json")
+}
+
+// loadSchema returns the cached schema of the provider binary, nil when it was never cached
+func loadSchema(providerName string, binary *ProviderBinary) *ProviderSchema {
+	checksum, err := binaryChecksum(binary.Path)
+	if err != nil {
+		log.Printf("WARN: failed to checksum %s: %s", binary.Path, err)
+		return nil
+	}
+	schemaCache.Lock()
+	defer schemaCache.Unlock()
+	if schema, exist := schemaCache.schemas[checksum]; exist {
+		return schema
+	}
+	content, err := ioutil.ReadFile(schemaCacheFile(providerName, checksum))
+	if err != nil {
+		return nil
+	}
+	schema := &ProviderSchema{}
+	if err := json.Unmarshal(content, schema); err != nil || schema.Provider.Block == nil {
+		log.Printf("WARN: ignoring invalid cached schema of %s: %v", providerName, err)
+		return nil
+	}
+	schemaCache.schemas[checksum] = schema
+	return schema
+}
+
+// saveSchema caches the schema of the provider binary, a failure only costs fetching it again next run
+func saveSchema(providerName string, binary *ProviderBinary, schema *ProviderSchema) {
+	checksum, err := binaryChecksum(binary.Path)
+	if err != nil {
+		log.Printf("WARN: failed to checksum %s: %s", binary.Path, err)
+		return
+	}
+	schemaCache.Lock()
+	schemaCache.schemas[checksum] = schema
+	schemaCache.Unlock()
+	if err := writeSchemaFile(schemaCacheFile(providerName, checksum), schema); err != nil {
+		log.Printf("WARN: failed to cache schema of %s: %s", providerName, err)
+	}
+}
+
+// writeSchemaFile writes to a temporary file first, concurrent runs never read a partial schema
+func writeSchemaFile(path string, schema *ProviderSchema) error {
+	content, err := json.Marshal(schema)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return err
+	}
+	f, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(content); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// GetProviderSchema returns the schema of the installed provider, from the cache when the binary was seen
+// before, else fetched from a plugin process started without configuration
+func GetProviderSchema(providerName string, verbose bool) (*ProviderSchema, error) {
+	binary, err := FindProvider(providerName)
+	if err != nil {
+		return nil, err
+	}
+	if schema := loadSchema(providerName, binary); schema != nil {
+		return schema, nil
+	}
+	process, err := launchPlugin(binary, verbose)
+	if err != nil {
+		return nil, err
+	}
+	defer process.client.Kill()
+	r := process.provider.GetSchema()
+	if r.Diagnostics.HasErrors() {
+		return nil, fmt.Errorf("failed to get schema of %s: %w", providerName, r.Diagnostics.Err())
+	}
+	var nestedTypes map[string]map[string]*NestedType
+	if grpcProvider, ok := process.provider.(*GRPCProvider); ok {
+		nestedTypes = grpcProvider.NestedTypes()
+	}
+	schema := newProviderSchema(r, nestedTypes)
+	saveSchema(providerName, binary, schema)
+	return schema, nil
+}
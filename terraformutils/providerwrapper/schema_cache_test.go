@@ -0,0 +1,91 @@
+// Copyright 2018 The Terraformer Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package providerwrapper //nolint
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform/configs/configschema"
+	"github.com/hashicorp/terraform/providers"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestSchemaCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "terraformer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	defer func(previous string) { schemaCacheDir = previous }(schemaCacheDir)
+	schemaCacheDir = filepath.Join(dir, "schemas")
+
+	binary := &ProviderBinary{Path: installProvider(t, dir, "registry.terraform.io/hashicorp/aws", "3.10.0")}
+	block := &configschema.Block{
+		Attributes: map[string]*configschema.Attribute{
+			"id":   {Type: cty.String, Computed: true},
+			"tags": {Type: cty.Map(cty.String), Optional: true},
+		},
+		BlockTypes: map[string]*configschema.NestedBlock{
+			"ingress": {
+				Nesting: configschema.NestingSet,
+				Block: configschema.Block{Attributes: map[string]*configschema.Attribute{
+					"port": {Type: cty.Number, Required: true},
+				}},
+			},
+		},
+	}
+	saved := newProviderSchema(providers.GetSchemaResponse{
+		Provider:      providers.Schema{Block: &configschema.Block{}},
+		ResourceTypes: map[string]providers.Schema{"aws_security_group": {Version: 1, Block: block}},
+	}, map[string]map[string]*NestedType{"aws_security_group": {"rules": {
+		Nesting:    configschema.NestingList,
+		Attributes: map[string]*configschema.Attribute{"arn": {Type: cty.String, Computed: true}},
+	}}})
+	saveSchema("aws", binary, saved)
+
+	schemaCache.schemas = map[string]*ProviderSchema{}
+	loaded := loadSchema("aws", binary)
+	if loaded == nil {
+		t.Fatal("schema not cached")
+	}
+	resourceSchema := loaded.ResourceTypes["aws_security_group"]
+	if resourceSchema.Version != 1 || !resourceSchema.Block.ImpliedType().Equals(block.ImpliedType()) {
+		t.Errorf("unexpected schema %v", resourceSchema)
+	}
+	if !loaded.NestedTypes["aws_security_group"]["rules"].Attributes["arn"].Computed {
+		t.Errorf("unexpected nested types %v", loaded.NestedTypes)
+	}
+
+	other := &ProviderBinary{Path: installProvider(t, dir, "registry.terraform.io/hashicorp/aws", "3.11.0")}
+	if loadSchema("aws", other) != nil {
+		t.Error("schema of another binary expected not to be cached")
+	}
+
+	if err := SelectProvider("aws", binary.Path, ""); err != nil {
+		t.Fatal(err)
+	}
+	defer SelectProvider("aws", "", "") //nolint
+	wrapper, err := NewSchemaProviderWrapper("aws", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer wrapper.Kill()
+	if wrapper.GetSchema().ResourceTypes["aws_security_group"].Version != 1 || wrapper.nestedTypes["aws_security_group"]["rules"] == nil {
+		t.Errorf("unexpected schema of wrapper %v", wrapper.GetSchema())
+	}
+}
@@ -0,0 +1,244 @@
+// Copyright 2018 The Terraformer Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package terraformutils
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/GoogleCloudPlatform/terraformer/terraformutils/providerwrapper"
+)
+
+// RegionalProvider is implemented by providers importing one region at a time
+type RegionalProvider interface {
+	GetRegion() string
+}
+
+// IgnoredErrorReporter is implemented by services ignoring errors of InitResources,
+// e.g. AWS services not available in a region
+type IgnoredErrorReporter interface {
+	IgnoredError() error
+}
+
+// Report records the outcome of a run for each provider, region and service
+type Report struct {
+	mutex               sync.Mutex
+	Services            []*ServiceReport     `json:"services"`
+	PluginRestarts      []PluginRestart      `json:"plugin_restarts,omitempty"`
+	AmbiguousReferences []AmbiguousReference `json:"ambiguous_references,omitempty"`
+}
+
+type ServiceReport struct {
+	mutex           sync.Mutex
+	Provider        string            `json:"provider"`
+	Region          string            `json:"region,omitempty"`
+	Service         string            `json:"service"`
+	ListingDuration float64           `json:"listing_duration_seconds"`
+	Found           int               `json:"found"`
+	Refreshed       int               `json:"refreshed"`
+	Filtered        int               `json:"filtered"`
+	NotFound        int               `json:"not_found,omitempty"`
+	Error           string            `json:"error,omitempty"`
+	IgnoredError    string            `json:"ignored_error,omitempty"`
+	Failures        []ResourceFailure `json:"failures,omitempty"`
+}
+
+type ResourceFailure struct {
+	Address string `json:"address"`
+	ID      string `json:"id"`
+	Class   string `json:"class,omitempty"`
+	Error   string `json:"error"`
+}
+
+// PluginRestart records a provider plugin process restarted after it died refreshing a resource
+type PluginRestart struct {
+	Provider string `json:"provider"`
+	Plugin   int    `json:"plugin"`
+	Type     string `json:"type"`
+	ID       string `json:"id"`
+	Error    string `json:"error"`
+}
+
+func NewReport() *Report {
+	return &Report{Services: []*ServiceReport{}}
+}
+
+// Service returns the report of a service, created on first use. Nil reports record nothing.
+func (r *Report) Service(provider, region, service string) *ServiceReport {
+	if r == nil {
+		return nil
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for _, s := range r.Services {
+		if s.Provider == provider && s.Region == region && s.Service == service {
+			return s
+		}
+	}
+	s := &ServiceReport{Provider: provider, Region: region, Service: service}
+	r.Services = append(r.Services, s)
+	return s
+}
+
+func (r *Report) AddPluginRestart(provider string, plugin int, resourceType, id string, err error) {
+	if r == nil {
+		return
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.PluginRestarts = append(r.PluginRestarts, PluginRestart{
+		Provider: provider,
+		Plugin:   plugin,
+		Type:     resourceType,
+		ID:       id,
+		Error:    err.Error(),
+	})
+}
+
+// AddAmbiguousReferences records literals left unchanged because they match several resources
+func (r *Report) AddAmbiguousReferences(references []AmbiguousReference) {
+	if r == nil {
+		return
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.AmbiguousReferences = append(r.AmbiguousReferences, references...)
+}
+
+// Write saves the report as JSON, services sorted by provider, region and name
+func (r *Report) Write(path string) error {
+	if r == nil || path == "" {
+		return nil
+	}
+	r.mutex.Lock()
+	sort.SliceStable(r.Services, func(i, j int) bool {
+		a, b := r.Services[i], r.Services[j]
+		if a.Provider != b.Provider {
+			return a.Provider < b.Provider
+		}
+		if a.Region != b.Region {
+			return a.Region < b.Region
+		}
+		return a.Service < b.Service
+	})
+	data, err := json.MarshalIndent(r, "", "  ")
+	r.mutex.Unlock()
+	if err != nil {
+		return err
+	}
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+			return err
+		}
+	}
+	return ioutil.WriteFile(path, data, os.ModePerm)
+}
+
+// Listed records the listing of a service: its duration, the resources found and kept by filters
+func (s *ServiceReport) Listed(duration time.Duration, found, kept int) {
+	if s == nil {
+		return
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.ListingDuration = duration.Seconds()
+	s.Found = found
+	s.Filtered += found - kept
+}
+
+func (s *ServiceReport) SetRefreshed(refreshed int) {
+	if s == nil {
+		return
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.Refreshed = refreshed
+}
+
+func (s *ServiceReport) AddFiltered(filtered int) {
+	if s == nil {
+		return
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.Filtered += filtered
+}
+
+func (s *ServiceReport) SetError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.Error = err.Error()
+}
+
+func (s *ServiceReport) SetIgnoredError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.IgnoredError = err.Error()
+}
+
+func (s *ServiceReport) AddFailure(address, id string, err error) {
+	if s == nil {
+		return
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.Failures = append(s.Failures, ResourceFailure{
+		Address: address,
+		ID:      id,
+		Class:   string(providerwrapper.ErrorClassOf(err)),
+		Error:   err.Error(),
+	})
+}
+
+// AddNotFound counts a resource deleted between listing and refresh, it is not a failure
+func (s *ServiceReport) AddNotFound() {
+	if s == nil {
+		return
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.NotFound++
+}
+
+// Failures returns the services that failed to list their resources and the resources that failed to import
+func (r *Report) Failures() ([]*ServiceReport, []ResourceFailure) {
+	failedServices := []*ServiceReport{}
+	failedResources := []ResourceFailure{}
+	if r == nil {
+		return failedServices, failedResources
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for _, s := range r.Services {
+		s.mutex.Lock()
+		if s.Error != "" {
+			failedServices = append(failedServices, s)
+		}
+		failedResources = append(failedResources, s.Failures...)
+		s.mutex.Unlock()
+	}
+	return failedServices, failedResources
+}
@@ -0,0 +1,92 @@
+// Copyright 2018 The Terraformer Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package terraformutils
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/terraformer/terraformutils/providerwrapper"
+)
+
+func TestReport(t *testing.T) {
+	report := NewReport()
+	vpc := report.Service("aws", "eu-west-1", "vpc")
+	vpc.Listed(1500*time.Millisecond, 3, 2)
+	vpc.SetRefreshed(1)
+	vpc.AddFiltered(1)
+	vpc.AddFailure("aws_vpc.tfer--vpc-002D-1", "vpc-1", &providerwrapper.RefreshError{Class: providerwrapper.ErrorTransient, Err: errors.New("timeout")})
+	vpc.AddNotFound()
+	report.Service("aws", "eu-west-1", "sqs").SetError(errors.New("no such host"))
+	if report.Service("aws", "eu-west-1", "vpc") != vpc {
+		t.Error("expected the same service report")
+	}
+	report.AddPluginRestart("aws", 1, "aws_vpc", "vpc-1", errors.New("transport is closing"))
+	var disabled *Report
+	disabled.Service("aws", "", "vpc").Listed(time.Second, 1, 1)
+	disabled.AddPluginRestart("aws", 0, "aws_vpc", "vpc-1", errors.New("transport is closing"))
+
+	failedServices, failedResources := report.Failures()
+	if len(failedServices) != 1 || failedServices[0].Service != "sqs" {
+		t.Errorf("unexpected failed services %v", failedServices)
+	}
+	if len(failedResources) != 1 || failedResources[0].ID != "vpc-1" {
+		t.Errorf("unexpected failed resources %v", failedResources)
+	}
+
+	path := filepath.Join(os.TempDir(), "terraformer-report-test", "report.json")
+	defer os.RemoveAll(filepath.Dir(path))
+	if err := report.Write(path); err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed := map[string][]map[string]interface{}{}
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		t.Fatal(err)
+	}
+	if len(parsed["plugin_restarts"]) != 1 || parsed["plugin_restarts"][0]["type"] != "aws_vpc" || parsed["plugin_restarts"][0]["plugin"] != float64(1) {
+		t.Fatalf("unexpected plugin restarts %s", string(data))
+	}
+	if len(parsed["services"]) != 2 || parsed["services"][0]["service"] != "sqs" || parsed["services"][0]["error"] != "no such host" {
+		t.Fatalf("unexpected report %s", string(data))
+	}
+	if !reflect.DeepEqual(parsed["services"][1], map[string]interface{}{
+		"provider":                 "aws",
+		"region":                   "eu-west-1",
+		"service":                  "vpc",
+		"listing_duration_seconds": 1.5,
+		"found":                    float64(3),
+		"refreshed":                float64(1),
+		"filtered":                 float64(2),
+		"not_found":                float64(1),
+		"failures": []interface{}{map[string]interface{}{
+			"address": "aws_vpc.tfer--vpc-002D-1",
+			"id":      "vpc-1",
+			"class":   "transient",
+			"error":   "transient: timeout",
+		}},
+	}) {
+		t.Errorf("unexpected service report %v", parsed["services"][1])
+	}
+}
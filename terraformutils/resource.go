@@ -15,11 +15,11 @@
 package terraformutils
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"regexp"
 	"strings"
-	"time"
 
 	"github.com/GoogleCloudPlatform/terraformer/terraformutils/providerwrapper"
 	"github.com/hashicorp/terraform/terraform"
@@ -27,33 +27,40 @@ import (
 )
 
 type Resource struct {
-	InstanceInfo      *terraform.InstanceInfo
-	InstanceState     *terraform.InstanceState
-	Outputs           map[string]*terraform.OutputState `json:",omitempty"`
-	ResourceName      string
-	Provider          string
-	Item              map[string]interface{} `json:",omitempty"`
-	IgnoreKeys        []string               `json:",omitempty"`
-	AllowEmptyValues  []string               `json:",omitempty"`
-	AdditionalFields  map[string]interface{} `json:",omitempty"`
-	SlowQueryRequired bool
+	InstanceInfo     *terraform.InstanceInfo
+	InstanceState    *terraform.InstanceState
+	Outputs          map[string]*terraform.OutputState `json:",omitempty"`
+	ResourceName     string
+	Provider         string
+	Item             map[string]interface{} `json:",omitempty"`
+	IgnoreKeys       []string               `json:",omitempty"`
+	AllowEmptyValues []string               `json:",omitempty"`
+	AdditionalFields map[string]interface{} `json:",omitempty"`
+	// Deprecated: SlowQueryRequired is ignored, refreshes are paced by the provider rate limits.
+	// It is kept so plan files written by older versions still load.
+	SlowQueryRequired bool `json:",omitempty"`
 }
 
 type ApplicableFilter interface {
 	IsApplicable(resourceName string) bool
 }
 
+// ResourceFilter keeps resources whose FieldPath has one of AcceptableValues, or matching Expression when set
 type ResourceFilter struct {
 	ApplicableFilter
 	ServiceName      string
 	FieldPath        string
 	AcceptableValues []string
+	Expression       *FilterExpression
 }
 
 func (rf *ResourceFilter) Filter(resource Resource) bool {
 	if !rf.IsApplicable(strings.TrimPrefix(resource.InstanceInfo.Type, resource.Provider+"_")) {
 		return true
 	}
+	if rf.Expression != nil {
+		return rf.Expression.Match(resource)
+	}
 	var vals []interface{}
 	if rf.FieldPath == "id" {
 		vals = []interface{}{resource.InstanceState.ID}
@@ -120,15 +127,20 @@ func NewSimpleResource(id, resourceName, resourceType, provider string, allowEmp
 	)
 }
 
-func (r *Resource) Refresh(provider *providerwrapper.ProviderWrapper) {
+func (r *Resource) Refresh(ctx context.Context, provider *providerwrapper.ProviderWrapper) error {
 	var err error
-	if r.SlowQueryRequired {
-		time.Sleep(200 * time.Millisecond)
-	}
-	r.InstanceState, err = provider.Refresh(r.InstanceInfo, r.InstanceState)
-	if err != nil {
-		log.Println(err)
+	r.InstanceState, err = provider.Refresh(ctx, r.InstanceInfo, r.InstanceState)
+	switch providerwrapper.ErrorClassOf(err) {
+	case providerwrapper.ErrorNotFound:
+		log.Printf("%s no longer exists, dropping it", r.InstanceInfo.Id)
+	case providerwrapper.ErrorPermissionDenied:
+		// logged once per resource type by the provider wrapper
+	default:
+		if err != nil {
+			log.Println(err)
+		}
 	}
+	return err
 }
 
 func (r Resource) GetIDKey() string {
@@ -174,6 +186,11 @@ func (r *Resource) ConvertTFstate(provider *providerwrapper.ProviderWrapper) err
 	return r.ParseTFstate(parser, impliedType)
 }
 
+// Address returns the resource address used in generated configuration, e.g. aws_vpc.tfer--main
+func (r Resource) Address() string {
+	return r.InstanceInfo.Type + "." + r.ResourceName
+}
+
 func (r *Resource) ServiceName() string {
 	return strings.TrimPrefix(r.InstanceInfo.Type, r.Provider+"_")
 }
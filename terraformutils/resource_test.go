@@ -0,0 +1,35 @@
+// Copyright 2018 The Terraformer Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+
+package terraformutils
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestDecodeResourceOfOlderPlan(t *testing.T) {
+	data := `{"InstanceInfo": {"Id": "aws_kms_key.tfer--key", "Type": "aws_kms_key"}, "ResourceName": "tfer--key", "SlowQueryRequired": true}`
+	dec := json.NewDecoder(strings.NewReader(data))
+	dec.DisallowUnknownFields()
+	r := Resource{}
+	if err := dec.Decode(&r); err != nil {
+		t.Fatalf("resources of older plan files expected to load: %s", err)
+	}
+	if r.ResourceName != "tfer--key" {
+		t.Errorf("unexpected resource %v", r)
+	}
+}
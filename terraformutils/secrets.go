@@ -0,0 +1,148 @@
+// Copyright 2018 The Terraformer Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package terraformutils
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform/configs/configschema"
+	"github.com/hashicorp/terraform/providers"
+)
+
+const (
+	SecretsStateKeep  = "keep"
+	SecretsStateStrip = "strip"
+)
+
+// CompileSecretPatterns compiles the regular expressions of attributes to redact besides sensitive ones
+func CompileSecretPatterns(patterns []string) ([]*regexp.Regexp, error) {
+	compiled := []*regexp.Regexp{}
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid secret pattern %q: %w", pattern, err)
+		}
+		compiled = append(compiled, re)
+	}
+	return compiled, nil
+}
+
+// RedactSecrets replaces the values of attributes marked sensitive in the provider schema, or whose
+// <type>.<attribute> matches one of patterns, with references to variables named after the resource
+// and attribute. It returns the replaced values by variable name. stripState also empties them in the
+// state, terraform then plans to set them again from the variables.
+func RedactSecrets(resources []Resource, schema *providers.GetSchemaResponse, patterns []*regexp.Regexp, stripState bool) map[string]string {
+	secrets := map[string]string{}
+	names := map[string]bool{}
+	sensitive := map[string]map[string]bool{}
+	for i := range resources {
+		r := &resources[i]
+		resourceType := r.InstanceInfo.Type
+		if _, exist := sensitive[resourceType]; !exist {
+			sensitive[resourceType] = sensitiveAttributes(schema, resourceType)
+		}
+		redacted := map[string]bool{}
+		walkStrings("", r.Item, func(key, value string) string {
+			path := attributePath(key)
+			if value == "" || strings.Contains(value, "${") || !isSecret(resourceType, path, sensitive[resourceType], patterns) {
+				return value
+			}
+			name := uniqueName(secretName(*r, key), names)
+			names[name] = true
+			secrets[name] = value
+			redacted[path] = true
+			return "${var." + name + "}"
+		})
+		if stripState && len(redacted) > 0 && r.InstanceState != nil {
+			for key := range r.InstanceState.Attributes {
+				if redacted[attributePath(key)] && !strings.HasSuffix(key, ".#") && !strings.HasSuffix(key, ".%") {
+					r.InstanceState.Attributes[key] = ""
+				}
+			}
+		}
+	}
+	return secrets
+}
+
+// StripSecrets empties in the state the attributes RedactSecrets redacts, so states written with secrets
+// stripped compare equal to refreshed resources
+func StripSecrets(resources []Resource, schema *providers.GetSchemaResponse, patterns []*regexp.Regexp) {
+	sensitive := map[string]map[string]bool{}
+	for _, r := range resources {
+		if r.InstanceState == nil {
+			continue
+		}
+		resourceType := r.InstanceInfo.Type
+		if _, exist := sensitive[resourceType]; !exist {
+			sensitive[resourceType] = sensitiveAttributes(schema, resourceType)
+		}
+		for key := range r.InstanceState.Attributes {
+			if strings.HasSuffix(key, ".#") || strings.HasSuffix(key, ".%") {
+				continue
+			}
+			if isSecret(resourceType, attributePath(key), sensitive[resourceType], patterns) {
+				r.InstanceState.Attributes[key] = ""
+			}
+		}
+	}
+}
+
+// sensitiveAttributes returns the paths of the sensitive attributes of a resource type, nested blocks included
+func sensitiveAttributes(schema *providers.GetSchemaResponse, resourceType string) map[string]bool {
+	paths := map[string]bool{}
+	if schema == nil {
+		return paths
+	}
+	if resourceSchema, exist := schema.ResourceTypes[resourceType]; exist && resourceSchema.Block != nil {
+		addSensitiveAttributes("", resourceSchema.Block, paths)
+	}
+	return paths
+}
+
+func addSensitiveAttributes(prefix string, block *configschema.Block, paths map[string]bool) {
+	for name, attribute := range block.Attributes {
+		if attribute.Sensitive {
+			paths[prefix+name] = true
+		}
+	}
+	for name, nested := range block.BlockTypes {
+		addSensitiveAttributes(prefix+name+".", &nested.Block, paths)
+	}
+}
+
+// isSecret returns whether path, without list indexes, is or is inside a sensitive attribute, e.g. a
+// value of a sensitive map, or matches a pattern
+func isSecret(resourceType, path string, sensitive map[string]bool, patterns []*regexp.Regexp) bool {
+	segments := strings.Split(path, ".")
+	for i := range segments {
+		if sensitive[strings.Join(segments[:i+1], ".")] {
+			return true
+		}
+	}
+	for _, pattern := range patterns {
+		if pattern.MatchString(resourceType + "." + path) {
+			return true
+		}
+	}
+	return false
+}
+
+// secretName names the variable of a secret after the resource and the attribute holding it
+func secretName(r Resource, key string) string {
+	name := r.InstanceInfo.Type + "_" + strings.TrimPrefix(r.ResourceName, "tfer--") + "_" + key
+	return strings.ToLower(strings.Trim(localNameUnsafeChars.ReplaceAllString(name, "_"), "_"))
+}
@@ -15,6 +15,7 @@
 package terraformutils
 
 import (
+	"context"
 	"log"
 	"strings"
 
@@ -36,6 +37,8 @@ type ServiceGenerator interface {
 	GetProviderName() string
 	GetName() string
 	InitialCleanup()
+	SetManagedResources(managed *ManagedResources)
+	SetContext(ctx context.Context)
 	PopulateIgnoreKeys(*providerwrapper.ProviderWrapper)
 	PostRefreshCleanup()
 }
@@ -47,6 +50,8 @@ type Service struct {
 	Args         map[string]interface{}
 	Filter       []ResourceFilter
 	Verbose      bool
+	Managed      *ManagedResources
+	ctx          context.Context
 }
 
 func (s *Service) SetProviderName(providerName string) {
@@ -71,6 +76,17 @@ func (s *Service) ParseFilters(rawFilters []string) {
 
 func (s *Service) ParseFilter(rawFilter string) []ResourceFilter {
 	var filters []ResourceFilter
+	if expression, serviceName, ok := splitExpressionFilter(rawFilter); ok {
+		parsed, err := ParseFilterExpression(expression)
+		if err != nil {
+			log.Printf("Invalid filter: %s: %s", rawFilter, err)
+			return filters
+		}
+		return append(filters, ResourceFilter{
+			ServiceName: serviceName,
+			Expression:  parsed,
+		})
+	}
 	if !strings.HasPrefix(rawFilter, "Name=") && len(strings.Split(rawFilter, "=")) == 2 {
 		parts := strings.Split(rawFilter, "=")
 		serviceName, resourcesID := parts[0], parts[1]
@@ -119,6 +135,23 @@ func (s *Service) GetName() string {
 	return s.Name
 }
 
+func (s *Service) SetManagedResources(managed *ManagedResources) {
+	s.Managed = managed
+}
+
+// SetContext sets the context InitResources should pass to API calls, cancelled on timeout or interrupt
+func (s *Service) SetContext(ctx context.Context) {
+	s.ctx = ctx
+}
+
+// GetContext returns the context of the service, context.Background() when none was set
+func (s *Service) GetContext() context.Context {
+	if s.ctx == nil {
+		return context.Background()
+	}
+	return s.ctx
+}
+
 func (s *Service) InitialCleanup() {
 	FilterCleanup(s, true)
 }
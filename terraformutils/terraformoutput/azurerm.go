@@ -0,0 +1,95 @@
+// Copyright 2018 The Terraformer Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package terraformoutput
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/Azure/azure-storage-blob-go/azblob"
+)
+
+const azureBlobEndpointFormat = "https://%s.blob.core.windows.net"
+
+// AzureRMState uploads state to a blob container, e.g. --bucket=tfstate with
+// storage_account_name and access_key (or sas_token) in the backend config.
+// blob_endpoint points uploads at a local stand-in like Azurite.
+type AzureRMState struct {
+	Container string
+	Prefix    string
+	config    map[string]string
+}
+
+func NewAzureRMState(bucket string, config map[string]string) (*AzureRMState, error) {
+	container := bucket
+	if container == "" {
+		container = config["container_name"]
+	}
+	if container == "" {
+		return nil, errors.New("azurerm state requires a container")
+	}
+	if config["storage_account_name"] == "" {
+		return nil, errors.New("azurerm state requires storage_account_name backend config")
+	}
+	parts := strings.SplitN(container, "/", 2)
+	a := &AzureRMState{Container: parts[0], Prefix: config["key_prefix"], config: config}
+	if len(parts) == 2 {
+		a.Prefix = parts[1]
+	}
+	return a, nil
+}
+
+func (a *AzureRMState) Type() string {
+	return "azurerm"
+}
+
+func (a *AzureRMState) Config(path string) map[string]interface{} {
+	config := renderBackendConfig(a.config, "key_prefix", "blob_endpoint")
+	config["container_name"] = a.Container
+	config["key"] = stateKey(a.Prefix, path)
+	return config
+}
+
+func (a *AzureRMState) Upload(path string, state []byte) error {
+	accountName := a.config["storage_account_name"]
+	endpoint := a.config["blob_endpoint"]
+	if endpoint == "" {
+		endpoint = fmt.Sprintf(azureBlobEndpointFormat, accountName)
+	}
+	var credential azblob.Credential
+	if sasToken := a.config["sas_token"]; sasToken != "" {
+		credential = azblob.NewAnonymousCredential()
+		endpoint += "?" + strings.TrimPrefix(sasToken, "?")
+	} else {
+		sharedKeyCredential, err := azblob.NewSharedKeyCredential(accountName, a.config["access_key"])
+		if err != nil {
+			return err
+		}
+		credential = sharedKeyCredential
+	}
+	serviceURL, err := url.Parse(endpoint)
+	if err != nil {
+		return err
+	}
+	containerURL := azblob.NewServiceURL(*serviceURL, azblob.NewPipeline(credential, azblob.PipelineOptions{})).
+		NewContainerURL(a.Container)
+	_, err = azblob.UploadBufferToBlockBlob(context.Background(), state, containerURL.NewBlockBlobURL(stateKey(a.Prefix, path)),
+		azblob.UploadToBlockBlobOptions{
+			BlobHTTPHeaders: azblob.BlobHTTPHeaders{ContentType: "application/json"},
+		})
+	return err
+}
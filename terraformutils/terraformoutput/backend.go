@@ -0,0 +1,154 @@
+// Copyright 2018 The Terraformer Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package terraformoutput
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+// StateBackend stores generated state and describes how terraform reaches it
+type StateBackend interface {
+	// Type is the terraform backend type, e.g. gcs or s3
+	Type() string
+	// Config is the backend configuration for the state of the code generated in path
+	Config(path string) map[string]interface{}
+	// Upload stores the state of the code generated in path
+	Upload(path string, state []byte) error
+}
+
+// credentials are used for uploads but never rendered into generated code
+var secretBackendConfigKeys = map[string]struct{}{
+	"access_key":    {},
+	"secret_key":    {},
+	"token":         {},
+	"password":      {},
+	"sas_token":     {},
+	"client_secret": {},
+}
+
+func NewStateBackend(state, bucket string, rawConfig []string) (StateBackend, error) {
+	config := map[string]string{}
+	for _, c := range rawConfig {
+		parts := strings.SplitN(c, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid backend config %s, expected key=value", c)
+		}
+		config[parts[0]] = parts[1]
+	}
+	switch state {
+	case "local":
+		return LocalState{}, nil
+	case "bucket", "gcs":
+		return BucketState{Name: bucket}, nil
+	case "s3":
+		return NewS3State(bucket, config)
+	case "azurerm":
+		return NewAzureRMState(bucket, config)
+	case "http":
+		return NewHTTPState(bucket, config)
+	}
+	return nil, fmt.Errorf("unsupported state backend: %s", state)
+}
+
+// RedactBackendConfig drops credentials from key=value backend config, e.g. before saving it to a plan file
+func RedactBackendConfig(rawConfig []string) []string {
+	redacted := []string{}
+	for _, c := range rawConfig {
+		if _, secret := secretBackendConfigKeys[strings.SplitN(c, "=", 2)[0]]; !secret {
+			redacted = append(redacted, c)
+		}
+	}
+	return redacted
+}
+
+// BackendTfData renders the terraform backend block for the code generated in path
+func BackendTfData(b StateBackend, path string) interface{} {
+	return map[string]interface{}{
+		"terraform": map[string]interface{}{
+			"backend": []map[string]interface{}{
+				{
+					b.Type(): b.Config(path),
+				},
+			},
+		},
+	}
+}
+
+// RemoteStateTfData renders a terraform_remote_state data source reading the state of path
+func RemoteStateTfData(b StateBackend, path string) map[string]interface{} {
+	return map[string]interface{}{
+		"backend": b.Type(),
+		"config":  b.Config(path),
+	}
+}
+
+// renderBackendConfig copies user supplied backend config without credentials and upload only keys
+func renderBackendConfig(config map[string]string, uploadOnlyKeys ...string) map[string]interface{} {
+	rendered := map[string]interface{}{}
+	for k, v := range config {
+		if _, secret := secretBackendConfigKeys[k]; secret {
+			continue
+		}
+		if containsKey(uploadOnlyKeys, k) {
+			continue
+		}
+		switch v {
+		case "true":
+			rendered[k] = true
+		case "false":
+			rendered[k] = false
+		default:
+			rendered[k] = v
+		}
+	}
+	return rendered
+}
+
+func containsKey(keys []string, key string) bool {
+	for _, k := range keys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+func stateKey(prefix, path string) string {
+	key := strings.Trim(path, "/") + "/terraform.tfstate"
+	if prefix != "" {
+		key = strings.Trim(prefix, "/") + "/" + key
+	}
+	return key
+}
+
+// LocalState keeps terraform.tfstate next to the generated code
+type LocalState struct{}
+
+func (l LocalState) Type() string {
+	return "local"
+}
+
+func (l LocalState) Config(path string) map[string]interface{} {
+	path = strings.TrimSuffix(path, "/")
+	return map[string]interface{}{
+		"path": strings.Repeat("../", strings.Count(path, "/")+1) + path + "/terraform.tfstate",
+	}
+}
+
+func (l LocalState) Upload(path string, state []byte) error {
+	return ioutil.WriteFile(strings.TrimSuffix(path, "/")+"/terraform.tfstate", state, os.ModePerm)
+}
@@ -0,0 +1,142 @@
+// Copyright 2018 The Terraformer Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package terraformoutput
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/terraformer/terraformutils"
+)
+
+func TestS3StateConfig(t *testing.T) {
+	backend, err := NewStateBackend("s3", "s3://terraform-state/team", []string{
+		"region=eu-west-1",
+		"dynamodb_table=terraform-lock",
+		"access_key=AKIA",
+		"secret_key=secret",
+		"force_path_style=true",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(backend.Config("generated/aws/vpc/"), map[string]interface{}{
+		"bucket":           "terraform-state",
+		"key":              "team/generated/aws/vpc/terraform.tfstate",
+		"region":           "eu-west-1",
+		"dynamodb_table":   "terraform-lock",
+		"force_path_style": true,
+	}) {
+		t.Errorf("unexpected config %v", backend.Config("generated/aws/vpc/"))
+	}
+
+	data, err := terraformutils.Print(BackendTfData(backend, "generated/aws/vpc/"), "hcl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `backend "s3" {`) || strings.Contains(string(data), "secret") {
+		t.Errorf("unexpected backend block %s", string(data))
+	}
+}
+
+func TestRedactBackendConfig(t *testing.T) {
+	redacted := RedactBackendConfig([]string{"region=eu-west-1", "access_key=AKIA", "secret_key=secret", "dynamodb_table=terraform-lock"})
+	if !reflect.DeepEqual(redacted, []string{"region=eu-west-1", "dynamodb_table=terraform-lock"}) {
+		t.Errorf("unexpected config %v", redacted)
+	}
+}
+
+func TestAzureRMStateConfig(t *testing.T) {
+	if _, err := NewStateBackend("azurerm", "tfstate", []string{}); err == nil {
+		t.Error("expected storage_account_name to be required")
+	}
+	backend, err := NewStateBackend("azurerm", "tfstate", []string{
+		"storage_account_name=terraformer",
+		"access_key=key",
+		"blob_endpoint=http://127.0.0.1:10000/devstoreaccount1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(RemoteStateTfData(backend, "generated/azurerm/network/"), map[string]interface{}{
+		"backend": "azurerm",
+		"config": map[string]interface{}{
+			"storage_account_name": "terraformer",
+			"container_name":       "tfstate",
+			"key":                  "generated/azurerm/network/terraform.tfstate",
+		},
+	}) {
+		t.Errorf("unexpected remote state %v", RemoteStateTfData(backend, "generated/azurerm/network/"))
+	}
+}
+
+func TestLocalStateConfig(t *testing.T) {
+	backend, err := NewStateBackend("local", "", []string{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(backend.Config("generated/aws/vpc/"), map[string]interface{}{
+		"path": "../../../generated/aws/vpc/terraform.tfstate",
+	}) {
+		t.Errorf("unexpected config %v", backend.Config("generated/aws/vpc/"))
+	}
+}
+
+func TestHTTPStateUpload(t *testing.T) {
+	var method, path, body, user string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method, path = r.Method, r.URL.Path
+		user, _, _ = r.BasicAuth()
+		data, _ := ioutil.ReadAll(r.Body)
+		body = string(data)
+	}))
+	defer server.Close()
+
+	backend, err := NewStateBackend("http", server.URL+"/state/", []string{"username=terraformer", "password=secret", "lock_address=" + server.URL + "/lock"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := backend.Upload("generated/aws/vpc/", []byte(`{"version": 4}`)); err != nil {
+		t.Fatal(err)
+	}
+	if method != http.MethodPost || path != "/state/generated/aws/vpc" || body != `{"version": 4}` || user != "terraformer" {
+		t.Errorf("unexpected upload %s %s %s %s", method, path, body, user)
+	}
+	if !reflect.DeepEqual(backend.Config("generated/aws/vpc/"), map[string]interface{}{
+		"address":      server.URL + "/state/generated/aws/vpc",
+		"lock_address": server.URL + "/lock/generated/aws/vpc",
+		"username":     "terraformer",
+	}) {
+		t.Errorf("unexpected config %v", backend.Config("generated/aws/vpc/"))
+	}
+}
+
+func TestHTTPStateUploadFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+	}))
+	defer server.Close()
+
+	backend, err := NewStateBackend("http", server.URL, []string{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := backend.Upload("generated/aws/vpc/", []byte(`{}`)); err == nil {
+		t.Error("expected upload to fail")
+	}
+}
@@ -15,7 +15,6 @@ package terraformoutput
 
 import (
 	"context"
-	"log"
 	"strings"
 
 	"cloud.google.com/go/storage"
@@ -25,32 +24,26 @@ type BucketState struct {
 	Name string
 }
 
-func (b BucketState) BucketGetTfData(path string) interface{} {
-	name := strings.ReplaceAll(b.Name, "gs://", "")
-	bucketStateData := map[string]interface{}{
-		"terraform": map[string]interface{}{
-			"backend": []map[string]interface{}{
-				{
-					"gcs": map[string]interface{}{
-						"bucket": name,
-						"prefix": b.BucketPrefix(path),
-					},
-				},
-			},
-		},
+func (b BucketState) Type() string {
+	return "gcs"
+}
+
+func (b BucketState) Config(path string) map[string]interface{} {
+	return map[string]interface{}{
+		"bucket": strings.ReplaceAll(b.Name, "gs://", ""),
+		"prefix": b.BucketPrefix(path),
 	}
-	return bucketStateData
 }
 
 func (b BucketState) BucketPrefix(path string) string {
 	return strings.TrimSuffix(path, "/")
 }
 
-func (b BucketState) BucketUpload(path string, file []byte) error {
+func (b BucketState) Upload(path string, file []byte) error {
 	ctx := context.Background()
 	client, err := storage.NewClient(ctx)
 	if err != nil {
-		log.Fatalf("Failed to create client: %v", err)
+		return err
 	}
 	name := strings.ReplaceAll(b.Name, "gs://", "")
 	wc := client.Bucket(name).Object(b.BucketPrefix(path) + "/default.tfstate").NewWriter(ctx)
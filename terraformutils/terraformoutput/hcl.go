@@ -22,57 +22,46 @@ import (
 	"github.com/GoogleCloudPlatform/terraformer/terraformutils"
 	"github.com/GoogleCloudPlatform/terraformer/terraformutils/providerwrapper"
 
+	"github.com/hashicorp/terraform/providers"
 	"github.com/hashicorp/terraform/terraform"
 )
 
-func OutputHclFiles(resources []terraformutils.Resource, provider terraformutils.ProviderGenerator, path string, serviceName string, isCompact bool, output string) error {
+// OutputHclFiles writes the resources and their outputs, exposing the keys other services connect to
+func OutputHclFiles(resources []terraformutils.Resource, provider terraformutils.ProviderGenerator, path string, serviceName string, isCompact bool, output string, schema *providers.GetSchemaResponse, connections map[string]map[string][]string) error {
 	if err := os.MkdirAll(path, os.ModePerm); err != nil {
 		return err
 	}
-	// create provider file
-	providerData := provider.GetProviderData()
-	providerData["terraform"] = map[string]interface{}{
-		"required_providers": []map[string]interface{}{{
-			provider.GetName(): []map[string]interface{}{{
-				"version": providerwrapper.GetProviderVersion(provider.GetName()),
-			}},
-		}},
-	}
-
-	providerDataFile, err := terraformutils.Print(providerData, map[string]struct{}{}, output)
-	if err != nil {
-		return err
-	}
-	PrintFile(path+"/provider."+GetFileExtension(output), providerDataFile)
-
 	// create outputs files
 	outputs := map[string]interface{}{}
 	outputsByResource := map[string]map[string]interface{}{}
 
 	for i, r := range resources {
 		outputState := map[string]*terraform.OutputState{}
-		outputsByResource[r.InstanceInfo.Type+"_"+r.ResourceName+"_"+r.GetIDKey()] = map[string]interface{}{
-			"value": r.InstanceInfo.Type + "." + r.ResourceName + "." + r.GetIDKey(),
+		outputsByResource[terraformutils.OutputName(r, r.GetIDKey())] = map[string]interface{}{
+			"value": "${" + r.InstanceInfo.Type + "." + r.ResourceName + "." + r.GetIDKey() + "}",
 		}
-		outputState[r.InstanceInfo.Type+"_"+r.ResourceName+"_"+r.GetIDKey()] = &terraform.OutputState{
+		outputState[terraformutils.OutputName(r, r.GetIDKey())] = &terraform.OutputState{
 			Type:  "string",
 			Value: r.InstanceState.Attributes[r.GetIDKey()],
 		}
-		for _, v := range provider.GetResourceConnections() {
+		for _, v := range connections {
 			for k, ids := range v {
 				if (serviceName != "" && k == serviceName) || (serviceName == "" && k == r.ServiceName()) {
-					if _, exist := r.InstanceState.Attributes[ids[1]]; exist {
-						key := ids[1]
-						if ids[1] == "self_link" || ids[1] == "id" {
+					for j := 1; j < len(ids); j += 2 {
+						if _, exist := r.InstanceState.Attributes[ids[j]]; !exist {
+							continue
+						}
+						key := ids[j]
+						if ids[j] == "self_link" || ids[j] == "id" {
 							key = r.GetIDKey()
 						}
-						linkKey := r.InstanceInfo.Type + "_" + r.ResourceName + "_" + key
+						linkKey := terraformutils.OutputName(r, key)
 						outputsByResource[linkKey] = map[string]interface{}{
-							"value": r.InstanceInfo.Type + "." + r.ResourceName + "." + key,
+							"value": "${" + r.InstanceInfo.Type + "." + r.ResourceName + "." + key + "}",
 						}
 						outputState[linkKey] = &terraform.OutputState{
 							Type:  "string",
-							Value: r.InstanceState.Attributes[ids[1]],
+							Value: r.InstanceState.Attributes[key],
 						}
 					}
 				}
@@ -82,7 +71,7 @@ func OutputHclFiles(resources []terraformutils.Resource, provider terraformutils
 	}
 	if len(outputsByResource) > 0 {
 		outputs["output"] = outputsByResource
-		outputsFile, err := terraformutils.Print(outputs, map[string]struct{}{}, output)
+		outputsFile, err := terraformutils.Print(outputs, output)
 		if err != nil {
 			return err
 		}
@@ -95,14 +84,14 @@ func OutputHclFiles(resources []terraformutils.Resource, provider terraformutils
 		typeOfServices[r.InstanceInfo.Type] = append(typeOfServices[r.InstanceInfo.Type], r)
 	}
 	if isCompact {
-		err := printFile(resources, "resources", path, output)
+		err := printFile(resources, "resources", path, output, schema)
 		if err != nil {
 			return err
 		}
 	} else {
 		for k, v := range typeOfServices {
 			fileName := strings.ReplaceAll(k, strings.Split(k, "_")[0]+"_", "")
-			err := printFile(v, fileName, path, output)
+			err := printFile(v, fileName, path, output, schema)
 			if err != nil {
 				return err
 			}
@@ -111,8 +100,45 @@ func OutputHclFiles(resources []terraformutils.Resource, provider terraformutils
 	return nil
 }
 
-func printFile(v []terraformutils.Resource, fileName, path, output string) error {
-	tfFile, err := terraformutils.HclPrintResource(v, map[string]interface{}{}, output)
+// OutputProviderFile writes provider.tf with the provider requirements, and the dependency lock file in root modules.
+// Child modules receive their provider configuration from the root module, so withConfig is false for them.
+func OutputProviderFile(provider terraformutils.ProviderGenerator, path, output string, schema *providers.GetSchemaResponse, withConfig bool) error {
+	if err := os.MkdirAll(path, os.ModePerm); err != nil {
+		return err
+	}
+	providerData := map[string]interface{}{}
+	if withConfig {
+		providerData = provider.GetProviderData()
+	}
+	providerData["terraform"] = map[string]interface{}{
+		"required_providers": []map[string]interface{}{{
+			provider.GetName(): map[string]interface{}{
+				"source":  providerwrapper.GetProviderSourceAddress(provider.GetName()),
+				"version": providerwrapper.GetProviderVersion(provider.GetName()),
+			},
+		}},
+	}
+
+	providerDataFile, err := terraformutils.PrintWithSchema(providerData, schema, output)
+	if err != nil {
+		return err
+	}
+	PrintFile(path+"/provider."+GetFileExtension(output), providerDataFile)
+	if !withConfig {
+		return nil
+	}
+	lockFile, err := providerwrapper.LockFile(provider.GetName())
+	if err != nil {
+		return err
+	}
+	if lockFile != nil {
+		PrintFile(path+"/"+providerwrapper.LockFileName, lockFile)
+	}
+	return nil
+}
+
+func printFile(v []terraformutils.Resource, fileName, path, output string, schema *providers.GetSchemaResponse) error {
+	tfFile, err := terraformutils.HclPrintResource(v, map[string]interface{}{}, output, schema)
 	if err != nil {
 		return err
 	}
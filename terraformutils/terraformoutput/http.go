@@ -0,0 +1,95 @@
+// Copyright 2018 The Terraformer Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package terraformoutput
+
+import (
+	"bytes"
+	"crypto/md5" //nolint
+	"crypto/tls"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// HTTPState uploads state to a REST endpoint, e.g. --bucket=https://state.example.com/terraformer.
+// The state of each generated path is stored under its own address below the base address.
+type HTTPState struct {
+	Address string
+	config  map[string]string
+}
+
+func NewHTTPState(bucket string, config map[string]string) (*HTTPState, error) {
+	address := bucket
+	if address == "" {
+		address = config["address"]
+	}
+	if address == "" {
+		return nil, errors.New("http state requires an address")
+	}
+	return &HTTPState{Address: address, config: config}, nil
+}
+
+func (h *HTTPState) Type() string {
+	return "http"
+}
+
+func (h *HTTPState) Config(path string) map[string]interface{} {
+	config := renderBackendConfig(h.config)
+	config["address"] = h.address(h.Address, path)
+	for _, k := range []string{"lock_address", "unlock_address"} {
+		if base, exist := h.config[k]; exist {
+			config[k] = h.address(base, path)
+		}
+	}
+	return config
+}
+
+func (h *HTTPState) address(base, path string) string {
+	return strings.TrimSuffix(base, "/") + "/" + strings.Trim(path, "/")
+}
+
+func (h *HTTPState) Upload(path string, state []byte) error {
+	method := h.config["update_method"]
+	if method == "" {
+		method = http.MethodPost
+	}
+	req, err := http.NewRequest(method, h.address(h.Address, path), bytes.NewReader(state))
+	if err != nil {
+		return err
+	}
+	sum := md5.Sum(state) //nolint
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Content-MD5", base64.StdEncoding.EncodeToString(sum[:]))
+	if username := h.config["username"]; username != "" {
+		req.SetBasicAuth(username, h.config["password"])
+	}
+	client := &http.Client{}
+	if skip, _ := strconv.ParseBool(h.config["skip_cert_verification"]); skip {
+		client.Transport = &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true}, //nolint
+		}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("failed to upload state to %s: %s", req.URL, resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+// Copyright 2018 The Terraformer Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package terraformoutput
+
+import (
+	"bytes"
+	"crypto/md5" //nolint
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// S3State uploads state to an S3 bucket, e.g. --bucket=s3://terraform-state/prefix.
+// An endpoint and force_path_style=true in the backend config allow S3 compatible stores like MinIO.
+type S3State struct {
+	Bucket string
+	Prefix string
+	config map[string]string
+}
+
+func NewS3State(bucket string, config map[string]string) (*S3State, error) {
+	name := strings.TrimPrefix(bucket, "s3://")
+	if name == "" {
+		name = config["bucket"]
+	}
+	if name == "" {
+		return nil, errors.New("s3 state requires a bucket")
+	}
+	parts := strings.SplitN(name, "/", 2)
+	s := &S3State{Bucket: parts[0], Prefix: config["key_prefix"], config: config}
+	if len(parts) == 2 {
+		s.Prefix = parts[1]
+	}
+	return s, nil
+}
+
+func (s *S3State) Type() string {
+	return "s3"
+}
+
+func (s *S3State) Config(path string) map[string]interface{} {
+	config := renderBackendConfig(s.config, "key_prefix")
+	config["bucket"] = s.Bucket
+	config["key"] = stateKey(s.Prefix, path)
+	return config
+}
+
+func (s *S3State) Upload(path string, state []byte) error {
+	sess, err := session.NewSessionWithOptions(session.Options{
+		Config:            *s.awsConfig(),
+		Profile:           s.config["profile"],
+		SharedConfigState: session.SharedConfigEnable,
+	})
+	if err != nil {
+		return err
+	}
+	key := stateKey(s.Prefix, path)
+	input := &s3.PutObjectInput{
+		Bucket:      aws.String(s.Bucket),
+		Key:         aws.String(key),
+		Body:        bytes.NewReader(state),
+		ContentType: aws.String("application/json"),
+	}
+	if encrypt, _ := strconv.ParseBool(s.config["encrypt"]); encrypt {
+		input.ServerSideEncryption = aws.String(s3.ServerSideEncryptionAes256)
+	}
+	s3Config := aws.NewConfig()
+	if endpoint := s.config["endpoint"]; endpoint != "" {
+		s3Config = s3Config.WithEndpoint(endpoint)
+	}
+	if forcePathStyle, _ := strconv.ParseBool(s.config["force_path_style"]); forcePathStyle {
+		s3Config = s3Config.WithS3ForcePathStyle(true)
+	}
+	if _, err := s3.New(sess, s3Config).PutObject(input); err != nil {
+		return err
+	}
+
+	table := s.config["dynamodb_table"]
+	if table == "" {
+		return nil
+	}
+	// with locking enabled terraform refuses state that doesn't match the stored digest
+	dynamoConfig := aws.NewConfig()
+	if endpoint := s.config["dynamodb_endpoint"]; endpoint != "" {
+		dynamoConfig = dynamoConfig.WithEndpoint(endpoint)
+	}
+	sum := md5.Sum(state) //nolint
+	_, err = dynamodb.New(sess, dynamoConfig).PutItem(&dynamodb.PutItemInput{
+		TableName: aws.String(table),
+		Item: map[string]*dynamodb.AttributeValue{
+			"LockID": {S: aws.String(s.Bucket + "/" + key + "-md5")},
+			"Digest": {S: aws.String(hex.EncodeToString(sum[:]))},
+		},
+	})
+	return err
+}
+
+func (s *S3State) awsConfig() *aws.Config {
+	config := aws.NewConfig()
+	if region := s.config["region"]; region != "" {
+		config = config.WithRegion(region)
+	}
+	if accessKey := s.config["access_key"]; accessKey != "" {
+		config = config.WithCredentials(credentials.NewStaticCredentials(accessKey, s.config["secret_key"], s.config["token"]))
+	}
+	return config
+}
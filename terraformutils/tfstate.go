@@ -0,0 +1,347 @@
+// Copyright 2018 The Terraformer Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package terraformutils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/terraformer/terraformutils/providerwrapper"
+	"github.com/hashicorp/go-uuid"
+	"github.com/hashicorp/terraform/providers"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+)
+
+// StateVersion is the state file format written by terraformer
+const StateVersion = 4
+
+// StateTerraformVersion is the oldest terraform able to read fully qualified provider addresses
+const StateTerraformVersion = "0.13.0"
+
+// TfState is the v4 terraform state file format
+type TfState struct {
+	Version          int                      `json:"version"`
+	TerraformVersion string                   `json:"terraform_version"`
+	Serial           uint64                   `json:"serial"`
+	Lineage          string                   `json:"lineage"`
+	Outputs          map[string]TfStateOutput `json:"outputs"`
+	Resources        []TfStateResource        `json:"resources"`
+}
+
+type TfStateOutput struct {
+	Value     json.RawMessage `json:"value"`
+	Type      json.RawMessage `json:"type"`
+	Sensitive bool            `json:"sensitive,omitempty"`
+}
+
+type TfStateResource struct {
+	Module    string                    `json:"module,omitempty"`
+	Mode      string                    `json:"mode"`
+	Type      string                    `json:"type"`
+	Name      string                    `json:"name"`
+	Provider  string                    `json:"provider"`
+	Instances []TfStateResourceInstance `json:"instances"`
+}
+
+type TfStateResourceInstance struct {
+	IndexKey       interface{}       `json:"index_key,omitempty"`
+	SchemaVersion  uint64            `json:"schema_version"`
+	AttributesRaw  json.RawMessage   `json:"attributes,omitempty"`
+	AttributesFlat map[string]string `json:"attributes_flat,omitempty"`
+	Private        []byte            `json:"private,omitempty"`
+	Dependencies   []string          `json:"dependencies,omitempty"`
+}
+
+// NewTfState builds a v4 state, decoding the flatmap attributes of each resource
+// with the provider schema. Resources unknown to the schema keep flat attributes.
+func NewTfState(resources []Resource, schema *providers.GetSchemaResponse) *TfState {
+	return NewModulesTfState(map[string][]Resource{"": resources}, schema)
+}
+
+// NewModulesTfState builds a single v4 state for resources of child modules, keyed by module
+// name. Resources of the root module are keyed by an empty name.
+func NewModulesTfState(resourcesByModule map[string][]Resource, schema *providers.GetSchemaResponse) *TfState {
+	lineage, err := uuid.GenerateUUID()
+	if err != nil {
+		log.Println("failed to generate state lineage:", err)
+	}
+	tfstate := &TfState{
+		Version:          StateVersion,
+		TerraformVersion: StateTerraformVersion,
+		Serial:           1,
+		Lineage:          lineage,
+		Outputs:          map[string]TfStateOutput{},
+		Resources:        []TfStateResource{},
+	}
+	// only outputs of the root module are saved in state
+	for _, r := range resourcesByModule[""] {
+		for k, v := range r.Outputs {
+			output, err := newTfStateOutput(v.Value)
+			if err != nil {
+				log.Printf("failed to write output %s because of error %s", k, err)
+				continue
+			}
+			tfstate.Outputs[k] = output
+		}
+	}
+
+	providerAddresses := map[string]string{}
+	for module, resources := range resourcesByModule {
+		if module != "" {
+			module = "module." + module
+		}
+		for _, r := range resources {
+			if _, exist := providerAddresses[r.Provider]; !exist {
+				providerAddresses[r.Provider] = "provider[\"" + providerwrapper.GetProviderSource(r.Provider) + "\"]"
+			}
+			tfstate.Resources = append(tfstate.Resources, TfStateResource{
+				Module:    module,
+				Mode:      "managed",
+				Type:      r.InstanceInfo.Type,
+				Name:      r.ResourceName,
+				Provider:  providerAddresses[r.Provider],
+				Instances: []TfStateResourceInstance{newTfStateResourceInstance(r, schema)},
+			})
+		}
+	}
+	sort.Slice(tfstate.Resources, func(i, j int) bool {
+		if tfstate.Resources[i].Module != tfstate.Resources[j].Module {
+			return tfstate.Resources[i].Module < tfstate.Resources[j].Module
+		}
+		if tfstate.Resources[i].Type != tfstate.Resources[j].Type {
+			return tfstate.Resources[i].Type < tfstate.Resources[j].Type
+		}
+		return tfstate.Resources[i].Name < tfstate.Resources[j].Name
+	})
+	return tfstate
+}
+
+// newTfStateOutput keeps the type of the value, lists and maps are written as tuples and objects
+func newTfStateOutput(value interface{}) (TfStateOutput, error) {
+	if value == nil {
+		return TfStateOutput{}, fmt.Errorf("unsupported null value")
+	}
+	valueJSON, err := json.Marshal(value)
+	if err != nil {
+		return TfStateOutput{}, err
+	}
+	ty, err := ctyjson.ImpliedType(valueJSON)
+	if err != nil {
+		return TfStateOutput{}, err
+	}
+	typeJSON, err := ctyjson.MarshalType(ty)
+	if err != nil {
+		return TfStateOutput{}, err
+	}
+	return TfStateOutput{Value: valueJSON, Type: typeJSON}, nil
+}
+
+func newTfStateResourceInstance(r Resource, schema *providers.GetSchemaResponse) TfStateResourceInstance {
+	instance := TfStateResourceInstance{
+		SchemaVersion: instanceSchemaVersion(r),
+	}
+	if schema != nil {
+		if resourceSchema, exist := schema.ResourceTypes[r.InstanceInfo.Type]; exist && resourceSchema.Block != nil {
+			instance.SchemaVersion = uint64(resourceSchema.Version)
+			attributes, err := decodeStateAttributes(r, resourceSchema.Block.ImpliedType())
+			if err == nil {
+				instance.AttributesRaw = attributes
+				return instance
+			}
+			log.Printf("failed to decode attributes of %s, keeping flat attributes: %s", r.Address(), err)
+		}
+	}
+	instance.AttributesFlat = r.InstanceState.Attributes
+	if instance.AttributesFlat == nil {
+		instance.AttributesFlat = map[string]string{}
+	}
+	return instance
+}
+
+func decodeStateAttributes(r Resource, impliedType cty.Type) (json.RawMessage, error) {
+	val, err := r.InstanceState.AttrsAsObjectValue(impliedType)
+	if err != nil {
+		return nil, err
+	}
+	return ctyjson.Marshal(val, impliedType)
+}
+
+// instanceSchemaVersion reads the schema version recorded by the provider refresh
+func instanceSchemaVersion(r Resource) uint64 {
+	if r.InstanceState.Meta == nil {
+		return 0
+	}
+	switch v := r.InstanceState.Meta["schema_version"].(type) {
+	case int:
+		return uint64(v)
+	case float64: // after a round trip through a planfile
+		return uint64(v)
+	case string:
+		version, _ := strconv.ParseUint(v, 10, 64)
+		return version
+	}
+	return 0
+}
+
+func PrintTfState(resources []Resource, schema *providers.GetSchemaResponse) ([]byte, error) {
+	state := NewTfState(resources, schema)
+	return json.MarshalIndent(state, "", "  ")
+}
+
+func PrintModulesTfState(resourcesByModule map[string][]Resource, schema *providers.GetSchemaResponse) ([]byte, error) {
+	state := NewModulesTfState(resourcesByModule, schema)
+	return json.MarshalIndent(state, "", "  ")
+}
+
+// tfStateV3 is the legacy state format written by terraform 0.11 and older terraformer versions
+type tfStateV3 struct {
+	Modules []struct {
+		Resources map[string]struct {
+			Type    string `json:"type"`
+			Primary *struct {
+				ID         string            `json:"id"`
+				Attributes map[string]string `json:"attributes"`
+			} `json:"primary"`
+		} `json:"resources"`
+	} `json:"modules"`
+}
+
+// ReadTfStateResources reads the managed resources of a v3 or v4 state file with flatmap attributes
+func ReadTfStateResources(data []byte) ([]Resource, error) {
+	version := struct {
+		Version int `json:"version"`
+	}{}
+	if err := json.Unmarshal(data, &version); err != nil {
+		return nil, err
+	}
+	switch version.Version {
+	case 3:
+		return readTfStateV3Resources(data)
+	case StateVersion:
+		return readTfStateV4Resources(data)
+	}
+	return nil, fmt.Errorf("unsupported state version %d", version.Version)
+}
+
+func readTfStateV3Resources(data []byte) ([]Resource, error) {
+	state := tfStateV3{}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, err
+	}
+	resources := []Resource{}
+	for _, module := range state.Modules {
+		for key, r := range module.Resources {
+			if strings.HasPrefix(key, "data.") || r.Primary == nil {
+				continue
+			}
+			name := strings.TrimPrefix(key, r.Type+".")
+			resources = append(resources, newStateResource(r.Type, name, r.Primary.ID, r.Primary.Attributes))
+		}
+	}
+	return resources, nil
+}
+
+func readTfStateV4Resources(data []byte) ([]Resource, error) {
+	state := TfState{}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, err
+	}
+	resources := []Resource{}
+	for _, r := range state.Resources {
+		if r.Mode != "managed" {
+			continue
+		}
+		for _, instance := range r.Instances {
+			attributes := instance.AttributesFlat
+			if instance.AttributesRaw != nil {
+				var err error
+				attributes, err = flattenJSONAttributes(instance.AttributesRaw)
+				if err != nil {
+					return nil, fmt.Errorf("failed to read attributes of %s.%s: %s", r.Type, r.Name, err)
+				}
+			}
+			name := r.Name
+			switch index := instance.IndexKey.(type) {
+			case float64:
+				name += "[" + strconv.FormatFloat(index, 'f', -1, 64) + "]"
+			case string:
+				name += "[" + strconv.Quote(index) + "]"
+			}
+			resources = append(resources, newStateResource(r.Type, name, attributes["id"], attributes))
+		}
+	}
+	return resources, nil
+}
+
+func newStateResource(resourceType, name, id string, attributes map[string]string) Resource {
+	if attributes == nil {
+		attributes = map[string]string{}
+	}
+	return Resource{
+		ResourceName: name,
+		Provider:     strings.SplitN(resourceType, "_", 2)[0],
+		InstanceInfo: &terraform.InstanceInfo{
+			Type: resourceType,
+			Id:   resourceType + "." + name,
+		},
+		InstanceState: &terraform.InstanceState{
+			ID:         id,
+			Attributes: attributes,
+		},
+	}
+}
+
+// flattenJSONAttributes converts v4 attributes to the flatmap format refreshed resources use
+func flattenJSONAttributes(raw json.RawMessage) (map[string]string, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	attributes := map[string]interface{}{}
+	if err := dec.Decode(&attributes); err != nil {
+		return nil, err
+	}
+	flat := map[string]string{}
+	for k, v := range attributes {
+		flattenJSONValue(k, v, flat)
+	}
+	return flat, nil
+}
+
+func flattenJSONValue(key string, value interface{}, flat map[string]string) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		flat[key+".%"] = strconv.Itoa(len(v))
+		for k, e := range v {
+			flattenJSONValue(key+"."+k, e, flat)
+		}
+	case []interface{}:
+		flat[key+".#"] = strconv.Itoa(len(v))
+		for i, e := range v {
+			flattenJSONValue(key+"."+strconv.Itoa(i), e, flat)
+		}
+	case json.Number:
+		flat[key] = v.String()
+	case bool:
+		flat[key] = strconv.FormatBool(v)
+	case string:
+		flat[key] = v
+	}
+}
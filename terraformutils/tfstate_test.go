@@ -0,0 +1,201 @@
+// Copyright 2018 The Terraformer Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package terraformutils
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform/configs/configschema"
+	"github.com/hashicorp/terraform/providers"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestTfStateV4(t *testing.T) {
+	schema := &providers.GetSchemaResponse{
+		ResourceTypes: map[string]providers.Schema{
+			"type1": {
+				Version: 2,
+				Block: &configschema.Block{
+					Attributes: map[string]*configschema.Attribute{
+						"id":    {Type: cty.String, Computed: true},
+						"count": {Type: cty.Number, Optional: true},
+						"tags":  {Type: cty.Map(cty.String), Optional: true},
+					},
+				},
+			},
+		},
+	}
+	typed := prepare("ID1", "type1", map[string]string{
+		"count":      "3",
+		"tags.%":     "1",
+		"tags.Owner": "me",
+	}, map[string]interface{}{})
+	typed.Outputs = map[string]*terraform.OutputState{
+		"type1_tfer--name-002D-type1_id":   {Type: "string", Value: "ID1"},
+		"type1_tfer--name-002D-type1_size": {Type: "string", Value: 3},
+		"type1_tfer--name-002D-type1_tags": {Type: "map", Value: map[string]interface{}{"Owner": "me", "Enabled": true}},
+	}
+	untyped := prepare("ID2", "type2", map[string]string{"count": "3"}, map[string]interface{}{})
+
+	data, err := PrintTfState([]Resource{untyped, typed}, schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state := TfState{}
+	if err := json.Unmarshal(data, &state); err != nil {
+		t.Fatal(err)
+	}
+
+	if state.Version != 4 || state.Lineage == "" || len(state.Resources) != 2 {
+		t.Fatalf("unexpected state %s", string(data))
+	}
+	resource := state.Resources[0]
+	if resource.Mode != "managed" || resource.Type != "type1" || resource.Name != "tfer--name-002D-type1" ||
+		resource.Provider != `provider["registry.terraform.io/hashicorp/provider"]` {
+		t.Errorf("unexpected resource %v", resource)
+	}
+	if resource.Instances[0].SchemaVersion != 2 {
+		t.Errorf("unexpected schema version %d", resource.Instances[0].SchemaVersion)
+	}
+	attributes := map[string]interface{}{}
+	if err := json.Unmarshal(resource.Instances[0].AttributesRaw, &attributes); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(attributes, map[string]interface{}{
+		"id":    "ID1",
+		"count": float64(3),
+		"tags":  map[string]interface{}{"Owner": "me"},
+	}) {
+		t.Errorf("failed to decode attributes %v", attributes)
+	}
+	if !reflect.DeepEqual(state.Resources[1].Instances[0].AttributesFlat, map[string]string{"id": "ID2", "count": "3"}) {
+		t.Errorf("failed to keep flat attributes %v", state.Resources[1].Instances[0].AttributesFlat)
+	}
+	output := state.Outputs["type1_tfer--name-002D-type1_id"]
+	if string(output.Value) != `"ID1"` || string(output.Type) != `"string"` {
+		t.Errorf("unexpected output %s %s", output.Value, output.Type)
+	}
+	output = state.Outputs["type1_tfer--name-002D-type1_size"]
+	if string(output.Value) != `3` || string(output.Type) != `"number"` {
+		t.Errorf("unexpected output %s %s", output.Value, output.Type)
+	}
+	var value, valueType interface{}
+	output = state.Outputs["type1_tfer--name-002D-type1_tags"]
+	if err := json.Unmarshal(output.Value, &value); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(output.Type, &valueType); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(value, map[string]interface{}{"Owner": "me", "Enabled": true}) ||
+		!reflect.DeepEqual(valueType, []interface{}{"object", map[string]interface{}{"Owner": "string", "Enabled": "bool"}}) {
+		t.Errorf("unexpected output %s %s", output.Value, output.Type)
+	}
+}
+
+func TestReadTfStateResources(t *testing.T) {
+	schema := &providers.GetSchemaResponse{
+		ResourceTypes: map[string]providers.Schema{
+			"type1": {
+				Block: &configschema.Block{
+					Attributes: map[string]*configschema.Attribute{
+						"id":    {Type: cty.String, Computed: true},
+						"count": {Type: cty.Number, Optional: true},
+						"name":  {Type: cty.String, Optional: true},
+						"tags":  {Type: cty.Map(cty.String), Optional: true},
+					},
+				},
+			},
+		},
+	}
+	typed := prepare("ID1", "type1", map[string]string{
+		"count":      "3",
+		"tags.%":     "1",
+		"tags.Owner": "me",
+	}, map[string]interface{}{})
+	untyped := prepare("ID2", "type2", map[string]string{"count": "3"}, map[string]interface{}{})
+	data, err := PrintTfState([]Resource{typed, untyped}, schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resources, err := ReadTfStateResources(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resources) != 2 {
+		t.Fatalf("unexpected resources %v", resources)
+	}
+	if resources[0].Address() != "type1.tfer--name-002D-type1" || resources[0].InstanceState.ID != "ID1" {
+		t.Errorf("unexpected resource %s %s", resources[0].Address(), resources[0].InstanceState.ID)
+	}
+	if !reflect.DeepEqual(resources[0].InstanceState.Attributes, map[string]string{
+		"id":         "ID1",
+		"count":      "3",
+		"tags.%":     "1",
+		"tags.Owner": "me",
+	}) {
+		t.Errorf("unexpected attributes %v", resources[0].InstanceState.Attributes)
+	}
+	if !reflect.DeepEqual(resources[1].InstanceState.Attributes, untyped.InstanceState.Attributes) {
+		t.Errorf("unexpected flat attributes %v", resources[1].InstanceState.Attributes)
+	}
+
+	legacy := []byte(`{"version": 3, "modules": [{"path": ["root"], "resources": {
+		"type1.name": {"type": "type1", "primary": {"id": "ID1", "attributes": {"id": "ID1", "count": "3"}}},
+		"data.type1.name": {"type": "type1", "primary": {"id": "ID2", "attributes": {"id": "ID2"}}}
+	}}]}`)
+	resources, err = ReadTfStateResources(legacy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resources) != 1 || resources[0].Address() != "type1.name" || resources[0].InstanceState.Attributes["count"] != "3" {
+		t.Errorf("unexpected v3 resources %v", resources)
+	}
+
+	if _, err := ReadTfStateResources([]byte(`{"version": 2}`)); err == nil {
+		t.Error("expected unsupported version error")
+	}
+}
+
+func TestModulesTfState(t *testing.T) {
+	child := prepare("ID1", "type1", map[string]string{}, map[string]interface{}{})
+	child.Outputs = map[string]*terraform.OutputState{
+		"type1_tfer--name-002D-type1_id":   {Type: "string", Value: "ID1"},
+		"type1_tfer--name-002D-type1_size": {Type: "string", Value: 3},
+		"type1_tfer--name-002D-type1_tags": {Type: "map", Value: map[string]interface{}{"Owner": "me", "Enabled": true}},
+	}
+	data, err := PrintModulesTfState(map[string][]Resource{
+		"svc": {child},
+		"":    {prepare("ID2", "type2", map[string]string{}, map[string]interface{}{})},
+	}, &providers.GetSchemaResponse{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	state := TfState{}
+	if err := json.Unmarshal(data, &state); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(state.Resources) != 2 || state.Resources[0].Module != "" || state.Resources[1].Module != "module.svc" {
+		t.Fatalf("unexpected modules in state %s", string(data))
+	}
+	if len(state.Outputs) != 0 {
+		t.Errorf("unexpected child module outputs %v", state.Outputs)
+	}
+}
@@ -15,146 +15,205 @@
 package terraformutils
 
 import (
-	"bytes"
+	"context"
+	"errors"
+	"fmt"
 	"log"
+	"sort"
 	"sync"
 
 	"github.com/GoogleCloudPlatform/terraformer/terraformutils/providerwrapper"
-
-	"github.com/hashicorp/terraform/terraform"
 )
 
 type BaseResource struct {
 	Tags map[string]string `json:"tags,omitempty"`
 }
 
-func NewTfState(resources []Resource) *terraform.State {
-	tfstate := &terraform.State{
-		Version:   terraform.StateVersion,
-		TFVersion: terraform.VersionString(), //nolint
-		Serial:    1,
-	}
-	outputs := map[string]*terraform.OutputState{}
-	for _, r := range resources {
-		for k, v := range r.Outputs {
-			outputs[k] = v
-		}
-	}
-	tfstate.Modules = []*terraform.ModuleState{
-		{
-			Path:      []string{"root"},
-			Resources: map[string]*terraform.ResourceState{},
-			Outputs:   outputs,
-		},
-	}
-	for _, resource := range resources {
-		resourceState := &terraform.ResourceState{
-			Type:     resource.InstanceInfo.Type,
-			Primary:  resource.InstanceState,
-			Provider: "provider." + resource.Provider,
-		}
-		tfstate.Modules[0].Resources[resource.InstanceInfo.Type+"."+resource.ResourceName] = resourceState
-	}
-	return tfstate
-}
+// RefreshedHook receives each resource once refreshed, with its ID before refresh and the error when refresh failed
+type RefreshedHook func(resource *Resource, id string, err error)
 
-func PrintTfState(resources []Resource) ([]byte, error) {
-	state := NewTfState(resources)
-	var buf bytes.Buffer
-	err := terraform.WriteState(state, &buf)
-	return buf.Bytes(), err
-}
+// ServiceRefreshedHook receives the refreshed resources of a service once all of them were processed
+type ServiceRefreshedHook func(service string, resources []Resource)
+
+// DefaultParallelism is the number of refresh workers used unless the provider or --parallelism sets another
+const DefaultParallelism = 15
 
-func RefreshResources(resources []*Resource, provider *providerwrapper.ProviderWrapper, slowProcessingResources [][]*Resource) ([]*Resource, error) {
+func RefreshResources(ctx context.Context, resources []*Resource, provider *providerwrapper.ProviderWrapper, parallelism int, onRefreshed RefreshedHook) ([]*Resource, error) {
 	refreshedResources := []*Resource{}
 	input := make(chan *Resource, len(resources))
 	var wg sync.WaitGroup
-	poolSize := 15
+	if parallelism <= 0 {
+		parallelism = DefaultParallelism
+	}
 	for i := range resources {
 		wg.Add(1)
 		input <- resources[i]
 	}
 	close(input)
 
-	for i := 0; i < poolSize; i++ {
-		go RefreshResourceWorker(input, &wg, provider)
-	}
-
-	spInputs := []chan *Resource{}
-	for i, resourceGroup := range slowProcessingResources {
-		spInputs = append(spInputs, make(chan *Resource, len(resourceGroup)))
-		for j := range resourceGroup {
-			spInputs[i] <- resourceGroup[j]
+	// resources deleted since listing are dropped quietly
+	var notFound sync.Map
+	hook := func(r *Resource, id string, err error) {
+		if providerwrapper.ErrorClassOf(err) == providerwrapper.ErrorNotFound {
+			notFound.Store(r, true)
+		}
+		if onRefreshed != nil {
+			onRefreshed(r, id, err)
 		}
-		close(spInputs[i])
 	}
-
-	for i := 0; i < len(spInputs); i++ {
-		wg.Add(len(slowProcessingResources[i]))
-		go RefreshResourceWorker(spInputs[i], &wg, provider)
+	for i := 0; i < parallelism; i++ {
+		go RefreshResourceWorker(ctx, input, &wg, provider, hook)
 	}
 
 	wg.Wait()
 	for _, r := range resources {
 		if r.InstanceState != nil && r.InstanceState.ID != "" {
 			refreshedResources = append(refreshedResources, r)
-		} else {
+		} else if _, dropped := notFound.Load(r); !dropped {
 			log.Printf("ERROR: Unable to refresh resource %s", r.ResourceName)
 		}
 	}
-
-	for _, resourceGroup := range slowProcessingResources {
-		for i := range resourceGroup {
-			r := resourceGroup[i]
-			if r.InstanceState != nil && r.InstanceState.ID != "" {
-				refreshedResources = append(refreshedResources, r)
-			} else {
-				log.Printf("ERROR: Unable to refresh resource %s", r.ResourceName)
-			}
-		}
-	}
 	return refreshedResources, nil
 }
 
-func RefreshResourcesByProvider(providersMapping *ProvidersMapping, providerWrapper *providerwrapper.ProviderWrapper) error {
+// RefreshResourcesByProvider refreshes the resources of all services. When the context is done before the end,
+// services with resources left unrefreshed are removed, so only complete services are kept.
+func RefreshResourcesByProvider(ctx context.Context, providersMapping *ProvidersMapping, providerWrapper *providerwrapper.ProviderWrapper, parallelism int, onServiceRefreshed ServiceRefreshedHook) error {
 	allResources := providersMapping.ShuffleResources()
-	slowProcessingResources := make(map[ProviderGenerator][]*Resource)
-	regularResources := []*Resource{}
+	resourcesToRefresh := []*Resource{}
+	alreadyRefreshedResources := []*Resource{}
+	tracker := newServiceRefreshTracker(providersMapping, onServiceRefreshed)
 	for i := range allResources {
 		resource := allResources[i]
-		if resource.SlowQueryRequired {
-			provider := providersMapping.MatchProvider(resource)
-			if slowProcessingResources[provider] == nil {
-				slowProcessingResources[provider] = []*Resource{}
-			}
-			slowProcessingResources[provider] = append(slowProcessingResources[provider], resource)
-		} else {
-			regularResources = append(regularResources, resource)
+		if providersMapping.IsServiceRefreshed(providersMapping.MatchService(resource)) {
+			alreadyRefreshedResources = append(alreadyRefreshedResources, resource)
+			continue
 		}
+		tracker.add(resource)
+		resourcesToRefresh = append(resourcesToRefresh, resource)
 	}
 
-	var spResourcesList [][]*Resource
-	for p := range slowProcessingResources {
-		spResourcesList = append(spResourcesList, slowProcessingResources[p])
-	}
-
-	refreshedResources, err := RefreshResources(regularResources, providerWrapper, spResourcesList)
+	tracker.notifyEmptyServices()
+	refreshedResources, err := RefreshResources(ctx, resourcesToRefresh, providerWrapper, parallelism, tracker.done)
 	if err != nil {
 		return err
 	}
 
-	providersMapping.SetResources(refreshedResources)
+	if incomplete := tracker.incompleteServices(); len(incomplete) > 0 {
+		completeResources := []*Resource{}
+		for _, r := range refreshedResources {
+			if !tracker.incomplete[providersMapping.MatchService(r)] {
+				completeResources = append(completeResources, r)
+			}
+		}
+		refreshedResources = completeResources
+		for _, service := range incomplete {
+			log.Printf("refresh of service %s interrupted, skipping it", service)
+			providersMapping.GetServiceReport(service).SetError(fmt.Errorf("refresh interrupted: %w", ctx.Err()))
+		}
+		providersMapping.RemoveServices(incomplete)
+	}
+	providersMapping.SetResources(append(refreshedResources, alreadyRefreshedResources...))
 	return nil
 }
 
-func RefreshResourceWorker(input chan *Resource, wg *sync.WaitGroup, provider *providerwrapper.ProviderWrapper) {
+func RefreshResourceWorker(ctx context.Context, input chan *Resource, wg *sync.WaitGroup, provider *providerwrapper.ProviderWrapper, onRefreshed RefreshedHook) {
 	for r := range input {
-		log.Println("Refreshing state...", r.InstanceInfo.Id)
-		r.Refresh(provider)
+		id := r.InstanceState.ID
+		var err error
+		if err = ctx.Err(); err != nil {
+			r.InstanceState = nil
+		} else {
+			log.Println("Refreshing state...", r.InstanceInfo.Id)
+			err = r.Refresh(ctx, provider)
+		}
+		if err == nil && (r.InstanceState == nil || r.InstanceState.ID == "") {
+			err = errors.New("provider returned an empty state")
+		}
+		if onRefreshed != nil {
+			onRefreshed(r, id, err)
+		}
 		wg.Done()
 	}
 }
 
+// serviceRefreshTracker calls the hook for each service as soon as its last resource is refreshed
+type serviceRefreshTracker struct {
+	sync.Mutex
+	providersMapping   *ProvidersMapping
+	resources          map[string][]*Resource
+	remaining          map[string]int
+	incomplete         map[string]bool
+	onServiceRefreshed ServiceRefreshedHook
+}
+
+func newServiceRefreshTracker(providersMapping *ProvidersMapping, onServiceRefreshed ServiceRefreshedHook) *serviceRefreshTracker {
+	return &serviceRefreshTracker{
+		providersMapping:   providersMapping,
+		resources:          map[string][]*Resource{},
+		remaining:          map[string]int{},
+		incomplete:         map[string]bool{},
+		onServiceRefreshed: onServiceRefreshed,
+	}
+}
+
+func (t *serviceRefreshTracker) add(resource *Resource) {
+	service := t.providersMapping.MatchService(resource)
+	t.resources[service] = append(t.resources[service], resource)
+	t.remaining[service]++
+}
+
+func (t *serviceRefreshTracker) notifyEmptyServices() {
+	if t.onServiceRefreshed == nil {
+		return
+	}
+	for service := range t.providersMapping.Services {
+		if _, exist := t.remaining[service]; !exist && !t.providersMapping.IsServiceRefreshed(service) {
+			t.onServiceRefreshed(service, []Resource{})
+		}
+	}
+}
+
+func (t *serviceRefreshTracker) done(resource *Resource, id string, err error) {
+	service := t.providersMapping.MatchService(resource)
+	interrupted := errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+	switch {
+	case providerwrapper.ErrorClassOf(err) == providerwrapper.ErrorNotFound:
+		t.providersMapping.GetServiceReport(service).AddNotFound()
+	case err != nil && !interrupted:
+		t.providersMapping.GetServiceReport(service).AddFailure(resource.Address(), id, err)
+	}
+	t.Lock()
+	t.remaining[service]--
+	if interrupted {
+		t.incomplete[service] = true
+	}
+	finished := t.remaining[service] == 0 && !t.incomplete[service]
+	t.Unlock()
+	if !finished || t.onServiceRefreshed == nil {
+		return
+	}
+	refreshed := []Resource{}
+	for _, r := range t.resources[service] {
+		if r.InstanceState != nil && r.InstanceState.ID != "" {
+			refreshed = append(refreshed, *r)
+		}
+	}
+	t.onServiceRefreshed(service, refreshed)
+}
+
+// incompleteServices returns the services with resources whose refresh was interrupted
+func (t *serviceRefreshTracker) incompleteServices() []string {
+	t.Lock()
+	defer t.Unlock()
+	services := []string{}
+	for service := range t.incomplete {
+		services = append(services, service)
+	}
+	sort.Strings(services)
+	return services
+}
+
 func IgnoreKeys(resourcesTypes []string, p *providerwrapper.ProviderWrapper) map[string][]string {
 	readOnlyAttributes, err := p.GetReadOnlyAttributes(resourcesTypes)
 	if err != nil {
@@ -195,6 +254,9 @@ func ParseFilterValues(value string) []string {
 }
 
 func FilterCleanup(s *Service, isInitial bool) {
+	if isInitial {
+		s.Resources = s.Managed.Exclude(s.Resources)
+	}
 	if len(s.Filter) == 0 {
 		return
 	}
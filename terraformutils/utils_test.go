@@ -0,0 +1,138 @@
+// Copyright 2018 The Terraformer Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package terraformutils
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+type testProvider struct {
+	Provider
+}
+
+func (p *testProvider) InitService(serviceName string, verbose bool) error {
+	p.Service = &Service{Name: serviceName}
+	return nil
+}
+
+func (p *testProvider) GetProviderData(arg ...string) map[string]interface{} {
+	return map[string]interface{}{}
+}
+
+func (p *testProvider) GetResourceConnections() map[string]map[string][]string {
+	return map[string]map[string][]string{}
+}
+
+func prepareMapping(resourcesByService map[string][]Resource) *ProvidersMapping {
+	mapping := NewProvidersMapping(&testProvider{})
+	for service, resources := range resourcesByService {
+		provider := mapping.AddServiceToProvider(service)
+		_ = provider.InitService(service, false)
+		provider.GetService().SetResources(resources)
+	}
+	mapping.ProcessResources()
+	return mapping
+}
+
+func TestServiceRefreshTracker(t *testing.T) {
+	mapping := prepareMapping(map[string][]Resource{
+		"type1": {prepareNoAttrs("ID1", "type1"), prepareNoAttrs("ID2", "type1")},
+		"type2": {},
+	})
+	refreshed := map[string][]string{}
+	tracker := newServiceRefreshTracker(mapping, func(service string, resources []Resource) {
+		refreshed[service] = []string{}
+		for _, r := range resources {
+			refreshed[service] = append(refreshed[service], r.InstanceState.ID)
+		}
+	})
+	resources := mapping.ShuffleResources()
+	for _, r := range resources {
+		tracker.add(r)
+	}
+	tracker.notifyEmptyServices()
+	if !reflect.DeepEqual(refreshed, map[string][]string{"type2": {}}) {
+		t.Errorf("unexpected services %v", refreshed)
+	}
+
+	report := NewReport()
+	mapping.SetServiceReport("type1", report.Service("provider", "", "type1"))
+	tracker.done(resources[0], resources[0].InstanceState.ID, nil)
+	if _, exist := refreshed["type1"]; exist {
+		t.Error("service reported before all of its resources were refreshed")
+	}
+	id := resources[1].InstanceState.ID
+	resources[1].InstanceState = nil // failed to refresh
+	tracker.done(resources[1], id, errors.New("not found"))
+	if len(refreshed["type1"]) != 1 || refreshed["type1"][0] != resources[0].InstanceState.ID {
+		t.Errorf("unexpected refreshed resources %v", refreshed["type1"])
+	}
+	if !reflect.DeepEqual(report.Services[0].Failures, []ResourceFailure{{Address: resources[1].Address(), ID: id, Error: "not found"}}) {
+		t.Errorf("unexpected failures %v", report.Services[0].Failures)
+	}
+}
+
+func TestRefreshSkipsRefreshedServices(t *testing.T) {
+	mapping := prepareMapping(map[string][]Resource{
+		"type1": {prepareNoAttrs("ID1", "type1")},
+	})
+	mapping.MarkServiceRefreshed("type1")
+	hookCalls := 0
+	err := RefreshResourcesByProvider(context.Background(), mapping, nil, DefaultParallelism, func(service string, resources []Resource) {
+		hookCalls++
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hookCalls != 0 {
+		t.Errorf("hook called for a refreshed service")
+	}
+	ids := []string{}
+	for _, r := range mapping.GetResourcesByService()["type1"] {
+		ids = append(ids, r.InstanceState.ID)
+	}
+	sort.Strings(ids)
+	if !reflect.DeepEqual(ids, []string{"ID1"}) {
+		t.Errorf("refreshed resources were dropped %v", ids)
+	}
+}
+
+func TestRefreshInterrupted(t *testing.T) {
+	mapping := prepareMapping(map[string][]Resource{
+		"type1": {prepareNoAttrs("ID1", "type1")},
+		"type2": {prepareNoAttrs("ID2", "type2")},
+	})
+	mapping.MarkServiceRefreshed("type2")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	hookCalls := 0
+	err := RefreshResourcesByProvider(ctx, mapping, nil, DefaultParallelism, func(service string, resources []Resource) {
+		hookCalls++
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hookCalls != 0 {
+		t.Errorf("hook called for an interrupted service")
+	}
+	resources := mapping.GetResourcesByService()
+	if _, exist := resources["type1"]; exist || len(resources["type2"]) != 1 {
+		t.Errorf("expected only the finished service, got %v", resources)
+	}
+}
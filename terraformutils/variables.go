@@ -0,0 +1,298 @@
+// Copyright 2018 The Terraformer Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package terraformutils
+
+import (
+	"errors"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// KnownValuesProvider is implemented by providers able to name values found all over
+// the generated code, e.g. the AWS account ID or the GCP project
+type KnownValuesProvider interface {
+	GetKnownValues() map[string]string
+}
+
+// LiftedValues holds the literals replaced by references in resources, by variable or local name
+type LiftedValues struct {
+	Variables map[string]string
+	Locals    map[string]string
+}
+
+// minLiftedLength skips short literals, e.g. "80" or "true", they are rarely environment specific
+const minLiftedLength = 4
+
+var localNameUnsafeChars = regexp.MustCompile(`[^0-9A-Za-z_]+`)
+
+// segmentDelimiters bound the known values replaced inside longer strings, e.g. in ARNs and self links
+const segmentDelimiters = "/:.-"
+
+// LiftValues replaces known values with var. references, also as segments of longer strings such as
+// ARNs, and literals used more than threshold times with local. references
+func LiftValues(resources []Resource, known map[string]string, threshold int) LiftedValues {
+	lifted := LiftedValues{
+		Variables: map[string]string{},
+		Locals:    map[string]string{},
+	}
+	references := map[string]string{}
+	knownNames := make([]string, 0, len(known))
+	for name, value := range known {
+		if value == "" {
+			continue
+		}
+		knownNames = append(knownNames, name)
+	}
+	// replace longer values first, a region may be part of another known value
+	sort.Slice(knownNames, func(i, j int) bool {
+		if len(known[knownNames[i]]) != len(known[knownNames[j]]) {
+			return len(known[knownNames[i]]) > len(known[knownNames[j]])
+		}
+		return knownNames[i] < knownNames[j]
+	})
+	for _, name := range knownNames {
+		references[known[name]] = "var." + name
+	}
+
+	if threshold > 0 {
+		counts := map[string]int{}
+		keys := map[string]map[string]int{}
+		for _, r := range resources {
+			walkStrings("", r.Item, func(key, value string) string {
+				if isLiftable(value) && !containsKnownValue(value, known) {
+					counts[value]++
+					if keys[value] == nil {
+						keys[value] = map[string]int{}
+					}
+					keys[value][key]++
+				}
+				return value
+			})
+		}
+		values := []string{}
+		for value, count := range counts {
+			if _, exist := references[value]; !exist && count > threshold {
+				values = append(values, value)
+			}
+		}
+		sort.Strings(values)
+		names := map[string]bool{}
+		for _, name := range knownNames {
+			names[name] = true
+		}
+		for _, value := range values {
+			name := uniqueName(localName(keys[value]), names)
+			names[name] = true
+			references[value] = "local." + name
+			lifted.Locals[name] = value
+		}
+	}
+
+	used := map[string]bool{}
+	for i := range resources {
+		walkStrings("", resources[i].Item, func(key, value string) string {
+			if reference, exist := references[value]; exist {
+				used[reference] = true
+				return "${" + reference + "}"
+			}
+			for _, name := range knownNames {
+				if replaced, found := replaceLiteralSegment(value, known[name], "${var."+name+"}"); found {
+					used["var."+name] = true
+					value = replaced
+				}
+			}
+			return value
+		})
+	}
+	for _, name := range knownNames {
+		if used["var."+name] {
+			lifted.Variables[name] = known[name]
+		}
+	}
+	return lifted
+}
+
+// containsKnownValue leaves values holding known values to variables, e.g. an ARN with the account ID
+func containsKnownValue(value string, known map[string]string) bool {
+	for _, knownValue := range known {
+		if knownValue == "" {
+			continue
+		}
+		if _, found := replaceLiteralSegment(value, knownValue, ""); found {
+			return true
+		}
+	}
+	return false
+}
+
+// replaceLiteralSegment replaces segments like replaceSegment, leaving ${} interpolations untouched, e.g. a
+// reference to a resource named after the account ID
+func replaceLiteralSegment(value, old, replacement string) (string, bool) {
+	var b strings.Builder
+	found := false
+	for {
+		start := strings.Index(value, "${")
+		if start < 0 {
+			break
+		}
+		end := interpolationEnd(value, start)
+		if end < 0 {
+			break
+		}
+		literal, replaced := replaceSegment(value[:start], old, replacement)
+		found = found || replaced
+		b.WriteString(literal)
+		b.WriteString(value[start:end])
+		value = value[end:]
+	}
+	literal, replaced := replaceSegment(value, old, replacement)
+	b.WriteString(literal)
+	return b.String(), found || replaced
+}
+
+// interpolationEnd returns the index after the brace closing the interpolation at start, -1 if unclosed
+func interpolationEnd(value string, start int) int {
+	depth := 0
+	for i := start + 1; i < len(value); i++ {
+		switch value[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i + 1
+			}
+		}
+	}
+	return -1
+}
+
+// replaceSegment replaces the occurrences of old bounded by delimiters or the ends of value, so a
+// project "prod" is replaced in "projects/prod/zones" but not in "production-db"
+func replaceSegment(value, old, replacement string) (string, bool) {
+	var b strings.Builder
+	found := false
+	i := 0
+	for {
+		j := strings.Index(value[i:], old)
+		if j < 0 {
+			break
+		}
+		start, end := i+j, i+j+len(old)
+		if (start == 0 || strings.IndexByte(segmentDelimiters, value[start-1]) >= 0) &&
+			(end == len(value) || strings.IndexByte(segmentDelimiters, value[end]) >= 0) {
+			b.WriteString(value[i:start])
+			b.WriteString(replacement)
+			found = true
+			i = end
+			continue
+		}
+		b.WriteString(value[i : start+1])
+		i = start + 1
+	}
+	if !found {
+		return value, false
+	}
+	b.WriteString(value[i:])
+	return b.String(), true
+}
+
+func isLiftable(value string) bool {
+	if len(value) < minLiftedLength || strings.Contains(value, "\n") || strings.Contains(value, "${") {
+		return false
+	}
+	if _, err := strconv.ParseFloat(value, 64); err == nil {
+		return false
+	}
+	return value != "true" && value != "false"
+}
+
+// localName names a local after the attribute holding the value most often
+func localName(keys map[string]int) string {
+	best := ""
+	for key, count := range keys {
+		if best == "" || count > keys[best] || (count == keys[best] && key < best) {
+			best = key
+		}
+	}
+	parts := strings.Split(best, ".")
+	name := parts[len(parts)-1]
+	if _, err := strconv.Atoi(name); err == nil && len(parts) > 1 {
+		name = parts[len(parts)-2]
+	}
+	name = strings.Trim(localNameUnsafeChars.ReplaceAllString(name, "_"), "_")
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "value_" + name
+	}
+	return strings.ToLower(name)
+}
+
+func uniqueName(name string, names map[string]bool) string {
+	if !names[name] {
+		return name
+	}
+	for i := 2; ; i++ {
+		candidate := name + "_" + strconv.Itoa(i)
+		if !names[candidate] {
+			return candidate
+		}
+	}
+}
+
+// walkStrings calls replace with the flatmap like key of every string, storing its result in place
+func walkStrings(key string, value interface{}, replace func(key, value string) string) interface{} {
+	switch v := value.(type) {
+	case string:
+		return replace(key, v)
+	case map[string]interface{}:
+		for k, e := range v {
+			v[k] = walkStrings(strings.TrimPrefix(key+"."+k, "."), e, replace)
+		}
+	case []interface{}:
+		for i, e := range v {
+			v[i] = walkStrings(key+"."+strconv.Itoa(i), e, replace)
+		}
+	case []map[string]interface{}:
+		for i, e := range v {
+			walkStrings(key+"."+strconv.Itoa(i), e, replace)
+		}
+	}
+	return value
+}
+
+// PrintTfVars renders the values of lifted variables as a terraform.tfvars file
+func PrintTfVars(values map[string]string, format string) ([]byte, error) {
+	switch format {
+	case "hcl":
+		names := make([]string, 0, len(values))
+		for name := range values {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		f := hclwrite.NewEmptyFile()
+		for _, name := range names {
+			f.Body().SetAttributeValue(name, cty.StringVal(values[name]))
+		}
+		return hclwrite.Format(f.Bytes()), nil
+	case "json":
+		return jsonPrint(values)
+	}
+	return []byte{}, errors.New("error: unknown output format")
+}
@@ -0,0 +1,120 @@
+// Copyright 2018 The Terraformer Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package terraformutils
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLiftValues(t *testing.T) {
+	resources := []Resource{}
+	for _, id := range []string{"ID1", "ID2", "ID3"} {
+		resources = append(resources, prepare(id, "aws_subnet", map[string]string{}, map[string]interface{}{
+			"vpc_id":            "vpc-0a1b2c",
+			"cidr_block":        "10.0.0.0/24",
+			"availability_zone": "eu-west-1a",
+			"tags":              map[string]interface{}{"Environment": "production"},
+			"arns":              []interface{}{"arn:aws:iam::123456789012:role/" + id},
+			"map_public_ip":     "true",
+		}))
+	}
+	resources = append(resources, prepare("ID4", "aws_vpc", map[string]string{}, map[string]interface{}{
+		"owner_id":   "123456789012",
+		"cidr_block": "10.0.0.0/16",
+		"arn":        "arn:aws:ec2:eu-west-1:123456789012:vpc/vpc-0a1b2c",
+	}))
+
+	lifted := LiftValues(resources, map[string]string{
+		"account_id": "123456789012",
+		"region":     "eu-west-1",
+		"unused":     "us-east-2",
+	}, 2)
+
+	if !reflect.DeepEqual(lifted.Variables, map[string]string{"account_id": "123456789012", "region": "eu-west-1"}) {
+		t.Errorf("unexpected variables %v", lifted.Variables)
+	}
+	if !reflect.DeepEqual(lifted.Locals, map[string]string{
+		"availability_zone": "eu-west-1a",
+		"cidr_block":        "10.0.0.0/24",
+		"environment":       "production",
+		"vpc_id":            "vpc-0a1b2c",
+	}) {
+		t.Errorf("unexpected locals %v", lifted.Locals)
+	}
+	if !reflect.DeepEqual(resources[0].Item, map[string]interface{}{
+		"vpc_id":            "${local.vpc_id}",
+		"cidr_block":        "${local.cidr_block}",
+		"availability_zone": "${local.availability_zone}",
+		"tags":              map[string]interface{}{"Environment": "${local.environment}"},
+		"arns":              []interface{}{"arn:aws:iam::${var.account_id}:role/ID1"},
+		"map_public_ip":     "true",
+	}) {
+		t.Errorf("unexpected item %v", resources[0].Item)
+	}
+	if resources[3].Item["owner_id"] != "${var.account_id}" || resources[3].Item["cidr_block"] != "10.0.0.0/16" ||
+		resources[3].Item["arn"] != "arn:aws:ec2:${var.region}:${var.account_id}:vpc/vpc-0a1b2c" {
+		t.Errorf("unexpected item %v", resources[3].Item)
+	}
+}
+
+func TestLiftValuesSegments(t *testing.T) {
+	resources := []Resource{prepare("db", "google_sql_database_instance", map[string]string{}, map[string]interface{}{
+		"name":      "production-db",
+		"project":   "prod",
+		"self_link": "https://sqladmin.googleapis.com/sql/v1beta4/projects/prod/instances/production-db",
+		"labels":    map[string]interface{}{"env": "prod-eu", "owner": "products"},
+	})}
+
+	lifted := LiftValues(resources, map[string]string{"project": "prod"}, 0)
+
+	if !reflect.DeepEqual(lifted.Variables, map[string]string{"project": "prod"}) {
+		t.Errorf("unexpected variables %v", lifted.Variables)
+	}
+	if !reflect.DeepEqual(resources[0].Item, map[string]interface{}{
+		"name":      "production-db",
+		"project":   "${var.project}",
+		"self_link": "https://sqladmin.googleapis.com/sql/v1beta4/projects/${var.project}/instances/production-db",
+		"labels":    map[string]interface{}{"env": "${var.project}-eu", "owner": "products"},
+	}) {
+		t.Errorf("unexpected item %v", resources[0].Item)
+	}
+}
+
+func TestLiftValuesReferences(t *testing.T) {
+	resources := []Resource{prepare("trail", "aws_cloudtrail", map[string]string{}, map[string]interface{}{
+		"s3_bucket_name": "${aws_s3_bucket.tfer--logs-002D-123456789012.id}",
+		"kms_key_id":     "arn:aws:kms:eu-west-1:123456789012:key/${aws_kms_key.tfer--key-002D-123456789012.id}",
+	})}
+
+	LiftValues(resources, map[string]string{"account_id": "123456789012"}, 0)
+
+	if !reflect.DeepEqual(resources[0].Item, map[string]interface{}{
+		"s3_bucket_name": "${aws_s3_bucket.tfer--logs-002D-123456789012.id}",
+		"kms_key_id":     "arn:aws:kms:eu-west-1:${var.account_id}:key/${aws_kms_key.tfer--key-002D-123456789012.id}",
+	}) {
+		t.Errorf("references expected to be left unchanged, got %v", resources[0].Item)
+	}
+}
+
+func TestPrintTfVars(t *testing.T) {
+	data, err := PrintTfVars(map[string]string{"region": "eu-west-1", "account_id": "123456789012"}, "hcl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "account_id = \"123456789012\"\nregion     = \"eu-west-1\"\n" {
+		t.Errorf("unexpected tfvars %s", string(data))
+	}
+}